@@ -32,6 +32,7 @@ func main() {
 
 	agent := agent.NewMetrixAgent(agent.MetrixAgentOptions{
 		ServerAddr:         args.Addr,
+		GRPCAddr:           args.GRPCAddr,
 		PrivateKey:         args.PrivateKey,
 		PollInterval:       args.PollInterval,
 		ReportInterval:     args.ReportInterval,
@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/xantinium/metrix/internal/alerting"
 	"github.com/xantinium/metrix/internal/config"
+	"github.com/xantinium/metrix/internal/infrastructure/boltstorage"
 	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
 	"github.com/xantinium/metrix/internal/infrastructure/postgres"
 	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/recording"
 	"github.com/xantinium/metrix/internal/server"
 	"github.com/xantinium/metrix/internal/tools"
 )
@@ -28,6 +32,17 @@ func main() {
 	})
 
 	ctx := context.Background()
+
+	if len(os.Args) > 1 && os.Args[1] == migrateMode {
+		err := runMigrate(ctx, os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	args := config.ParseServerArgs()
 
 	logger.Init(args.IsDev)
@@ -44,7 +59,13 @@ func main() {
 		case err = <-server.Run():
 			if err != nil {
 				logger.Errorf("failed to run metrix server: %v", err)
-				return
+			}
+
+			// Останавливаем воркеры и записываем буфер
+			// отложенной записи до закрытия хранилища.
+			err = server.Stop()
+			if err != nil {
+				logger.Errorf("failed to gracefully stop metrix server: %v", err)
 			}
 
 			return
@@ -70,23 +91,93 @@ func (emptyDBChecker) Ping(_ context.Context) error {
 func getMetrixServer(ctx context.Context, args config.ServerArgs) (*server.MetrixServer, cleanUpFunc, error) {
 	builder := server.NewMetrixServerBuilder().
 		SetAddr(args.Addr).
+		SetStatsdAddr(args.StatsdAddr).
+		SetGraphiteAddr(args.GraphiteAddr).
+		SetGRPCAddr(args.GRPCAddr).
 		SetPrivateKey(args.PrivateKey).
-		SetStoreInterval(args.StoreInterval)
+		SetStoreInterval(args.StoreInterval).
+		SetRulesInterval(args.RulesInterval).
+		SetWriteBehind(args.WriteBehindSize, args.WriteBehindFlushSize, args.WriteBehindInterval).
+		SetMetricsTTL(args.MetricsTTL).
+		SetHistoryRetention(args.HistoryRetention).
+		SetSilenceRetention(args.SilenceRetention)
+
+	if args.RulesPath != "" {
+		rules, err := alerting.LoadRules(args.RulesPath)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	// Если строка подключения к БД отсутствует,
-	// используем in-memory хранилище и моковый DBChecker.
-	if args.DatabaseConnStr == "" {
-		memStorage, err := memstorage.NewMemStorage(args.StoragePath, args.RestoreStorage)
+		builder.SetAlertingRules(rules)
+	}
+
+	if args.RecordingRulesPath != "" {
+		rules, err := recording.LoadRules(args.RecordingRulesPath)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		builder.SetStorage(memStorage, new(emptyDBChecker))
+		builder.SetRecordingRules(rules)
+	}
+
+	if len(args.WebhookURLs) != 0 {
+		notifier, err := alerting.NewNotifier(alerting.NotifierOptions{
+			URLs:       args.WebhookURLs,
+			OutboxPath: args.WebhookOutboxPath,
+			PrivateKey: args.PrivateKey,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		builder.SetAlertsNotifier(notifier)
+	}
+
+	// Если строка подключения к БД отсутствует,
+	// используем встраиваемое хранилище.
+	if args.DatabaseConnStr == "" {
+		switch args.StorageEngine {
+		case config.StorageEngineMemory:
+			memStorage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+				Path:             args.StoragePath,
+				Restore:          args.RestoreStorage,
+				HistoryRetention: args.HistoryRetention,
+				Generations:      args.SnapshotGenerations,
+				// При синхронном сохранении (StoreInterval == 0) файл метрик
+				// перезаписывается после каждого обновления и журнал не нужен.
+				WAL: args.WALEnabled && args.StoreInterval != 0,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
 
-		return builder.Build(), memStorage.Destroy, nil
+			// In-memory хранилище используется с моковым DBChecker.
+			builder.SetStorage(memStorage, new(emptyDBChecker))
+
+			return builder.Build(), memStorage.Destroy, nil
+		case config.StorageEngineBolt:
+			// Файл метрик (-f) содержит JSON-снимок хранилища в памяти,
+			// поэтому bolt использует собственный файл.
+			boltStorage, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{
+				Path:             args.BoltPath,
+				HistoryRetention: args.HistoryRetention,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			builder.SetStorage(boltStorage, boltStorage)
+
+			return builder.Build(), boltStorage.Destroy, nil
+		default:
+			return nil, nil, fmt.Errorf("unknown storage engine %q", args.StorageEngine)
+		}
 	}
 
-	psqlClient, err := postgres.NewPostgresClient(ctx, args.DatabaseConnStr)
+	psqlClient, err := postgres.NewPostgresClient(ctx, postgres.PostgresClientOptions{
+		ConnStr:          args.DatabaseConnStr,
+		HistoryRetention: args.HistoryRetention,
+	})
 	if err != nil {
 		return nil, nil, err
 	}
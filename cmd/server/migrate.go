@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/xantinium/metrix/internal/config"
+	"github.com/xantinium/metrix/internal/infrastructure/postgres"
+)
+
+// migrateMode название режима миграций схемы БД:
+// metrix-server migrate [-d <dsn>] [-steps <n>] up|down|status.
+const migrateMode = "migrate"
+
+// runMigrate выполняет команду режима миграций схемы БД.
+func runMigrate(ctx context.Context, arguments []string) error {
+	args, err := config.ParseMigrateArgs(arguments)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := postgres.NewMigrator(args.DatabaseConnStr)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args.Command {
+	case config.MigrateUp:
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			fmt.Println("no migrations to apply")
+		}
+	case config.MigrateDown:
+		reverted, err := migrator.Down(ctx, args.Steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(reverted) == 0 {
+			fmt.Println("no migrations to revert")
+		}
+	case config.MigrateStatus:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return w.Flush()
+	}
+
+	return nil
+}
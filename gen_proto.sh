@@ -0,0 +1,7 @@
+#!/bin/bash
+
+# Генерация Go-кода для gRPC-сервиса метрик.
+protoc --proto_path=internal/proto \
+  --go_out=internal/proto --go_opt=paths=source_relative \
+  --go-grpc_out=internal/proto --go-grpc_opt=paths=source_relative \
+  metrix.proto
@@ -10,18 +10,28 @@ import (
 	"time"
 
 	"github.com/mailru/easyjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/xantinium/metrix/internal/infrastructure/runtimemetrics"
 	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
+	pb "github.com/xantinium/metrix/internal/proto"
 	"github.com/xantinium/metrix/internal/tools"
 )
 
 const agentWorkerPoolSize = 3
 
+// grpcRequestTimeout время ожидания ответа на один gRPC-запрос.
+const grpcRequestTimeout = 5 * time.Second
+
 // MetrixAgentOptions параметры агента метрик.
 type MetrixAgentOptions struct {
 	ServerAddr         string
+	GRPCAddr           string // если задан, метрики отправляются по gRPC
 	PrivateKey         string
 	PollInterval       int
 	ReportInterval     time.Duration
@@ -39,6 +49,16 @@ func NewMetrixAgent(opts MetrixAgentOptions) *MetrixAgent {
 		retrier:            tools.DefaulRetrier,
 	}
 
+	if opts.GRPCAddr != "" {
+		conn, err := grpc.NewClient(opts.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Errorf("failed to create grpc client, falling back to http: %v", err)
+		} else {
+			agent.grpcConn = conn
+			agent.grpcClient = pb.NewMetrixClient(conn)
+		}
+	}
+
 	agent.workerPool = NewMetrixAgentWorkerPool(MetrixAgentWorkerPoolOptions{
 		PoolSize:        agentWorkerPoolSize,
 		ReportInterval:  opts.ReportInterval,
@@ -54,6 +74,8 @@ type MetrixAgent struct {
 	workerPool         *MetrixAgentWorkerPool
 	metricsSource      *runtimemetrics.RuntimeMetricsSource
 	retrier            *tools.Retrier
+	grpcConn           *grpc.ClientConn
+	grpcClient         pb.MetrixClient
 	serverAddr         string
 	privateKey         string
 	isProfilingEnabled bool
@@ -67,6 +89,13 @@ func (agent *MetrixAgent) Run(ctx context.Context) {
 	if agent.isProfilingEnabled {
 		tools.RunProfilingServer()
 	}
+
+	if agent.grpcConn != nil {
+		go func() {
+			<-ctx.Done()
+			agent.grpcConn.Close()
+		}()
+	}
 }
 
 // UpdateMetrics обновляет метрики на сервере.
@@ -106,7 +135,13 @@ func (agent *MetrixAgent) updateMetricV2(metric models.MetricInfo) {
 }
 
 // updateMetricsBatch массововое обновление метрик через хендлеры второй версии.
+// Если агент настроен на работу по gRPC, метрики отправляются через него.
 func (agent *MetrixAgent) updateMetricsBatch(metrics []models.MetricInfo) {
+	if agent.grpcClient != nil {
+		agent.updateMetricsGRPC(metrics)
+		return
+	}
+
 	req := make(MetricsBatch, len(metrics))
 	for i, metric := range metrics {
 		value := metric.GaugeValue()
@@ -126,6 +161,58 @@ func (agent *MetrixAgent) updateMetricsBatch(metrics []models.MetricInfo) {
 	}
 }
 
+// updateMetricsGRPC массововое обновление метрик по gRPC.
+func (agent *MetrixAgent) updateMetricsGRPC(metrics []models.MetricInfo) {
+	req := &pb.UpdateMetricsRequest{
+		Metrics: make([]*pb.Metric, len(metrics)),
+	}
+	for i, metric := range metrics {
+		req.Metrics[i] = pb.FromMetricInfo(metric)
+	}
+
+	err := agent.sendGRPCRequest(req)
+	if err != nil {
+		logger.Errorf("failed to batch update metrics via grpc: %v", err)
+	}
+}
+
+func (agent *MetrixAgent) sendGRPCRequest(req *pb.UpdateMetricsRequest) error {
+	ctx := context.Background()
+
+	if agent.privateKey != "" {
+		hashedReq, err := pb.CalcHash(req, agent.privateKey)
+		if err != nil {
+			return err
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, pb.HashMetadataKey, hashedReq)
+	}
+
+	var err error
+	agent.retrier.Exec(func() bool {
+		callCtx, cancel := context.WithTimeout(ctx, grpcRequestTimeout)
+		defer cancel()
+
+		_, err = agent.grpcClient.UpdateMetrics(callCtx, req)
+		return shouldRetryGRPC(err)
+	})
+
+	return err
+}
+
+// shouldRetryGRPC сообщает, имеет ли смысл повторить gRPC-запрос,
+// завершившийся ошибкой err. Повторяются только запросы, не дошедшие
+// до сервера или не дождавшиеся ответа: остальные ошибки не исчезнут
+// при повторе.
+func shouldRetryGRPC(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
 func (agent *MetrixAgent) sendV2Request(url string, req easyjson.Marshaler) error {
 	var (
 		err      error
@@ -2,9 +2,24 @@
 package agent
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
+	pb "github.com/xantinium/metrix/internal/proto"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/server"
 )
 
 func TestMetrixAgent_GetUpdateMetricHandlerUrl(t *testing.T) {
@@ -36,3 +51,64 @@ func TestMetrixAgent_GetUpdateMetricHandlerUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestMetrixAgent_UpdateMetricsGRPC(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
+	require.NoError(t, err)
+
+	repo := metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{Storage: storage})
+
+	agent := NewMetrixAgent(MetrixAgentOptions{PrivateKey: "secret"})
+	agent.grpcClient = newTestGRPCClient(t, repo, "secret")
+
+	agent.updateMetricsBatch([]models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 123.45),
+		models.NewCounterMetric("PollCount", 7),
+	})
+
+	alloc, err := repo.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, 123.45, alloc)
+
+	pollCount, err := repo.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), pollCount)
+
+	t.Run("Отклонённый запрос не повторяется", func(t *testing.T) {
+		start := time.Now()
+		err := agent.sendGRPCRequest(&pb.UpdateMetricsRequest{
+			Metrics: []*pb.Metric{pb.FromMetricInfo(models.NewGaugeMetric("", 1))},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		// Первая повторная попытка выполняется не раньше чем через секунду.
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
+
+// newTestGRPCClient запускает gRPC-сервер метрик поверх repo
+// и возвращает подключённый к нему клиент.
+func newTestGRPCClient(t *testing.T, repo *metrics.MetricsRepository, privateKey string) pb.MetrixClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.NewGRPCServer("", repo, privateKey)
+	grpcServer.Serve(listener)
+	t.Cleanup(func() { grpcServer.Stop() })
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetrixClient(conn)
+}
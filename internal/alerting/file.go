@@ -0,0 +1,95 @@
+package alerting
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+type ruleItem struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Quantile  *float64          `json:"quantile,omitempty"`
+	Name      string            `json:"name"`
+	Metric    string            `json:"metric"`
+	Type      string            `json:"type"`
+	Op        string            `json:"op"`
+	For       string            `json:"for,omitempty"`
+	Threshold float64           `json:"threshold"`
+}
+
+//easyjson:json
+type rulesFile struct {
+	Rules []ruleItem `json:"rules"`
+}
+
+// LoadRules загружает правила оповещений из JSON-файла вида
+//
+//	{"rules": [{"name": "HighCPU", "metric": "CPUutilization1", "type": "gauge",
+//	  "labels": {"host": "a"}, "op": ">", "threshold": 90, "for": "1m"}]}
+//
+// Для метрик типа histogram и summary дополнительно указывается
+// сравниваемый квантиль, например "quantile": 0.99.
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %q: %v", path, err)
+	}
+
+	return rules, nil
+}
+
+// ParseRules парсит и проверяет правила оповещений в формате JSON.
+// Названия правил должны быть уникальны.
+func ParseRules(data []byte) ([]Rule, error) {
+	var file rulesFile
+
+	err := easyjson.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(file.Rules))
+	rules := make([]Rule, len(file.Rules))
+
+	for i, item := range file.Rules {
+		rule := Rule{
+			Labels:     item.Labels,
+			Quantile:   item.Quantile,
+			Name:       item.Name,
+			MetricID:   item.Metric,
+			MetricType: models.MetricType(item.Type),
+			Comparison: Comparison(item.Op),
+			Threshold:  item.Threshold,
+		}
+
+		if item.For != "" {
+			rule.For, err = time.ParseDuration(item.For)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid duration: %v", item.Name, err)
+			}
+		}
+
+		err = rule.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exists := names[rule.Name]; exists {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+
+		rules[i] = rule
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,94 @@
+package alerting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestParseRules(t *testing.T) {
+	quantile := 0.99
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []alerting.Rule
+		wantErr bool
+	}{
+		{
+			name: "Корректные правила",
+			data: `{"rules":[
+				{"name":"HighCPU","metric":"CPUutilization1","type":"gauge","labels":{"host":"a"},"op":">","threshold":90,"for":"1m"},
+				{"name":"SlowRequests","metric":"Latency","type":"summary","quantile":0.99,"op":">=","threshold":0.5}
+			]}`,
+			want: []alerting.Rule{
+				{
+					Labels:     models.Labels{"host": "a"},
+					Name:       "HighCPU",
+					MetricID:   "CPUutilization1",
+					MetricType: models.Gauge,
+					Comparison: alerting.Greater,
+					Threshold:  90,
+					For:        time.Minute,
+				},
+				{
+					Quantile:   &quantile,
+					Name:       "SlowRequests",
+					MetricID:   "Latency",
+					MetricType: models.Summary,
+					Comparison: alerting.GreaterOrEqual,
+					Threshold:  0.5,
+				},
+			},
+		},
+		{
+			name:    "Неизвестный оператор",
+			data:    `{"rules":[{"name":"A","metric":"Alloc","type":"gauge","op":"~","threshold":1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Неизвестный тип метрики",
+			data:    `{"rules":[{"name":"A","metric":"Alloc","type":"meter","op":">","threshold":1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Некорректная длительность",
+			data:    `{"rules":[{"name":"A","metric":"Alloc","type":"gauge","op":">","threshold":1,"for":"soon"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Квантиль не указан для гистограммы",
+			data:    `{"rules":[{"name":"A","metric":"Latency","type":"histogram","op":">","threshold":1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Квантиль указан для метрики типа Gauge",
+			data:    `{"rules":[{"name":"A","metric":"Alloc","type":"gauge","quantile":0.5,"op":">","threshold":1}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Повторяющееся название",
+			data: `{"rules":[
+				{"name":"A","metric":"Alloc","type":"gauge","op":">","threshold":1},
+				{"name":"A","metric":"PollCount","type":"counter","op":">","threshold":1}
+			]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := alerting.ParseRules([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package alerting
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// DefaultResolvedRetention время, в течение которого
+// разрешённые оповещения остаются в списке по умолчанию.
+const DefaultResolvedRetention = 15 * time.Minute
+
+// AlertState состояние оповещения.
+type AlertState string
+
+const (
+	// StatePending условие выполняется, но меньше времени, указанного в правиле.
+	StatePending AlertState = "pending"
+	// StateFiring условие выполняется дольше времени, указанного в правиле.
+	StateFiring AlertState = "firing"
+	// StateResolved условие оповещения в состоянии firing перестало выполняться.
+	StateResolved AlertState = "resolved"
+)
+
+// Alert оповещение, созданное правилом для конкретной метрики.
+type Alert struct {
+	// Labels метки метрики.
+	Labels models.Labels
+	// RuleName название правила.
+	RuleName string
+	// MetricID идентификатор метрики.
+	MetricID string
+	// State текущее состояние.
+	State AlertState
+	// Value последнее вычисленное значение метрики.
+	Value float64
+	// ActiveAt момент, начиная с которого выполняется условие.
+	ActiveAt time.Time
+	// FiredAt момент перехода в состояние firing.
+	FiredAt time.Time
+	// ResolvedAt момент перехода в состояние resolved.
+	ResolvedAt time.Time
+	// SilencedBy идентификаторы действующих заглушений оповещения.
+	SilencedBy []string
+}
+
+// key возвращает строковый ключ оповещения: правило и набор меток метрики.
+func (alert Alert) key() string {
+	return alert.RuleName + "{" + alert.Labels.Key() + "}"
+}
+
+// AlertsNotifier сущность, уведомляющая об изменении состояний оповещений.
+type AlertsNotifier interface {
+	Notify(alerts []Alert) error
+}
+
+// SilencesSource источник заглушений оповещений.
+type SilencesSource interface {
+	GetSilences(ctx context.Context) ([]models.Silence, error)
+}
+
+// MetricsSource источник текущих значений метрик.
+type MetricsSource interface {
+	GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error)
+}
+
+// ManagerOptions параметры менеджера оповещений.
+type ManagerOptions struct {
+	// Source источник значений метрик.
+	Source MetricsSource
+	// Silences источник заглушений.
+	// Если не указан, оповещения не заглушаются.
+	Silences SilencesSource
+	// Notifier сущность для уведомлений об изменении состояний.
+	// Если не указана, уведомления не отправляются.
+	Notifier AlertsNotifier
+	// Rules правила оповещений.
+	Rules []Rule
+	// ResolvedRetention время, в течение которого разрешённые
+	// оповещения остаются в списке. Если равно нулю,
+	// используется DefaultResolvedRetention.
+	ResolvedRetention time.Duration
+}
+
+// NewManager создаёт новый менеджер оповещений.
+func NewManager(opts ManagerOptions) *Manager {
+	resolvedRetention := opts.ResolvedRetention
+	if resolvedRetention == 0 {
+		resolvedRetention = DefaultResolvedRetention
+	}
+
+	return &Manager{
+		source:            opts.Source,
+		silences:          opts.Silences,
+		notifier:          opts.Notifier,
+		rules:             slices.Clone(opts.Rules),
+		alerts:            make(map[alertKey]Alert),
+		resolvedRetention: resolvedRetention,
+	}
+}
+
+// Manager структура, вычисляющая правила оповещений
+// и хранящая состояние созданных ими оповещений.
+type Manager struct {
+	source            MetricsSource
+	silences          SilencesSource
+	notifier          AlertsNotifier
+	alerts            map[alertKey]Alert
+	rules             []Rule
+	resolvedRetention time.Duration
+	mx                sync.RWMutex
+}
+
+// alertKey ключ оповещения: правило и набор меток метрики.
+type alertKey struct {
+	ruleName string
+	labels   string
+}
+
+// Rules возвращает правила оповещений.
+func (manager *Manager) Rules() []Rule {
+	return slices.Clone(manager.rules)
+}
+
+// Evaluate вычисляет все правила по текущим значениям метрик
+// и обновляет состояния оповещений на момент now.
+func (manager *Manager) Evaluate(ctx context.Context, now time.Time) error {
+	if len(manager.rules) == 0 {
+		return nil
+	}
+
+	metrics, err := manager.source.GetAllMetrics(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var silences []models.Silence
+	if manager.silences != nil {
+		silences, err = manager.silences.GetSilences(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	notifications := manager.evaluate(metrics, silences, now)
+	if manager.notifier == nil || len(notifications) == 0 {
+		return nil
+	}
+
+	return manager.notifier.Notify(notifications)
+}
+
+// evaluate обновляет состояния оповещений по значениям метрик
+// и отмечает заглушённые оповещения. Возвращает оповещения,
+// о которых необходимо уведомить: незаглушённые в состоянии firing
+// и перешедшие в состояние resolved. Повторные уведомления
+// отбрасываются на стороне AlertsNotifier.
+func (manager *Manager) evaluate(metrics []models.MetricInfo, silences []models.Silence, now time.Time) []Alert {
+	manager.mx.Lock()
+	defer manager.mx.Unlock()
+
+	var notifications []Alert
+	active := make(map[alertKey]struct{})
+
+	for _, rule := range manager.rules {
+		for _, metric := range metrics {
+			if !rule.Matches(metric) {
+				continue
+			}
+
+			value, ok := rule.Evaluate(metric)
+			if !ok {
+				continue
+			}
+
+			key := alertKey{ruleName: rule.Name, labels: metric.Labels().Key()}
+			active[key] = struct{}{}
+
+			alert, exists := manager.alerts[key]
+			if !exists || alert.State == StateResolved {
+				alert = Alert{
+					Labels:   metric.Labels(),
+					RuleName: rule.Name,
+					MetricID: metric.ID(),
+					State:    StatePending,
+					ActiveAt: now,
+				}
+			}
+
+			alert.Value = value
+			if alert.State == StatePending && now.Sub(alert.ActiveAt) >= rule.For {
+				alert.State = StateFiring
+				alert.FiredAt = now
+			}
+
+			manager.alerts[key] = alert
+		}
+	}
+
+	// Оповещения, условие которых перестало выполняться
+	// (в том числе из-за пропажи метрики).
+	for key, alert := range manager.alerts {
+		if _, ok := active[key]; ok {
+			continue
+		}
+
+		switch alert.State {
+		case StatePending:
+			delete(manager.alerts, key)
+		case StateFiring:
+			alert.State = StateResolved
+			alert.ResolvedAt = now
+			manager.alerts[key] = alert
+			notifications = append(notifications, alert)
+		case StateResolved:
+			if now.Sub(alert.ResolvedAt) >= manager.resolvedRetention {
+				delete(manager.alerts, key)
+			}
+		}
+	}
+
+	for key, alert := range manager.alerts {
+		alert.SilencedBy = silencedBy(alert, silences, now)
+		manager.alerts[key] = alert
+
+		if alert.State == StateFiring && len(alert.SilencedBy) == 0 {
+			notifications = append(notifications, alert)
+		}
+	}
+
+	return notifications
+}
+
+// silencedBy возвращает идентификаторы действующих в момент now
+// заглушений, относящихся к оповещению.
+func silencedBy(alert Alert, silences []models.Silence, now time.Time) []string {
+	var ids []string
+	for _, silence := range silences {
+		if silence.IsActive(now) && silence.Matches(alert.RuleName, alert.MetricID, alert.Labels) {
+			ids = append(ids, silence.ID)
+		}
+	}
+
+	return ids
+}
+
+// Alerts возвращает все отслеживаемые оповещения,
+// упорядоченные по названию правила и меткам.
+func (manager *Manager) Alerts() []Alert {
+	manager.mx.RLock()
+	defer manager.mx.RUnlock()
+
+	keys := make([]alertKey, 0, len(manager.alerts))
+	for key := range manager.alerts {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b alertKey) int {
+		if c := strings.Compare(a.ruleName, b.ruleName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.labels, b.labels)
+	})
+
+	alerts := make([]Alert, len(keys))
+	for i, key := range keys {
+		alerts[i] = manager.alerts[key]
+	}
+
+	return alerts
+}
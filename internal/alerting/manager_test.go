@@ -0,0 +1,196 @@
+package alerting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// staticSource источник метрик с заранее заданными значениями.
+type staticSource struct {
+	metrics []models.MetricInfo
+}
+
+func (source *staticSource) GetAllMetrics(_ context.Context, filter models.Labels) ([]models.MetricInfo, error) {
+	var metrics []models.MetricInfo
+	for _, metric := range source.metrics {
+		if metric.Labels().Matches(filter) {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics, nil
+}
+
+func TestManager_Evaluate(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(1000, 0)
+
+	hostA := models.Labels{"host": "a"}
+
+	source := new(staticSource)
+	manager := alerting.NewManager(alerting.ManagerOptions{
+		Source: source,
+		Rules: []alerting.Rule{{
+			Labels:     models.Labels{"service": "api"},
+			Name:       "HighCPU",
+			MetricID:   "CPUutilization1",
+			MetricType: models.Gauge,
+			Comparison: alerting.Greater,
+			Threshold:  90,
+			For:        time.Minute,
+		}},
+		ResolvedRetention: 5 * time.Minute,
+	})
+
+	setCPU := func(a, b float64) {
+		source.metrics = []models.MetricInfo{
+			models.NewGaugeMetric("CPUutilization1", a).WithLabels(models.Labels{"host": "a", "service": "api"}),
+			models.NewGaugeMetric("CPUutilization1", b).WithLabels(models.Labels{"host": "b", "service": "api"}),
+			// Не соответствует меткам правила.
+			models.NewGaugeMetric("CPUutilization1", 100).WithLabels(hostA),
+		}
+	}
+
+	states := func() map[string]alerting.AlertState {
+		result := make(map[string]alerting.AlertState)
+		for _, alert := range manager.Alerts() {
+			result[alert.Labels["host"]] = alert.State
+		}
+		return result
+	}
+
+	// Условие выполняется только для host=a: оповещение ожидает.
+	setCPU(95, 10)
+	require.NoError(t, manager.Evaluate(ctx, start))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StatePending}, states())
+
+	// Условие для host=b выполнилось, но перестало выполняться
+	// раньше истечения времени: оповещение удаляется без срабатывания.
+	setCPU(96, 99)
+	require.NoError(t, manager.Evaluate(ctx, start.Add(30*time.Second)))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StatePending, "b": alerting.StatePending}, states())
+
+	setCPU(97, 10)
+	require.NoError(t, manager.Evaluate(ctx, start.Add(time.Minute)))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StateFiring}, states())
+
+	alerts := manager.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, "HighCPU", alerts[0].RuleName)
+	require.Equal(t, "CPUutilization1", alerts[0].MetricID)
+	require.Equal(t, float64(97), alerts[0].Value)
+	require.Equal(t, start, alerts[0].ActiveAt)
+	require.Equal(t, start.Add(time.Minute), alerts[0].FiredAt)
+
+	// Условие перестало выполняться: оповещение разрешено.
+	setCPU(50, 10)
+	require.NoError(t, manager.Evaluate(ctx, start.Add(2*time.Minute)))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StateResolved}, states())
+	require.Equal(t, start.Add(2*time.Minute), manager.Alerts()[0].ResolvedAt)
+
+	// Разрешённое оповещение удаляется после истечения времени хранения.
+	require.NoError(t, manager.Evaluate(ctx, start.Add(7*time.Minute)))
+	require.Empty(t, manager.Alerts())
+
+	// Пропажа метрики равнозначна невыполнению условия.
+	setCPU(99, 10)
+	require.NoError(t, manager.Evaluate(ctx, start.Add(8*time.Minute)))
+	require.NoError(t, manager.Evaluate(ctx, start.Add(9*time.Minute)))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StateFiring}, states())
+
+	source.metrics = nil
+	require.NoError(t, manager.Evaluate(ctx, start.Add(10*time.Minute)))
+	require.Equal(t, map[string]alerting.AlertState{"a": alerting.StateResolved}, states())
+}
+
+func TestManager_EvaluateQuantile(t *testing.T) {
+	ctx := context.Background()
+	quantile := 0.99
+
+	value, err := models.NewHistogramValue([]float64{0.1, 0.5, 1}, []int64{90, 5, 5, 0}, 10)
+	require.NoError(t, err)
+
+	manager := alerting.NewManager(alerting.ManagerOptions{
+		Source: &staticSource{metrics: []models.MetricInfo{models.NewHistogramMetric("Latency", value)}},
+		Rules: []alerting.Rule{{
+			Quantile:   &quantile,
+			Name:       "SlowRequests",
+			MetricID:   "Latency",
+			MetricType: models.Histogram,
+			Comparison: alerting.Greater,
+			Threshold:  0.5,
+		}},
+	})
+
+	// Без времени ожидания оповещение срабатывает сразу.
+	require.NoError(t, manager.Evaluate(ctx, time.Now()))
+
+	alerts := manager.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, alerting.StateFiring, alerts[0].State)
+	require.InDelta(t, 0.9, alerts[0].Value, 1e-9)
+}
+
+// staticSilences источник заранее заданных заглушений.
+type staticSilences []models.Silence
+
+func (silences staticSilences) GetSilences(_ context.Context) ([]models.Silence, error) {
+	return silences, nil
+}
+
+// recordingNotifier сохраняет переданные оповещения.
+type recordingNotifier struct {
+	alerts []alerting.Alert
+}
+
+func (notifier *recordingNotifier) Notify(alerts []alerting.Alert) error {
+	notifier.alerts = append(notifier.alerts, alerts...)
+	return nil
+}
+
+func TestManager_Silences(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(1000, 0)
+
+	notifier := new(recordingNotifier)
+	manager := alerting.NewManager(alerting.ManagerOptions{
+		Source: &staticSource{metrics: []models.MetricInfo{
+			models.NewGaugeMetric("CPUutilization1", 95),
+			models.NewGaugeMetric("CPUutilization2", 95),
+		}},
+		Silences: staticSilences{{
+			StartsAt: start,
+			EndsAt:   start.Add(time.Minute),
+			Matchers: []models.LabelMatcher{{Name: models.MetricNameLabel, Value: "CPUutilization1", Type: models.MatchEqual}},
+			ID:       "deploy",
+		}},
+		Notifier: notifier,
+		Rules: []alerting.Rule{
+			{Name: "HighCPU1", MetricID: "CPUutilization1", MetricType: models.Gauge, Comparison: alerting.Greater, Threshold: 90},
+			{Name: "HighCPU2", MetricID: "CPUutilization2", MetricType: models.Gauge, Comparison: alerting.Greater, Threshold: 90},
+		},
+	})
+
+	// Заглушённое оповещение отслеживается, но уведомление о нём не отправляется.
+	require.NoError(t, manager.Evaluate(ctx, start))
+
+	alerts := manager.Alerts()
+	require.Len(t, alerts, 2)
+	require.Equal(t, []string{"deploy"}, alerts[0].SilencedBy)
+	require.Empty(t, alerts[1].SilencedBy)
+
+	require.Len(t, notifier.alerts, 1)
+	require.Equal(t, "HighCPU2", notifier.alerts[0].RuleName)
+
+	// После окончания заглушения уведомление отправляется.
+	notifier.alerts = nil
+	require.NoError(t, manager.Evaluate(ctx, start.Add(time.Minute)))
+	require.Empty(t, manager.Alerts()[0].SilencedBy)
+	require.Len(t, notifier.alerts, 2)
+}
@@ -0,0 +1,443 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// DefaultFlushInterval интервал между повторными попытками
+// доставки недоставленных уведомлений по умолчанию.
+const DefaultFlushInterval = 30 * time.Second
+
+// Notification тело уведомления об изменении состояния оповещения.
+//
+//easyjson:json
+type Notification struct {
+	Labels     map[string]string `json:"labels,omitempty"`
+	FiredAt    *time.Time        `json:"firedAt,omitempty"`
+	ResolvedAt *time.Time        `json:"resolvedAt,omitempty"`
+	Rule       string            `json:"rule"`
+	ID         string            `json:"id"`
+	State      AlertState        `json:"state"`
+	ActiveAt   time.Time         `json:"activeAt"`
+	Value      float64           `json:"value"`
+}
+
+// newNotification создаёт уведомление об оповещении.
+func newNotification(alert Alert) Notification {
+	notification := Notification{
+		Labels:   alert.Labels,
+		Rule:     alert.RuleName,
+		ID:       alert.MetricID,
+		State:    alert.State,
+		ActiveAt: alert.ActiveAt.UTC(),
+		Value:    alert.Value,
+	}
+
+	if !alert.FiredAt.IsZero() {
+		firedAt := alert.FiredAt.UTC()
+		notification.FiredAt = &firedAt
+	}
+	if !alert.ResolvedAt.IsZero() {
+		resolvedAt := alert.ResolvedAt.UTC()
+		notification.ResolvedAt = &resolvedAt
+	}
+
+	return notification
+}
+
+// outboxItem уведомление, ожидающее доставки.
+type outboxItem struct {
+	// URLs адреса, на которые уведомление ещё не доставлено.
+	URLs         []string     `json:"urls"`
+	Notification Notification `json:"notification"`
+}
+
+// outboxFile содержимое файла с недоставленными уведомлениями.
+//
+//easyjson:json
+type outboxFile struct {
+	// States последние отправленные состояния оповещений.
+	States map[string]AlertState `json:"states,omitempty"`
+	Items  []outboxItem          `json:"items,omitempty"`
+}
+
+// NotifierOptions параметры сущности для отправки уведомлений.
+type NotifierOptions struct {
+	// Client HTTP-клиент. Если не указан, используется http.DefaultClient.
+	Client *http.Client
+	// Retrier ретраер для отправки запросов.
+	// Если не указан, используется tools.DefaulRetrier.
+	Retrier *tools.Retrier
+	// URLs адреса вебхуков.
+	URLs []string
+	// OutboxPath путь до файла с недоставленными уведомлениями.
+	// Если пустой, уведомления хранятся только в памяти.
+	OutboxPath string
+	// PrivateKey ключ для подписи тела запроса.
+	// Если пустой, запросы не подписываются.
+	PrivateKey string
+	// FlushInterval интервал между повторными попытками доставки.
+	// Если равен нулю, используется DefaultFlushInterval.
+	FlushInterval time.Duration
+}
+
+// NewNotifier создаёт новую сущность для отправки уведомлений
+// и восстанавливает недоставленные уведомления из OutboxPath.
+func NewNotifier(opts NotifierOptions) (*Notifier, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	retrier := opts.Retrier
+	if retrier == nil {
+		retrier = tools.DefaulRetrier
+	}
+
+	flushInterval := opts.FlushInterval
+	if flushInterval == 0 {
+		flushInterval = DefaultFlushInterval
+	}
+
+	notifier := &Notifier{
+		client:        client,
+		retrier:       retrier,
+		urls:          slices.Clone(opts.URLs),
+		outboxPath:    opts.OutboxPath,
+		privateKey:    opts.PrivateKey,
+		flushInterval: flushInterval,
+		states:        make(map[string]AlertState),
+		flushChan:     make(chan struct{}, 1),
+		stopFunc:      func() {},
+	}
+
+	err := notifier.restore()
+	if err != nil {
+		return nil, err
+	}
+
+	return notifier, nil
+}
+
+// Notifier структура, отправляющая подписанные уведомления
+// об изменении состояний оповещений на вебхуки.
+//
+// Уведомления сначала сохраняются в outbox на диске и удаляются
+// из него только после доставки на все вебхуки, поэтому переживают
+// перезапуск сервера. Повторные уведомления о том же состоянии
+// оповещения (например, срабатывание после перезапуска) не отправляются.
+type Notifier struct {
+	client        *http.Client
+	retrier       *tools.Retrier
+	states        map[string]AlertState
+	flushChan     chan struct{}
+	stopFunc      context.CancelFunc
+	outboxPath    string
+	privateKey    string
+	urls          []string
+	items         []outboxItem
+	wg            sync.WaitGroup
+	flushInterval time.Duration
+	mx            sync.Mutex
+	flushMx       sync.Mutex
+}
+
+// Notify ставит в очередь уведомления об изменении состояний оповещений.
+// Уведомления отправляются только о переходах в состояния firing и resolved,
+// причём о разрешении — только если ранее было отправлено срабатывание.
+func (notifier *Notifier) Notify(alerts []Alert) error {
+	notifier.mx.Lock()
+	defer notifier.mx.Unlock()
+
+	var changed bool
+	for _, alert := range alerts {
+		key := alert.key()
+
+		switch alert.State {
+		case StateFiring:
+			if notifier.states[key] == StateFiring {
+				continue
+			}
+			notifier.states[key] = StateFiring
+		case StateResolved:
+			if notifier.states[key] != StateFiring {
+				continue
+			}
+			// Для разрешённых оповещений состояние не храним.
+			delete(notifier.states, key)
+		default:
+			continue
+		}
+		changed = true
+
+		if len(notifier.urls) == 0 {
+			continue
+		}
+
+		notifier.items = append(notifier.items, outboxItem{
+			URLs:         slices.Clone(notifier.urls),
+			Notification: newNotification(alert),
+		})
+	}
+
+	if !changed {
+		return nil
+	}
+
+	err := notifier.save()
+	if err != nil {
+		return err
+	}
+
+	if len(notifier.items) != 0 {
+		select {
+		case notifier.flushChan <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Pending возвращает количество недоставленных уведомлений.
+func (notifier *Notifier) Pending() int {
+	notifier.mx.Lock()
+	defer notifier.mx.Unlock()
+
+	return len(notifier.items)
+}
+
+// Run запускает доставку уведомлений.
+func (notifier *Notifier) Run() {
+	var ctx context.Context
+	ctx, notifier.stopFunc = context.WithCancel(context.TODO())
+
+	t := time.NewTicker(notifier.flushInterval)
+
+	notifier.wg.Add(1)
+	go func() {
+		defer notifier.wg.Done()
+
+		// Доставляем уведомления, оставшиеся с прошлого запуска.
+		notifier.Flush(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				notifier.log("stopping...")
+				t.Stop()
+				return
+			case <-notifier.flushChan:
+				notifier.Flush(ctx)
+			case <-t.C:
+				notifier.Flush(ctx)
+			}
+		}
+	}()
+}
+
+// Stop прекращает доставку уведомлений.
+// Недоставленные уведомления остаются в outbox.
+func (notifier *Notifier) Stop() {
+	notifier.stopFunc()
+	notifier.wg.Wait()
+}
+
+// Flush пытается доставить все уведомления из outbox.
+// Если доставка на вебхук не удалась, последующие уведомления
+// на него не отправляются до следующей попытки, чтобы
+// сохранить порядок уведомлений.
+func (notifier *Notifier) Flush(ctx context.Context) {
+	notifier.flushMx.Lock()
+	defer notifier.flushMx.Unlock()
+
+	notifier.mx.Lock()
+	items := slices.Clone(notifier.items)
+	notifier.mx.Unlock()
+
+	failed := make(map[string]struct{})
+
+	for _, item := range items {
+		body, err := easyjson.Marshal(item.Notification)
+		if err != nil {
+			notifier.log(fmt.Sprintf("failed to marshal notification: %v", err))
+			continue
+		}
+
+		// Адреса изменяются в markDelivered, поэтому итерируемся по копии.
+		for _, url := range slices.Clone(item.URLs) {
+			if _, ok := failed[url]; ok {
+				continue
+			}
+
+			if notifier.deliver(ctx, url, body) {
+				notifier.markDelivered(item.Notification, url)
+			} else {
+				failed[url] = struct{}{}
+			}
+		}
+	}
+}
+
+// deliver отправляет уведомление на вебхук с ретраями.
+func (notifier *Notifier) deliver(ctx context.Context, url string, body []byte) bool {
+	var delivered bool
+
+	notifier.retrier.Exec(func() bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		err := notifier.send(ctx, url, body)
+		if err != nil {
+			notifier.log(fmt.Sprintf("failed to send notification to %q: %v", url, err))
+			return true
+		}
+
+		delivered = true
+		return false
+	})
+
+	return delivered
+}
+
+// send выполняет запрос на вебхук.
+func (notifier *Notifier) send(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if notifier.privateKey != "" {
+		var hash string
+		hash, err = tools.CalcSHA256(body, notifier.privateKey)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(tools.HashSHA256, hash)
+	}
+
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// markDelivered отмечает уведомление доставленным на вебхук
+// и удаляет его из outbox после доставки на все вебхуки.
+func (notifier *Notifier) markDelivered(notification Notification, url string) {
+	notifier.mx.Lock()
+	defer notifier.mx.Unlock()
+
+	for i := range notifier.items {
+		item := &notifier.items[i]
+		if !notificationsEqual(item.Notification, notification) {
+			continue
+		}
+
+		item.URLs = slices.DeleteFunc(item.URLs, func(u string) bool { return u == url })
+		if len(item.URLs) == 0 {
+			notifier.items = slices.Delete(notifier.items, i, i+1)
+		}
+		break
+	}
+
+	err := notifier.save()
+	if err != nil {
+		notifier.log(fmt.Sprintf("failed to save outbox: %v", err))
+	}
+}
+
+// restore восстанавливает outbox из файла. Адреса вебхуков,
+// отсутствующие в текущей конфигурации, отбрасываются.
+func (notifier *Notifier) restore() error {
+	if notifier.outboxPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(notifier.outboxPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var file outboxFile
+	err = easyjson.Unmarshal(data, &file)
+	if err != nil {
+		return fmt.Errorf("failed to parse outbox file %q: %v", notifier.outboxPath, err)
+	}
+
+	for key, state := range file.States {
+		notifier.states[key] = state
+	}
+
+	for _, item := range file.Items {
+		item.URLs = slices.DeleteFunc(item.URLs, func(u string) bool {
+			return !slices.Contains(notifier.urls, u)
+		})
+		if len(item.URLs) != 0 {
+			notifier.items = append(notifier.items, item)
+		}
+	}
+
+	return nil
+}
+
+// save атомарно сохраняет outbox в файл.
+// Вызывается под блокировкой notifier.mx.
+func (notifier *Notifier) save() error {
+	if notifier.outboxPath == "" {
+		return nil
+	}
+
+	data, err := easyjson.Marshal(outboxFile{
+		States: notifier.states,
+		Items:  notifier.items,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tools.WriteFileAtomic(notifier.outboxPath, data)
+}
+
+// log логирует события сущности.
+func (notifier *Notifier) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: "alerting-notifier",
+		},
+	)
+}
+
+// notificationsEqual сравнивает уведомления по оповещению и состоянию.
+func notificationsEqual(a, b Notification) bool {
+	return a.Rule == b.Rule && a.State == b.State && a.ActiveAt.Equal(b.ActiveAt) &&
+		models.Labels(a.Labels).Key() == models.Labels(b.Labels).Key()
+}
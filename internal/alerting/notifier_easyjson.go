@@ -0,0 +1,428 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package alerting
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting(in *jlexer.Lexer, out *outboxFile) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "states":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.States = make(map[string]AlertState)
+				} else {
+					out.States = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 AlertState
+					v1 = AlertState(in.String())
+					(out.States)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+				out.Items = nil
+			} else {
+				in.Delim('[')
+				if out.Items == nil {
+					if !in.IsDelim(']') {
+						out.Items = make([]outboxItem, 0, 0)
+					} else {
+						out.Items = []outboxItem{}
+					}
+				} else {
+					out.Items = (out.Items)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 outboxItem
+					easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting1(in, &v2)
+					out.Items = append(out.Items, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting(out *jwriter.Writer, in outboxFile) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.States) != 0 {
+		const prefix string = ",\"states\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v3First := true
+			for v3Name, v3Value := range in.States {
+				if v3First {
+					v3First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v3Name))
+				out.RawByte(':')
+				out.String(string(v3Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.Items) != 0 {
+		const prefix string = ",\"items\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v4, v5 := range in.Items {
+				if v4 > 0 {
+					out.RawByte(',')
+				}
+				easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting1(out, v5)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v outboxFile) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v outboxFile) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *outboxFile) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *outboxFile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting(l, v)
+}
+func easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting1(in *jlexer.Lexer, out *outboxItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "urls":
+			if in.IsNull() {
+				in.Skip()
+				out.URLs = nil
+			} else {
+				in.Delim('[')
+				if out.URLs == nil {
+					if !in.IsDelim(']') {
+						out.URLs = make([]string, 0, 4)
+					} else {
+						out.URLs = []string{}
+					}
+				} else {
+					out.URLs = (out.URLs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 string
+					v6 = string(in.String())
+					out.URLs = append(out.URLs, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "notification":
+			(out.Notification).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting1(out *jwriter.Writer, in outboxItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"urls\":"
+		out.RawString(prefix[1:])
+		if in.URLs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v7, v8 := range in.URLs {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v8))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"notification\":"
+		out.RawString(prefix)
+		(in.Notification).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+func easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting2(in *jlexer.Lexer, out *Notification) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v9 string
+					v9 = string(in.String())
+					(out.Labels)[key] = v9
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "firedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.FiredAt = nil
+			} else {
+				if out.FiredAt == nil {
+					out.FiredAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FiredAt).UnmarshalJSON(data))
+				}
+			}
+		case "resolvedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.ResolvedAt = nil
+			} else {
+				if out.ResolvedAt == nil {
+					out.ResolvedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ResolvedAt).UnmarshalJSON(data))
+				}
+			}
+		case "rule":
+			out.Rule = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "state":
+			out.State = AlertState(in.String())
+		case "activeAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ActiveAt).UnmarshalJSON(data))
+			}
+		case "value":
+			out.Value = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting2(out *jwriter.Writer, in Notification) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v10First := true
+			for v10Name, v10Value := range in.Labels {
+				if v10First {
+					v10First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v10Name))
+				out.RawByte(':')
+				out.String(string(v10Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.FiredAt != nil {
+		const prefix string = ",\"firedAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.FiredAt).MarshalJSON())
+	}
+	if in.ResolvedAt != nil {
+		const prefix string = ",\"resolvedAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ResolvedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"rule\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Rule))
+	}
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"activeAt\":"
+		out.RawString(prefix)
+		out.Raw((in.ActiveAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Value))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Notification) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Notification) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson12f28a7eEncodeGithubComXantiniumMetrixInternalAlerting2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Notification) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Notification) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson12f28a7eDecodeGithubComXantiniumMetrixInternalAlerting2(l, v)
+}
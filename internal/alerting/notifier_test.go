@@ -0,0 +1,236 @@
+package alerting_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+const testPrivateKey = "secret"
+
+// webhookReceiver тестовый приёмник уведомлений.
+type webhookReceiver struct {
+	notifications []alerting.Notification
+	hashes        []string
+	failing       atomic.Bool
+	// failures количество ближайших запросов, завершающихся ошибкой.
+	failures atomic.Int32
+	mx       sync.Mutex
+}
+
+func (receiver *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if receiver.failing.Load() || receiver.failures.Add(-1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var notification alerting.Notification
+	err = easyjson.Unmarshal(body, &notification)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	expectedHash, _ := tools.CalcSHA256(body, testPrivateKey)
+
+	receiver.mx.Lock()
+	receiver.notifications = append(receiver.notifications, notification)
+	receiver.hashes = append(receiver.hashes, r.Header.Get(tools.HashSHA256))
+	receiver.mx.Unlock()
+
+	if expectedHash != r.Header.Get(tools.HashSHA256) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (receiver *webhookReceiver) States() []alerting.AlertState {
+	receiver.mx.Lock()
+	defer receiver.mx.Unlock()
+
+	states := make([]alerting.AlertState, len(receiver.notifications))
+	for i, notification := range receiver.notifications {
+		states[i] = notification.State
+	}
+
+	return states
+}
+
+func newTestNotifier(t *testing.T, url, outboxPath string) *alerting.Notifier {
+	notifier, err := alerting.NewNotifier(alerting.NotifierOptions{
+		Retrier:    tools.NewRetrier(time.Millisecond),
+		URLs:       []string{url},
+		OutboxPath: outboxPath,
+		PrivateKey: testPrivateKey,
+	})
+	require.NoError(t, err)
+
+	return notifier
+}
+
+func TestNotifier(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	start := time.Unix(1000, 0)
+
+	receiver := new(webhookReceiver)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	source := &staticSource{metrics: []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization1", 95).WithLabels(models.Labels{"host": "a"}),
+	}}
+	rules := []alerting.Rule{{
+		Name:       "HighCPU",
+		MetricID:   "CPUutilization1",
+		MetricType: models.Gauge,
+		Comparison: alerting.Greater,
+		Threshold:  90,
+		For:        time.Minute,
+	}}
+
+	notifier := newTestNotifier(t, srv.URL, filepath.Join(t.TempDir(), "outbox.json"))
+	manager := alerting.NewManager(alerting.ManagerOptions{
+		Source:   source,
+		Notifier: notifier,
+		Rules:    rules,
+	})
+
+	// Переход в состояние pending не отправляется.
+	require.NoError(t, manager.Evaluate(ctx, start))
+	require.Equal(t, 0, notifier.Pending())
+
+	// Повторные вычисления в состоянии firing не дублируют уведомление.
+	require.NoError(t, manager.Evaluate(ctx, start.Add(time.Minute)))
+	require.NoError(t, manager.Evaluate(ctx, start.Add(2*time.Minute)))
+	require.Equal(t, 1, notifier.Pending())
+
+	notifier.Flush(ctx)
+	require.Equal(t, 0, notifier.Pending())
+	require.Equal(t, []alerting.AlertState{alerting.StateFiring}, receiver.States())
+
+	notification := receiver.notifications[0]
+	require.Equal(t, "HighCPU", notification.Rule)
+	require.Equal(t, "CPUutilization1", notification.ID)
+	require.Equal(t, map[string]string{"host": "a"}, notification.Labels)
+	require.Equal(t, float64(95), notification.Value)
+	require.True(t, start.Equal(notification.ActiveAt))
+	require.NotNil(t, notification.FiredAt)
+	require.Nil(t, notification.ResolvedAt)
+	require.NotEmpty(t, receiver.hashes[0])
+
+	source.metrics = nil
+	require.NoError(t, manager.Evaluate(ctx, start.Add(3*time.Minute)))
+	notifier.Flush(ctx)
+	require.Equal(t, []alerting.AlertState{alerting.StateFiring, alerting.StateResolved}, receiver.States())
+}
+
+func TestNotifier_Outbox(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	outboxPath := filepath.Join(t.TempDir(), "outbox.json")
+
+	receiver := new(webhookReceiver)
+	receiver.failing.Store(true)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	alert := alerting.Alert{
+		Labels:   models.Labels{"host": "a"},
+		RuleName: "HighCPU",
+		MetricID: "CPUutilization1",
+		State:    alerting.StateFiring,
+		Value:    95,
+		ActiveAt: time.Unix(1000, 0),
+		FiredAt:  time.Unix(1060, 0),
+	}
+
+	// Вебхук недоступен: уведомление остаётся в outbox.
+	notifier := newTestNotifier(t, srv.URL, outboxPath)
+	require.NoError(t, notifier.Notify([]alerting.Alert{alert}))
+	notifier.Flush(ctx)
+	require.Equal(t, 1, notifier.Pending())
+	require.Empty(t, receiver.States())
+
+	// После перезапуска уведомление восстанавливается из outbox,
+	// а повторное срабатывание того же оповещения не дублируется.
+	receiver.failing.Store(false)
+	notifier = newTestNotifier(t, srv.URL, outboxPath)
+	require.Equal(t, 1, notifier.Pending())
+
+	alert.ActiveAt = time.Unix(2000, 0)
+	alert.FiredAt = time.Unix(2060, 0)
+	require.NoError(t, notifier.Notify([]alerting.Alert{alert}))
+	require.Equal(t, 1, notifier.Pending())
+
+	notifier.Run()
+	require.Eventually(t, func() bool {
+		return notifier.Pending() == 0
+	}, time.Second, 10*time.Millisecond)
+	notifier.Stop()
+
+	require.Equal(t, []alerting.AlertState{alerting.StateFiring}, receiver.States())
+	require.True(t, time.Unix(1000, 0).Equal(receiver.notifications[0].ActiveAt))
+
+	// Доставленные уведомления не восстанавливаются повторно.
+	notifier = newTestNotifier(t, srv.URL, outboxPath)
+	require.Equal(t, 0, notifier.Pending())
+}
+
+func TestNotifier_RetryDeliversOnce(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+
+	receiver := new(webhookReceiver)
+	receiver.failures.Store(1)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	notifier, err := alerting.NewNotifier(alerting.NotifierOptions{
+		Retrier:    tools.NewRetrier(time.Millisecond, time.Millisecond, time.Millisecond),
+		URLs:       []string{srv.URL},
+		OutboxPath: filepath.Join(t.TempDir(), "outbox.json"),
+		PrivateKey: testPrivateKey,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify([]alerting.Alert{{
+		RuleName: "HighCPU",
+		MetricID: "CPUutilization1",
+		State:    alerting.StateFiring,
+		Value:    95,
+		ActiveAt: time.Unix(1000, 0),
+		FiredAt:  time.Unix(1060, 0),
+	}}))
+
+	// Первая попытка завершается ошибкой, вторая успешна:
+	// после успешной доставки ретраи прекращаются.
+	notifier.Flush(ctx)
+	require.Equal(t, 0, notifier.Pending())
+	require.Equal(t, []alerting.AlertState{alerting.StateFiring}, receiver.States())
+}
@@ -0,0 +1,170 @@
+// Package alerting содержит движок правил оповещений:
+// правила с пороговыми условиями периодически вычисляются
+// по текущим значениям метрик, а каждое оповещение проходит
+// через состояния pending, firing и resolved.
+package alerting
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// Comparison оператор сравнения значения метрики с порогом.
+type Comparison string
+
+const (
+	// Greater значение больше порога.
+	Greater Comparison = ">"
+	// GreaterOrEqual значение больше или равно порогу.
+	GreaterOrEqual Comparison = ">="
+	// Less значение меньше порога.
+	Less Comparison = "<"
+	// LessOrEqual значение меньше или равно порогу.
+	LessOrEqual Comparison = "<="
+	// Equal значение равно порогу.
+	Equal Comparison = "=="
+	// NotEqual значение не равно порогу.
+	NotEqual Comparison = "!="
+)
+
+// ParseComparison парсит строку в оператор сравнения.
+func ParseComparison(maybeComparison string) (Comparison, error) {
+	switch comparison := Comparison(maybeComparison); comparison {
+	case Greater, GreaterOrEqual, Less, LessOrEqual, Equal, NotEqual:
+		return comparison, nil
+	default:
+		return "", fmt.Errorf("unknown comparison %q", maybeComparison)
+	}
+}
+
+// Compare проверяет выполнение условия value <оператор> threshold.
+func (comparison Comparison) Compare(value, threshold float64) bool {
+	switch comparison {
+	case Greater:
+		return value > threshold
+	case GreaterOrEqual:
+		return value >= threshold
+	case Less:
+		return value < threshold
+	case LessOrEqual:
+		return value <= threshold
+	case Equal:
+		return value == threshold
+	case NotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
+
+// Rule правило оповещения.
+//
+// Правило вычисляется отдельно для каждой метрики с идентификатором MetricID
+// и типом MetricType, метки которой содержат Labels. Оповещение переходит
+// в состояние firing, если условие выполняется не менее For.
+type Rule struct {
+	// Labels метки, которые должна содержать метрика.
+	Labels models.Labels
+	// Quantile квантиль, сравниваемый с порогом.
+	// Обязателен для метрик типа Histogram и Summary.
+	Quantile *float64
+	// Name уникальное название правила.
+	Name string
+	// MetricID идентификатор метрики.
+	MetricID string
+	// MetricType тип метрики.
+	MetricType models.MetricType
+	// Comparison оператор сравнения с порогом.
+	Comparison Comparison
+	// Threshold пороговое значение.
+	Threshold float64
+	// For время, в течение которого должно выполняться условие.
+	For time.Duration
+}
+
+// Validate проверяет корректность правила.
+func (rule Rule) Validate() error {
+	if rule.Name == "" {
+		return fmt.Errorf("rule name cannot be empty")
+	}
+
+	err := models.ValidateMetricID(rule.MetricID)
+	if err != nil {
+		return fmt.Errorf("rule %q: %v", rule.Name, err)
+	}
+
+	_, err = models.ParseStringAsMetricType(string(rule.MetricType))
+	if err != nil {
+		return fmt.Errorf("rule %q: %v", rule.Name, err)
+	}
+
+	_, err = ParseComparison(string(rule.Comparison))
+	if err != nil {
+		return fmt.Errorf("rule %q: %v", rule.Name, err)
+	}
+
+	if math.IsNaN(rule.Threshold) {
+		return fmt.Errorf("rule %q: threshold cannot be NaN", rule.Name)
+	}
+
+	if rule.For < 0 {
+		return fmt.Errorf("rule %q: duration cannot be negative", rule.Name)
+	}
+
+	switch rule.MetricType {
+	case models.Histogram, models.Summary:
+		if rule.Quantile == nil {
+			return fmt.Errorf("rule %q: quantile is required for %s metrics", rule.Name, rule.MetricType)
+		}
+		if q := *rule.Quantile; math.IsNaN(q) || q < 0 || q > 1 {
+			return fmt.Errorf("rule %q: quantile must be in range [0, 1]", rule.Name)
+		}
+	default:
+		if rule.Quantile != nil {
+			return fmt.Errorf("rule %q: quantile can be used only for histogram and summary metrics", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// Matches проверяет, относится ли метрика к правилу.
+func (rule Rule) Matches(metric models.MetricInfo) bool {
+	return metric.ID() == rule.MetricID && metric.Type() == rule.MetricType && metric.Labels().Matches(rule.Labels)
+}
+
+// Value возвращает значение метрики, сравниваемое с порогом.
+// Если значение не определено (например, у пустой гистограммы),
+// возвращается false.
+func (rule Rule) Value(metric models.MetricInfo) (float64, bool) {
+	var value float64
+
+	switch metric.Type() {
+	case models.Gauge:
+		value = metric.GaugeValue()
+	case models.Counter:
+		value = float64(metric.CounterValue())
+	case models.Histogram:
+		value = metric.HistogramValue().Quantile(*rule.Quantile)
+	case models.Summary:
+		value = metric.SummaryValue().Quantile(*rule.Quantile)
+	default:
+		return 0, false
+	}
+
+	return value, !math.IsNaN(value)
+}
+
+// Evaluate проверяет выполнение условия правила для метрики.
+// Возвращает значение метрики и результат проверки.
+func (rule Rule) Evaluate(metric models.MetricInfo) (float64, bool) {
+	value, ok := rule.Value(metric)
+	if !ok {
+		return value, false
+	}
+
+	return value, rule.Comparison.Compare(value, rule.Threshold)
+}
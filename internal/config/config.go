@@ -13,16 +13,43 @@ import (
 	"github.com/xantinium/metrix/internal/tools"
 )
 
+// Встраиваемые хранилища метрик, используемые,
+// если строка подключения к БД не задана.
+const (
+	// StorageEngineMemory хранилище в памяти с сохранением в файл.
+	StorageEngineMemory = "memory"
+	// StorageEngineBolt хранилище в файле на диске на основе bbolt.
+	StorageEngineBolt = "bolt"
+)
+
 // ServerArgs структура, описывающая аргументы сервера.
 type ServerArgs struct {
-	Addr               string
-	StoragePath        string
-	PrivateKey         string
-	DatabaseConnStr    string
-	StoreInterval      time.Duration
-	IsDev              bool
-	IsProfilingEnabled bool
-	RestoreStorage     bool
+	Addr                 string
+	StatsdAddr           string
+	GraphiteAddr         string
+	GRPCAddr             string
+	StorageEngine        string
+	StoragePath          string
+	BoltPath             string
+	PrivateKey           string
+	DatabaseConnStr      string
+	RulesPath            string
+	RecordingRulesPath   string
+	WebhookOutboxPath    string
+	WebhookURLs          []string
+	SnapshotGenerations  int
+	WriteBehindSize      int
+	WriteBehindFlushSize int
+	StoreInterval        time.Duration
+	HistoryRetention     time.Duration
+	RulesInterval        time.Duration
+	WriteBehindInterval  time.Duration
+	MetricsTTL           time.Duration
+	SilenceRetention     time.Duration
+	IsDev                bool
+	IsProfilingEnabled   bool
+	RestoreStorage       bool
+	WALEnabled           bool
 }
 
 // ParseServerArgs парсит агрументы командной строки в ServerArgs.
@@ -33,24 +60,76 @@ func ParseServerArgs() ServerArgs {
 	isProfilingEnabled := flag.Bool("profile", false, "is profiling via pprof enabled")
 	privateKey := flag.String("k", "", "key for hash funcs")
 	storeInterval := flag.Int("i", 300, "interval (in seconds) of writing metrics into file")
+	storageEngine := flag.String("storage", StorageEngineMemory, "embedded metrics storage used without database: memory or bolt")
 	storagePath := flag.String("f", "./metrix.db", "path to file for metrics writing")
+	boltPath := flag.String("bolt-path", "./metrix.bolt", "path to bolt database file used by bolt storage engine")
 	restoreStorage := flag.Bool("r", true, "read metrics from file on start")
+	snapshotGenerations := flag.Int("snapshot-generations", 2, "number of previous metrics file versions kept for recovery")
+	walEnabled := flag.Bool("wal", true, "write every metrics update to write-ahead log next to metrics file")
 	databaseConnStr := flag.String("d", "", "connection string for postgresql")
+	historyRetention := flag.Int("history", 0, "retention (in seconds) of metrics history (0 = history disabled)")
+	statsdAddr := flag.String("statsd", "", "UDP address for receiving metrics in StatsD format (empty = disabled)")
+	graphiteAddr := flag.String("graphite", "", "TCP address for receiving metrics in Graphite plaintext format (empty = disabled)")
+	grpcAddr := flag.String("grpc", "", "address of metrix gRPC server in form <host:port> (empty = disabled)")
+	rulesPath := flag.String("rules", "", "path to JSON file with alerting rules (empty = alerting disabled)")
+	recordingRulesPath := flag.String("recording-rules", "", "path to JSON file with recording rules (empty = recording disabled)")
+	rulesInterval := flag.Int("rules-interval", 15, "interval (in seconds) of recording and alerting rules evaluation")
+	webhookURLs := flag.String("webhooks", "", "comma-separated URLs for alert notifications (empty = notifications disabled)")
+	writeBehindSize := flag.Int("write-behind-size", 0, "max number of series buffered before writing to storage (0 = buffering disabled)")
+	writeBehindFlushSize := flag.Int("write-behind-flush-size", 1000, "number of buffered series that triggers writing to storage")
+	writeBehindInterval := flag.Int("write-behind-interval", 1, "interval (in seconds) of writing buffered updates to storage")
+	metricsTTL := flag.Int("metrics-ttl", 0, "time (in seconds) after which metrics without updates are deleted (0 = metrics never expire)")
+	silenceRetention := flag.Int("silence-retention", 432000, "time (in seconds) after which expired silences are deleted (0 = expired silences are kept)")
+	webhookOutboxPath := flag.String("webhooks-outbox", "./metrix-outbox.json", "path to file for undelivered alert notifications")
 
 	flag.Parse()
 
 	args := ServerArgs{
 		Addr:               address.String(),
+		StatsdAddr:         *statsdAddr,
+		GraphiteAddr:       *graphiteAddr,
+		GRPCAddr:           *grpcAddr,
+		RulesPath:          *rulesPath,
+		RecordingRulesPath: *recordingRulesPath,
+		WebhookOutboxPath:  *webhookOutboxPath,
+		WebhookURLs:        parseList(*webhookURLs),
 		IsDev:              *isDev,
 		PrivateKey:         *privateKey,
+		StorageEngine:      *storageEngine,
 		StoragePath:        *storagePath,
+		BoltPath:           *boltPath,
 		RestoreStorage:     *restoreStorage,
+		WALEnabled:         *walEnabled,
 		DatabaseConnStr:    *databaseConnStr,
 		IsProfilingEnabled: *isProfilingEnabled,
 	}
 	if storeInterval != nil {
 		args.StoreInterval = time.Duration(*storeInterval) * time.Second
 	}
+	if historyRetention != nil && *historyRetention >= 0 {
+		args.HistoryRetention = time.Duration(*historyRetention) * time.Second
+	}
+	if snapshotGenerations != nil && *snapshotGenerations >= 0 {
+		args.SnapshotGenerations = *snapshotGenerations
+	}
+	if rulesInterval != nil && *rulesInterval > 0 {
+		args.RulesInterval = time.Duration(*rulesInterval) * time.Second
+	}
+	if writeBehindSize != nil && *writeBehindSize >= 0 {
+		args.WriteBehindSize = *writeBehindSize
+	}
+	if writeBehindFlushSize != nil && *writeBehindFlushSize > 0 {
+		args.WriteBehindFlushSize = *writeBehindFlushSize
+	}
+	if writeBehindInterval != nil && *writeBehindInterval > 0 {
+		args.WriteBehindInterval = time.Duration(*writeBehindInterval) * time.Second
+	}
+	if metricsTTL != nil && *metricsTTL >= 0 {
+		args.MetricsTTL = time.Duration(*metricsTTL) * time.Second
+	}
+	if silenceRetention != nil && *silenceRetention >= 0 {
+		args.SilenceRetention = time.Duration(*silenceRetention) * time.Second
+	}
 
 	envArgs := parseServerArgsFromEnv()
 
@@ -63,43 +142,207 @@ func ParseServerArgs() ServerArgs {
 	if envArgs.StoreInterval.Exists && envArgs.StoreInterval.Value >= 0 {
 		args.StoreInterval = time.Duration(envArgs.StoreInterval.Value) * time.Second
 	}
+	if envArgs.StorageEngine.Exists {
+		args.StorageEngine = envArgs.StorageEngine.Value
+	}
 	if envArgs.StoragePath.Exists && fs.ValidPath(envArgs.StoragePath.Value) {
 		args.StoragePath = envArgs.StoragePath.Value
 	}
+	if envArgs.BoltPath.Exists && fs.ValidPath(envArgs.BoltPath.Value) {
+		args.BoltPath = envArgs.BoltPath.Value
+	}
 	if envArgs.RestoreStorage.Exists {
 		args.RestoreStorage = envArgs.RestoreStorage.Value
 	}
+	if envArgs.SnapshotGenerations.Exists && envArgs.SnapshotGenerations.Value >= 0 {
+		args.SnapshotGenerations = envArgs.SnapshotGenerations.Value
+	}
+	if envArgs.WALEnabled.Exists {
+		args.WALEnabled = envArgs.WALEnabled.Value
+	}
 	if envArgs.DatabaseConnStr.Exists {
 		args.DatabaseConnStr = envArgs.DatabaseConnStr.Value
 	}
+	if envArgs.HistoryRetention.Exists && envArgs.HistoryRetention.Value >= 0 {
+		args.HistoryRetention = time.Duration(envArgs.HistoryRetention.Value) * time.Second
+	}
+	if envArgs.StatsdAddr.Exists {
+		args.StatsdAddr = envArgs.StatsdAddr.Value
+	}
+	if envArgs.GraphiteAddr.Exists {
+		args.GraphiteAddr = envArgs.GraphiteAddr.Value
+	}
+	if envArgs.GRPCAddr.Exists {
+		args.GRPCAddr = envArgs.GRPCAddr.Value
+	}
+	if envArgs.RulesPath.Exists {
+		args.RulesPath = envArgs.RulesPath.Value
+	}
+	if envArgs.RecordingRulesPath.Exists {
+		args.RecordingRulesPath = envArgs.RecordingRulesPath.Value
+	}
+	if envArgs.RulesInterval.Exists && envArgs.RulesInterval.Value > 0 {
+		args.RulesInterval = time.Duration(envArgs.RulesInterval.Value) * time.Second
+	}
+	if envArgs.WriteBehindSize.Exists && envArgs.WriteBehindSize.Value >= 0 {
+		args.WriteBehindSize = envArgs.WriteBehindSize.Value
+	}
+	if envArgs.WriteBehindFlushSize.Exists && envArgs.WriteBehindFlushSize.Value > 0 {
+		args.WriteBehindFlushSize = envArgs.WriteBehindFlushSize.Value
+	}
+	if envArgs.WriteBehindInterval.Exists && envArgs.WriteBehindInterval.Value > 0 {
+		args.WriteBehindInterval = time.Duration(envArgs.WriteBehindInterval.Value) * time.Second
+	}
+	if envArgs.MetricsTTL.Exists && envArgs.MetricsTTL.Value >= 0 {
+		args.MetricsTTL = time.Duration(envArgs.MetricsTTL.Value) * time.Second
+	}
+	if envArgs.SilenceRetention.Exists && envArgs.SilenceRetention.Value >= 0 {
+		args.SilenceRetention = time.Duration(envArgs.SilenceRetention.Value) * time.Second
+	}
+	if envArgs.WebhookURLs.Exists {
+		args.WebhookURLs = parseList(envArgs.WebhookURLs.Value)
+	}
+	if envArgs.WebhookOutboxPath.Exists && fs.ValidPath(envArgs.WebhookOutboxPath.Value) {
+		args.WebhookOutboxPath = envArgs.WebhookOutboxPath.Value
+	}
 
 	return args
 }
 
+// parseList парсит список значений, разделённых запятыми.
+// Пустые значения отбрасываются.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 type serverEnvArgs struct {
-	Addr            tools.StrEnvVar
-	PrivateKey      tools.StrEnvVar
-	StoragePath     tools.StrEnvVar
-	DatabaseConnStr tools.StrEnvVar
-	StoreInterval   tools.IntEnvVar
-	RestoreStorage  tools.BoolEnvVar
+	Addr                 tools.StrEnvVar
+	StatsdAddr           tools.StrEnvVar
+	GraphiteAddr         tools.StrEnvVar
+	GRPCAddr             tools.StrEnvVar
+	PrivateKey           tools.StrEnvVar
+	StorageEngine        tools.StrEnvVar
+	StoragePath          tools.StrEnvVar
+	BoltPath             tools.StrEnvVar
+	DatabaseConnStr      tools.StrEnvVar
+	RulesPath            tools.StrEnvVar
+	RecordingRulesPath   tools.StrEnvVar
+	WebhookURLs          tools.StrEnvVar
+	WebhookOutboxPath    tools.StrEnvVar
+	StoreInterval        tools.IntEnvVar
+	HistoryRetention     tools.IntEnvVar
+	SnapshotGenerations  tools.IntEnvVar
+	RulesInterval        tools.IntEnvVar
+	WriteBehindSize      tools.IntEnvVar
+	WriteBehindFlushSize tools.IntEnvVar
+	WriteBehindInterval  tools.IntEnvVar
+	MetricsTTL           tools.IntEnvVar
+	SilenceRetention     tools.IntEnvVar
+	RestoreStorage       tools.BoolEnvVar
+	WALEnabled           tools.BoolEnvVar
 }
 
 // parseServerArgsFromEnv парсит переменные окружения в serverEnvArgs.
 func parseServerArgsFromEnv() serverEnvArgs {
 	return serverEnvArgs{
-		Addr:            tools.GetStrFromEnv("ADDRESS"),
-		PrivateKey:      tools.GetStrFromEnv("KEY"),
-		StoreInterval:   tools.GetIntFromEnv("STORE_INTERVAL"),
-		StoragePath:     tools.GetStrFromEnv("FILE_STORAGE_PATH"),
-		RestoreStorage:  tools.GetBoolFromEnv("RESTORE"),
-		DatabaseConnStr: tools.GetStrFromEnv("DATABASE_DSN"),
+		Addr:                 tools.GetStrFromEnv("ADDRESS"),
+		PrivateKey:           tools.GetStrFromEnv("KEY"),
+		StoreInterval:        tools.GetIntFromEnv("STORE_INTERVAL"),
+		StorageEngine:        tools.GetStrFromEnv("STORAGE_ENGINE"),
+		StoragePath:          tools.GetStrFromEnv("FILE_STORAGE_PATH"),
+		BoltPath:             tools.GetStrFromEnv("BOLT_STORAGE_PATH"),
+		RestoreStorage:       tools.GetBoolFromEnv("RESTORE"),
+		WALEnabled:           tools.GetBoolFromEnv("WAL_ENABLED"),
+		SnapshotGenerations:  tools.GetIntFromEnv("SNAPSHOT_GENERATIONS"),
+		DatabaseConnStr:      tools.GetStrFromEnv("DATABASE_DSN"),
+		HistoryRetention:     tools.GetIntFromEnv("HISTORY_RETENTION"),
+		StatsdAddr:           tools.GetStrFromEnv("STATSD_ADDRESS"),
+		GraphiteAddr:         tools.GetStrFromEnv("GRAPHITE_ADDRESS"),
+		GRPCAddr:             tools.GetStrFromEnv("GRPC_ADDRESS"),
+		RulesPath:            tools.GetStrFromEnv("RULES_FILE"),
+		RecordingRulesPath:   tools.GetStrFromEnv("RECORDING_RULES_FILE"),
+		RulesInterval:        tools.GetIntFromEnv("RULES_INTERVAL"),
+		WriteBehindSize:      tools.GetIntFromEnv("WRITE_BEHIND_SIZE"),
+		WriteBehindFlushSize: tools.GetIntFromEnv("WRITE_BEHIND_FLUSH_SIZE"),
+		WriteBehindInterval:  tools.GetIntFromEnv("WRITE_BEHIND_INTERVAL"),
+		MetricsTTL:           tools.GetIntFromEnv("METRICS_TTL"),
+		SilenceRetention:     tools.GetIntFromEnv("SILENCE_RETENTION"),
+		WebhookURLs:          tools.GetStrFromEnv("WEBHOOK_URLS"),
+		WebhookOutboxPath:    tools.GetStrFromEnv("WEBHOOK_OUTBOX_PATH"),
 	}
 }
 
+// Команды режима миграций схемы БД.
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+)
+
+// MigrateArgs структура, описывающая аргументы режима миграций сервера.
+type MigrateArgs struct {
+	Command         string
+	DatabaseConnStr string
+	// Steps количество откатываемых миграций для команды down.
+	Steps int
+}
+
+// ParseMigrateArgs парсит аргументы режима миграций
+// migrate [-d <dsn>] [-steps <n>] up|down|status.
+// arguments не должны содержать название самого режима.
+func ParseMigrateArgs(arguments []string) (MigrateArgs, error) {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	databaseConnStr := flags.String("d", "", "connection string for postgresql")
+	steps := flags.Int("steps", 1, "number of migrations to revert by down command")
+
+	err := flags.Parse(arguments)
+	if err != nil {
+		return MigrateArgs{}, err
+	}
+
+	if flags.NArg() != 1 {
+		return MigrateArgs{}, errors.New("expected exactly one migrate command: up, down or status")
+	}
+
+	args := MigrateArgs{
+		Command:         flags.Arg(0),
+		DatabaseConnStr: *databaseConnStr,
+		Steps:           *steps,
+	}
+
+	switch args.Command {
+	case MigrateUp, MigrateDown, MigrateStatus:
+	default:
+		return MigrateArgs{}, fmt.Errorf("unknown migrate command %q", args.Command)
+	}
+
+	if args.Steps <= 0 {
+		return MigrateArgs{}, errors.New("steps must be positive")
+	}
+
+	if databaseConnStr := tools.GetStrFromEnv("DATABASE_DSN"); databaseConnStr.Exists {
+		args.DatabaseConnStr = databaseConnStr.Value
+	}
+
+	if args.DatabaseConnStr == "" {
+		return MigrateArgs{}, errors.New("connection string for postgresql is required")
+	}
+
+	return args, nil
+}
+
 // AgentArgs структура, описывающая аргументы агента.
 type AgentArgs struct {
 	Addr               string
+	GRPCAddr           string
 	PrivateKey         string
 	PollInterval       int
 	ReportInterval     time.Duration
@@ -112,6 +355,7 @@ type AgentArgs struct {
 func ParseAgentArgs() AgentArgs {
 	address := new(NetAddress)
 	flag.Var(address, "a", "address of metrix server in form <host:port>")
+	grpcAddr := flag.String("grpc", "", "address of metrix gRPC server in form <host:port> (empty = use HTTP)")
 	privateKey := flag.String("k", "", "key for hash funcs")
 	pollInterval := flag.Int("p", 2, "poll interval (in sec)")
 	reportInterval := flag.Int("r", 2, "report interval (in sec)")
@@ -123,6 +367,7 @@ func ParseAgentArgs() AgentArgs {
 
 	args := AgentArgs{
 		Addr:               address.String(),
+		GRPCAddr:           *grpcAddr,
 		PrivateKey:         *privateKey,
 		PollInterval:       *pollInterval,
 		ReportRateLimit:    *reportRateLimit,
@@ -141,6 +386,9 @@ func ParseAgentArgs() AgentArgs {
 	if envArgs.PrivateKey.Exists {
 		args.PrivateKey = envArgs.PrivateKey.Value
 	}
+	if envArgs.GRPCAddr.Exists {
+		args.GRPCAddr = envArgs.GRPCAddr.Value
+	}
 	if envArgs.PollInterval.Exists && envArgs.PollInterval.Value > 0 {
 		args.PollInterval = envArgs.PollInterval.Value
 	}
@@ -156,6 +404,7 @@ func ParseAgentArgs() AgentArgs {
 
 type agentEnvArgs struct {
 	Addr            tools.StrEnvVar
+	GRPCAddr        tools.StrEnvVar
 	PrivateKey      tools.StrEnvVar
 	PollInterval    tools.IntEnvVar
 	ReportInterval  tools.IntEnvVar
@@ -166,6 +415,7 @@ type agentEnvArgs struct {
 func parseAgentArgsFromEnv() agentEnvArgs {
 	return agentEnvArgs{
 		Addr:            tools.GetStrFromEnv("ADDRESS"),
+		GRPCAddr:        tools.GetStrFromEnv("GRPC_ADDRESS"),
 		PrivateKey:      tools.GetStrFromEnv("KEY"),
 		PollInterval:    tools.GetIntFromEnv("POLL_INTERVAL"),
 		ReportInterval:  tools.GetIntFromEnv("REPORT_INTERVAL"),
@@ -0,0 +1,120 @@
+// Package boltstorage содержит встраиваемое хранилище метрик
+// в файле на диске на основе bbolt.
+package boltstorage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+var (
+	// metricsBucket текущие значения метрик.
+	metricsBucket = []byte("metrics")
+	// historyBucket история значений метрик. Содержит
+	// вложенный бакет для каждой метрики.
+	historyBucket = []byte("history")
+	// silencesBucket заглушения оповещений.
+	silencesBucket = []byte("silences")
+)
+
+// openTimeout время ожидания блокировки файла хранилища,
+// удерживаемой другим процессом.
+const openTimeout = 5 * time.Second
+
+// BoltStorageOptions параметры хранилища.
+type BoltStorageOptions struct {
+	// Path путь до файла хранилища.
+	Path string
+	// HistoryRetention время хранения истории значений метрик.
+	// Если равно нулю, история не сохраняется.
+	HistoryRetention time.Duration
+}
+
+// NewBoltStorage открывает хранилище, создавая файл при его отсутствии.
+func NewBoltStorage(opts BoltStorageOptions) (*BoltStorage, error) {
+	db, err := bolt.Open(opts.Path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if errors.Is(err, bolterrors.ErrInvalid) {
+		return nil, fmt.Errorf("%s is not a bolt database, it may be a JSON metrics file of memory storage: %w", opts.Path, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{metricsBucket, historyBucket, silencesBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStorage{
+		db:               db,
+		historyRetention: opts.HistoryRetention,
+	}, nil
+}
+
+// BoltStorage хранилище метрик в файле на диске.
+//
+// Каждое обновление выполняется в отдельной транзакции и сбрасывается
+// на диск до возврата, поэтому периодическое сохранение не требуется.
+type BoltStorage struct {
+	db               *bolt.DB
+	historyRetention time.Duration
+}
+
+// Ping проверяет, что хранилище открыто.
+func (storage *BoltStorage) Ping(_ context.Context) error {
+	return storage.db.View(func(*bolt.Tx) error {
+		return nil
+	})
+}
+
+// SaveMetrics сохраняет текущие значения метрик.
+// Значения сохраняются при каждом обновлении, поэтому метод ничего не делает.
+func (storage *BoltStorage) SaveMetrics(_ context.Context) error {
+	return nil
+}
+
+// Destroy закрывает хранилище.
+func (storage *BoltStorage) Destroy(_ context.Context) {
+	storage.db.Close()
+}
+
+// metricKey ключ, однозначно определяющий метрику.
+// Части разделены нулевым байтом, который не может встретиться
+// ни в типе, ни в каноническом представлении меток.
+func metricKey(metricType models.MetricType, id string, labels models.Labels) []byte {
+	return []byte(string(metricType) + "\x00" + id + "\x00" + labels.Key())
+}
+
+// sampleKey ключ значения в истории метрики: момент получения
+// в наносекундах и порядковый номер для значений, полученных одновременно.
+// Ключи в big-endian упорядочены так же, как и моменты получения.
+func sampleKey(ts time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(ts.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], seq)
+
+	return key
+}
+
+// sampleTime возвращает момент получения значения по ключу истории.
+func sampleTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key)))
+}
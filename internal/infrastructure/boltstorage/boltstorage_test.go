@@ -0,0 +1,223 @@
+package boltstorage_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	bolterrors "go.etcd.io/bbolt/errors"
+
+	"github.com/xantinium/metrix/internal/infrastructure/boltstorage"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestBoltStorage(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.bolt"
+
+	storage, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{Path: path})
+	require.NoError(t, err)
+
+	hostA := models.Labels{"host": "a"}
+
+	_, err = storage.UpdateGaugeMetric(ctx, "Alloc", nil, 5)
+	require.NoError(t, err)
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", hostA, 2)
+	require.NoError(t, err)
+
+	counter, err := storage.UpdateCounterMetric(ctx, "PollCount", hostA, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), counter)
+
+	_, err = storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	metrics, err := storage.GetAllMetrics(ctx, hostA)
+	require.NoError(t, err)
+	require.Equal(t, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 5).WithLabels(hostA),
+	}, metrics)
+
+	require.NoError(t, storage.Ping(ctx))
+	storage.Destroy(ctx)
+	require.Error(t, storage.Ping(ctx))
+
+	// Значения сохраняются в файле между запусками.
+	reopened, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{Path: path})
+	require.NoError(t, err)
+	defer reopened.Destroy(ctx)
+
+	gauge, err := reopened.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(5), gauge)
+
+	counter, err = reopened.GetCounterMetric(ctx, "PollCount", hostA)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), counter)
+
+	// История не хранится, если время её хранения не задано.
+	_, err = reopened.GetMetricHistory(ctx, models.Gauge, "Alloc", nil, time.Now().Add(-time.Hour), time.Now())
+	require.ErrorIs(t, err, models.ErrHistoryDisabled)
+}
+
+func TestBoltStorage_JSONFile(t *testing.T) {
+	path := t.TempDir() + "/metrix.db"
+
+	// Файл метрик хранилища в памяти не открывается и не изменяется.
+	data := []byte(`{"metrics":[{"id":"Alloc","type":"gauge","delta":0,"value":1.5}]}`)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	_, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{Path: path})
+	require.ErrorIs(t, err, bolterrors.ErrInvalid)
+
+	stored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+}
+
+func TestBoltStorage_UpdateMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{Path: t.TempDir() + "/metrix.bolt"})
+	require.NoError(t, err)
+	defer storage.Destroy(ctx)
+
+	first, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+	second, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{0, 1, 1}, 1.4)
+	require.NoError(t, err)
+	mismatched, err := models.NewHistogramValue([]float64{1}, []int64{1, 0}, 0.5)
+	require.NoError(t, err)
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 1),
+		models.NewHistogramMetric("Latency", first),
+		models.NewCounterMetric("PollCount", 2),
+	})
+	require.NoError(t, err)
+
+	value, err := storage.UpdateHistogramMetric(ctx, "Latency", nil, second)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3, 1}, value.Counts)
+
+	// При ошибке объединения транзакция откатывается целиком.
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 10),
+		models.NewHistogramMetric("Latency", mismatched),
+	})
+	require.ErrorIs(t, err, models.ErrHistogramBoundsMismatch)
+
+	counter, err := storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), counter)
+}
+
+func TestBoltStorage_History(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{
+		Path:             t.TempDir() + "/metrix.bolt",
+		HistoryRetention: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer storage.Destroy(ctx)
+
+	start := time.Now()
+
+	_, err = storage.UpdateGaugeMetric(ctx, "HeapAlloc", nil, 1)
+	require.NoError(t, err)
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("HeapAlloc", 2),
+		models.NewCounterMetric("PollCount", 3),
+		models.NewCounterMetric("PollCount", 4),
+	})
+	require.NoError(t, err)
+
+	samples, err := storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, float64(1), samples[0].GaugeValue)
+	require.Equal(t, float64(2), samples[1].GaugeValue)
+
+	// Значения, полученные одновременно, сохраняются по отдельности.
+	samples, err = storage.GetMetricHistory(ctx, models.Counter, "PollCount", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, int64(3), samples[0].CounterValue)
+	require.Equal(t, int64(4), samples[1].CounterValue)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	// После истечения времени хранения старые значения удаляются.
+	time.Sleep(300 * time.Millisecond)
+
+	_, err = storage.UpdateGaugeMetric(ctx, "HeapAlloc", nil, 3)
+	require.NoError(t, err)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	require.Equal(t, float64(3), samples[0].GaugeValue)
+
+	// Значения метрик, которые больше не обновляются,
+	// удаляются при периодической очистке истории.
+	pruned, err := storage.PruneHistory(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Counter, "PollCount", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+}
+
+func TestBoltStorage_Silences(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{Path: t.TempDir() + "/metrix.bolt"})
+	require.NoError(t, err)
+	defer storage.Destroy(ctx)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	silence := models.Silence{
+		ID:       "b",
+		RuleName: "HighCPU",
+		Matchers: []models.LabelMatcher{{Name: "host", Value: "a", Type: models.MatchEqual}},
+		StartsAt: now,
+		EndsAt:   now.Add(time.Hour),
+	}
+	earlier := models.Silence{
+		ID:       "a",
+		RuleName: "HighCPU",
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+	}
+
+	require.NoError(t, storage.CreateSilence(ctx, silence))
+	require.NoError(t, storage.CreateSilence(ctx, earlier))
+	require.Error(t, storage.CreateSilence(ctx, silence))
+
+	silences, err := storage.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+	require.Equal(t, "a", silences[0].ID)
+	require.Equal(t, silence.Matchers, silences[1].Matchers)
+
+	expired, err := storage.ExpireSilence(ctx, "b", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), expired.EndsAt)
+
+	_, err = storage.ExpireSilence(ctx, "b", now.Add(2*time.Minute))
+	require.ErrorIs(t, err, models.ErrSilenceExpired)
+
+	_, err = storage.ExpireSilence(ctx, "c", now)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
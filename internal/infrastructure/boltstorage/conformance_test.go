@@ -0,0 +1,23 @@
+package boltstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xantinium/metrix/internal/infrastructure/boltstorage"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/repository/metrics/storagetest"
+)
+
+func TestBoltStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Opener {
+		path := t.TempDir() + "/metrix.bolt"
+
+		return func() (metrics.MetricsStorage, error) {
+			return boltstorage.NewBoltStorage(boltstorage.BoltStorageOptions{
+				Path:             path,
+				HistoryRetention: time.Hour,
+			})
+		}
+	})
+}
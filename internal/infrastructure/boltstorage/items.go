@@ -0,0 +1,196 @@
+package boltstorage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// metricItem представление метрики в хранилище.
+//
+//easyjson:json
+type metricItem struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Histogram *histogramItem    `json:"histogram,omitempty"`
+	Summary   *summaryItem      `json:"summary,omitempty"`
+	ID        string            `json:"name"`
+	Type      string            `json:"type"`
+	Delta     int64             `json:"delta"`
+	Value     float64           `json:"value"`
+	// UpdatedAt время последнего обновления метрики (в наносекундах).
+	// Отсутствует у метрик, записанных до его появления.
+	UpdatedAt int64 `json:"updatedAt,omitempty"`
+}
+
+type histogramItem struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+}
+
+type summaryBinsItem struct {
+	Counts []int64 `json:"counts,omitempty"`
+	Offset int     `json:"offset"`
+}
+
+type summaryItem struct {
+	Positive  summaryBinsItem `json:"positive"`
+	Negative  summaryBinsItem `json:"negative"`
+	Accuracy  float64         `json:"accuracy"`
+	ZeroCount int64           `json:"zeroCount"`
+	Count     int64           `json:"count"`
+	Sum       float64         `json:"sum"`
+	Min       float64         `json:"min"`
+	Max       float64         `json:"max"`
+}
+
+// sampleItem представление значения из истории метрики.
+// Момент получения значения хранится в ключе.
+//
+//easyjson:json
+type sampleItem struct {
+	Delta int64   `json:"delta,omitempty"`
+	Value float64 `json:"value,omitempty"`
+}
+
+//easyjson:json
+type silenceItem struct {
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	Matchers  []silenceMatcher `json:"matchers,omitempty"`
+	ID        string           `json:"id"`
+	RuleName  string           `json:"ruleName,omitempty"`
+	Comment   string           `json:"comment,omitempty"`
+	CreatedBy string           `json:"createdBy,omitempty"`
+}
+
+type silenceMatcher struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+// newMetricItem преобразует метрику в представление хранилища.
+func newMetricItem(metric models.MetricInfo) metricItem {
+	item := metricItem{
+		Labels: metric.Labels(),
+		ID:     metric.ID(),
+		Type:   string(metric.Type()),
+	}
+
+	switch metric.Type() {
+	case models.Gauge:
+		item.Value = metric.GaugeValue()
+	case models.Counter:
+		item.Delta = metric.CounterValue()
+	case models.Histogram:
+		value := metric.HistogramValue()
+		item.Histogram = &histogramItem{
+			Bounds: value.Bounds,
+			Counts: value.Counts,
+			Count:  value.Count,
+			Sum:    value.Sum,
+		}
+	case models.Summary:
+		value := metric.SummaryValue()
+		item.Summary = &summaryItem{
+			Positive:  summaryBinsItem{Counts: value.Positive.Counts, Offset: value.Positive.Offset},
+			Negative:  summaryBinsItem{Counts: value.Negative.Counts, Offset: value.Negative.Offset},
+			Accuracy:  value.Accuracy,
+			ZeroCount: value.ZeroCount,
+			Count:     value.Count,
+			Sum:       value.Sum,
+			Min:       value.Min,
+			Max:       value.Max,
+		}
+	}
+
+	return item
+}
+
+// toMetricInfo преобразует представление хранилища в метрику.
+func (item metricItem) toMetricInfo() (models.MetricInfo, error) {
+	var metric models.MetricInfo
+
+	switch item.Type {
+	case string(models.Gauge):
+		metric = models.NewGaugeMetric(item.ID, item.Value)
+	case string(models.Counter):
+		metric = models.NewCounterMetric(item.ID, item.Delta)
+	case string(models.Histogram):
+		if item.Histogram == nil {
+			return models.MetricInfo{}, fmt.Errorf("histogram metric %q has no value", item.ID)
+		}
+
+		metric = models.NewHistogramMetric(item.ID, models.HistogramValue{
+			Bounds: item.Histogram.Bounds,
+			Counts: item.Histogram.Counts,
+			Count:  item.Histogram.Count,
+			Sum:    item.Histogram.Sum,
+		})
+	case string(models.Summary):
+		if item.Summary == nil {
+			return models.MetricInfo{}, fmt.Errorf("summary metric %q has no value", item.ID)
+		}
+
+		metric = models.NewSummaryMetric(item.ID, models.SummaryValue{
+			Positive:  models.SummaryBins{Offset: item.Summary.Positive.Offset, Counts: item.Summary.Positive.Counts},
+			Negative:  models.SummaryBins{Offset: item.Summary.Negative.Offset, Counts: item.Summary.Negative.Counts},
+			Accuracy:  item.Summary.Accuracy,
+			ZeroCount: item.Summary.ZeroCount,
+			Count:     item.Summary.Count,
+			Sum:       item.Summary.Sum,
+			Min:       item.Summary.Min,
+			Max:       item.Summary.Max,
+		})
+	default:
+		return models.MetricInfo{}, fmt.Errorf("metric %q has unknown type %q", item.ID, item.Type)
+	}
+
+	return metric.WithLabels(item.Labels), nil
+}
+
+func newSilenceItem(silence models.Silence) silenceItem {
+	item := silenceItem{
+		StartsAt:  silence.StartsAt,
+		EndsAt:    silence.EndsAt,
+		Matchers:  make([]silenceMatcher, len(silence.Matchers)),
+		ID:        silence.ID,
+		RuleName:  silence.RuleName,
+		Comment:   silence.Comment,
+		CreatedBy: silence.CreatedBy,
+	}
+
+	for i, matcher := range silence.Matchers {
+		item.Matchers[i] = silenceMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  string(matcher.Type),
+		}
+	}
+
+	return item
+}
+
+func (item silenceItem) toSilence() models.Silence {
+	silence := models.Silence{
+		StartsAt:  item.StartsAt,
+		EndsAt:    item.EndsAt,
+		ID:        item.ID,
+		RuleName:  item.RuleName,
+		Comment:   item.Comment,
+		CreatedBy: item.CreatedBy,
+	}
+
+	for _, matcher := range item.Matchers {
+		silence.Matchers = append(silence.Matchers, models.LabelMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  models.MatchType(matcher.Type),
+		})
+	}
+
+	return silence
+}
@@ -0,0 +1,358 @@
+package boltstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mailru/easyjson"
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// UpdateGaugeMetric обновляет текущее значение метрики типа Gauge
+// с идентификатором id и набором меток labels, перезаписывая его значением value.
+//
+// Возвращает обновлённое значение метрики.
+func (storage *BoltStorage) UpdateGaugeMetric(_ context.Context, id string, labels models.Labels, value float64) (float64, error) {
+	metric, err := storage.updateMetric(models.NewGaugeMetric(id, value).WithLabels(labels))
+	if err != nil {
+		return 0, err
+	}
+
+	return metric.GaugeValue(), nil
+}
+
+// UpdateCounterMetric обновляет текущее значение метрики типа Counter
+// с идентификатором id и набором меток labels, добавляя к нему значение value.
+//
+// Возвращает обновлённое значение метрики.
+func (storage *BoltStorage) UpdateCounterMetric(_ context.Context, id string, labels models.Labels, value int64) (int64, error) {
+	metric, err := storage.updateMetric(models.NewCounterMetric(id, value).WithLabels(labels))
+	if err != nil {
+		return 0, err
+	}
+
+	return metric.CounterValue(), nil
+}
+
+// UpdateHistogramMetric обновляет текущее значение метрики типа Histogram
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+//
+// Возвращает обновлённое значение метрики.
+// История значений гистограмм не сохраняется.
+func (storage *BoltStorage) UpdateHistogramMetric(_ context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error) {
+	metric, err := storage.updateMetric(models.NewHistogramMetric(id, value).WithLabels(labels))
+	if err != nil {
+		return models.HistogramValue{}, err
+	}
+
+	return metric.HistogramValue(), nil
+}
+
+// UpdateSummaryMetric обновляет текущее значение метрики типа Summary
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+//
+// Возвращает обновлённое значение метрики.
+// История значений скетчей не сохраняется.
+func (storage *BoltStorage) UpdateSummaryMetric(_ context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error) {
+	metric, err := storage.updateMetric(models.NewSummaryMetric(id, value).WithLabels(labels))
+	if err != nil {
+		return models.SummaryValue{}, err
+	}
+
+	return metric.SummaryValue(), nil
+}
+
+// UpdateMetrics обновляет текущее значение метрик в одной транзакции.
+//
+// Если какую-либо из гистограмм или скетчей невозможно объединить
+// с текущим значением, ни одна метрика не обновляется.
+func (storage *BoltStorage) UpdateMetrics(_ context.Context, metrics []models.MetricInfo) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	return storage.db.Update(func(tx *bolt.Tx) error {
+		for _, metric := range metrics {
+			_, err := storage.applyMetric(tx, metric, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// updateMetric обновляет текущее значение метрики в отдельной транзакции.
+//
+// Возвращает обновлённую структуру метрики.
+func (storage *BoltStorage) updateMetric(metric models.MetricInfo) (models.MetricInfo, error) {
+	var newMetric models.MetricInfo
+
+	err := storage.db.Update(func(tx *bolt.Tx) error {
+		var err error
+
+		newMetric, err = storage.applyMetric(tx, metric, time.Now())
+		return err
+	})
+
+	return newMetric, err
+}
+
+// applyMetric объединяет значение метрики с текущим и сохраняет его
+// вместе со значением в истории: gauge перезаписывается, counter
+// увеличивается, гистограммы и скетчи объединяются.
+//
+// Возвращает обновлённую структуру метрики.
+func (storage *BoltStorage) applyMetric(tx *bolt.Tx, metric models.MetricInfo, now time.Time) (models.MetricInfo, error) {
+	bucket := tx.Bucket(metricsBucket)
+	key := metricKey(metric.Type(), metric.ID(), metric.Labels())
+
+	current, exists, err := readMetric(bucket, key)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	newMetric := metric
+	if exists {
+		switch metric.Type() {
+		case models.Counter:
+			newMetric = models.NewCounterMetric(metric.ID(), current.CounterValue()+metric.CounterValue())
+		case models.Histogram:
+			value, err := current.HistogramValue().Merge(metric.HistogramValue())
+			if err != nil {
+				return models.MetricInfo{}, err
+			}
+
+			newMetric = models.NewHistogramMetric(metric.ID(), value)
+		case models.Summary:
+			value, err := current.SummaryValue().Merge(metric.SummaryValue())
+			if err != nil {
+				return models.MetricInfo{}, err
+			}
+
+			newMetric = models.NewSummaryMetric(metric.ID(), value)
+		}
+
+		newMetric = newMetric.WithLabels(metric.Labels())
+	}
+
+	item := newMetricItem(newMetric)
+	item.UpdatedAt = now.UnixNano()
+
+	data, err := easyjson.Marshal(item)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	err = bucket.Put(key, data)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	if metric.Type() == models.Gauge || metric.Type() == models.Counter {
+		err = storage.appendSample(tx, key, metric, now)
+		if err != nil {
+			return models.MetricInfo{}, err
+		}
+	}
+
+	return newMetric, nil
+}
+
+// appendSample добавляет значение метрики в историю
+// и удаляет значения, вышедшие за время хранения.
+// Для метрик типа Counter сохраняется приращение.
+func (storage *BoltStorage) appendSample(tx *bolt.Tx, key []byte, metric models.MetricInfo, now time.Time) error {
+	if storage.historyRetention == 0 {
+		return nil
+	}
+
+	bucket, err := tx.Bucket(historyBucket).CreateBucketIfNotExists(key)
+	if err != nil {
+		return err
+	}
+
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	data, err := easyjson.Marshal(sampleItem{
+		Delta: metric.CounterValue(),
+		Value: metric.GaugeValue(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = bucket.Put(sampleKey(now, seq), data)
+	if err != nil {
+		return err
+	}
+
+	_, err = pruneSamples(bucket, now.Add(-storage.historyRetention))
+
+	return err
+}
+
+// PruneHistory удаляет значения истории, полученные раньше now
+// за вычетом времени хранения, в том числе у метрик, которые
+// больше не обновляются.
+//
+// Возвращает количество удалённых значений.
+func (storage *BoltStorage) PruneHistory(_ context.Context, now time.Time) (int, error) {
+	if storage.historyRetention == 0 {
+		return 0, nil
+	}
+
+	cutoff := now.Add(-storage.historyRetention)
+
+	var pruned int
+
+	err := storage.db.Update(func(tx *bolt.Tx) error {
+		history := tx.Bucket(historyBucket)
+
+		var (
+			keys  [][]byte
+			empty [][]byte
+		)
+		err := history.ForEachBucket(func(key []byte) error {
+			// Ключ действителен только до конца транзакции.
+			keys = append(keys, bytes.Clone(key))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Бакет нельзя изменять во время обхода ForEachBucket.
+		for _, key := range keys {
+			bucket := history.Bucket(key)
+
+			expired, err := pruneSamples(bucket, cutoff)
+			if err != nil {
+				return err
+			}
+			pruned += expired
+
+			if first, _ := bucket.Cursor().First(); first == nil {
+				empty = append(empty, key)
+			}
+		}
+
+		for _, key := range empty {
+			err = history.DeleteBucket(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return pruned, nil
+}
+
+// pruneSamples удаляет из бакета истории значения, полученные раньше cutoff.
+// Возвращает количество удалённых значений.
+func pruneSamples(bucket *bolt.Bucket, cutoff time.Time) (int, error) {
+	var pruned int
+
+	// Ключи упорядочены по времени, поэтому устаревшие
+	// значения всегда находятся в начале бакета.
+	cursor := bucket.Cursor()
+	for key, _ := cursor.First(); key != nil && sampleTime(key).Before(cutoff); key, _ = cursor.First() {
+		err := cursor.Delete()
+		if err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// DeleteMetric удаляет метрику типа metricType с идентификатором id
+// и набором меток labels вместе с её историей.
+func (storage *BoltStorage) DeleteMetric(_ context.Context, metricType models.MetricType, id string, labels models.Labels) error {
+	key := metricKey(metricType, id, labels)
+
+	return storage.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(metricsBucket).Get(key) == nil {
+			return models.ErrNotFound
+		}
+
+		return deleteMetric(tx, key)
+	})
+}
+
+// DeleteStaleMetrics удаляет метрики, последнее обновление которых
+// было раньше updatedBefore, вместе с их историей. Метрики, записанные
+// до появления времени обновления, не удаляются до их следующего обновления.
+//
+// Возвращает количество удалённых метрик.
+func (storage *BoltStorage) DeleteStaleMetrics(_ context.Context, updatedBefore time.Time) (int, error) {
+	var stale [][]byte
+
+	err := storage.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(metricsBucket).ForEach(func(key, data []byte) error {
+			var item metricItem
+
+			err := easyjson.Unmarshal(data, &item)
+			if err != nil {
+				return err
+			}
+
+			if item.UpdatedAt != 0 && item.UpdatedAt < updatedBefore.UnixNano() {
+				// Ключ действителен только до конца транзакции.
+				stale = append(stale, bytes.Clone(key))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Бакет нельзя изменять во время обхода ForEach.
+		for _, key := range stale {
+			err = deleteMetric(tx, key)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(stale), nil
+}
+
+// deleteMetric удаляет метрику с ключом key вместе с её историей.
+func deleteMetric(tx *bolt.Tx, key []byte) error {
+	err := tx.Bucket(metricsBucket).Delete(key)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Bucket(historyBucket).DeleteBucket(key)
+	if err != nil && !errors.Is(err, bolterrors.ErrBucketNotFound) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package boltstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mailru/easyjson"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id
+// и набору меток labels.
+func (storage *BoltStorage) GetGaugeMetric(_ context.Context, id string, labels models.Labels) (float64, error) {
+	metric, err := storage.getMetric(models.Gauge, id, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	return metric.GaugeValue(), nil
+}
+
+// GetCounterMetric возвращает метрику типа Counter по идентификатору id
+// и набору меток labels.
+func (storage *BoltStorage) GetCounterMetric(_ context.Context, id string, labels models.Labels) (int64, error) {
+	metric, err := storage.getMetric(models.Counter, id, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	return metric.CounterValue(), nil
+}
+
+// GetHistogramMetric возвращает метрику типа Histogram по идентификатору id
+// и набору меток labels.
+func (storage *BoltStorage) GetHistogramMetric(_ context.Context, id string, labels models.Labels) (models.HistogramValue, error) {
+	metric, err := storage.getMetric(models.Histogram, id, labels)
+	if err != nil {
+		return models.HistogramValue{}, err
+	}
+
+	return metric.HistogramValue(), nil
+}
+
+// GetSummaryMetric возвращает метрику типа Summary по идентификатору id
+// и набору меток labels.
+func (storage *BoltStorage) GetSummaryMetric(_ context.Context, id string, labels models.Labels) (models.SummaryValue, error) {
+	metric, err := storage.getMetric(models.Summary, id, labels)
+	if err != nil {
+		return models.SummaryValue{}, err
+	}
+
+	return metric.SummaryValue(), nil
+}
+
+func (storage *BoltStorage) getMetric(metricType models.MetricType, id string, labels models.Labels) (models.MetricInfo, error) {
+	var metric models.MetricInfo
+
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		var (
+			err    error
+			exists bool
+		)
+
+		metric, exists, err = readMetric(tx.Bucket(metricsBucket), metricKey(metricType, id, labels))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return metric, err
+}
+
+// readMetric читает метрику по ключу key.
+func readMetric(bucket *bolt.Bucket, key []byte) (models.MetricInfo, bool, error) {
+	data := bucket.Get(key)
+	if data == nil {
+		return models.MetricInfo{}, false, nil
+	}
+
+	metric, err := decodeMetric(data)
+	if err != nil {
+		return models.MetricInfo{}, false, err
+	}
+
+	return metric, true, nil
+}
+
+// decodeMetric десериализует метрику. Данные, прочитанные из транзакции,
+// действительны только до её завершения, поэтому метрика не должна
+// ссылаться на них.
+func decodeMetric(data []byte) (models.MetricInfo, error) {
+	var item metricItem
+
+	err := easyjson.Unmarshal(data, &item)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	return item.toMetricInfo()
+}
+
+// GetAllMetrics возвращает все существующие метрики,
+// содержащие метки из filter.
+func (storage *BoltStorage) GetAllMetrics(_ context.Context, filter models.Labels) ([]models.MetricInfo, error) {
+	metrics := make([]models.MetricInfo, 0)
+
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(metricsBucket).ForEach(func(_, data []byte) error {
+			metric, err := decodeMetric(data)
+			if err != nil {
+				return err
+			}
+
+			if metric.Labels().Matches(filter) {
+				metrics = append(metrics, metric)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// GetMetricHistory возвращает значения метрики с идентификатором id,
+// типом metricType и набором меток labels, полученные в промежутке [from, to].
+// Если время хранения истории не задано, возвращается models.ErrHistoryDisabled.
+func (storage *BoltStorage) GetMetricHistory(_ context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	if storage.historyRetention == 0 {
+		return nil, models.ErrHistoryDisabled
+	}
+
+	samples := make([]models.MetricSample, 0)
+
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket).Bucket(metricKey(metricType, id, labels))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for key, data := cursor.Seek(sampleKey(from, 0)); key != nil; key, data = cursor.Next() {
+			ts := sampleTime(key)
+			if ts.After(to) {
+				break
+			}
+
+			var item sampleItem
+
+			err := easyjson.Unmarshal(data, &item)
+			if err != nil {
+				return err
+			}
+
+			samples = append(samples, models.MetricSample{
+				Timestamp:    ts,
+				GaugeValue:   item.Value,
+				CounterValue: item.Delta,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
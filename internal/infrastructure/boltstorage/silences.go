@@ -0,0 +1,149 @@
+package boltstorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// GetSilences возвращает все заглушения, упорядоченные по времени начала.
+func (storage *BoltStorage) GetSilences(_ context.Context) ([]models.Silence, error) {
+	var silences []models.Silence
+
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(silencesBucket).ForEach(func(_, data []byte) error {
+			var item silenceItem
+
+			err := easyjson.Unmarshal(data, &item)
+			if err != nil {
+				return err
+			}
+
+			silences = append(silences, item.toSilence())
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(silences, func(a, b models.Silence) int {
+		if c := a.StartsAt.Compare(b.StartsAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return silences, nil
+}
+
+// CreateSilence сохраняет новое заглушение.
+func (storage *BoltStorage) CreateSilence(_ context.Context, silence models.Silence) error {
+	data, err := easyjson.Marshal(newSilenceItem(silence))
+	if err != nil {
+		return err
+	}
+
+	return storage.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(silencesBucket)
+
+		if bucket.Get([]byte(silence.ID)) != nil {
+			return fmt.Errorf("silence %q already exists", silence.ID)
+		}
+
+		return bucket.Put([]byte(silence.ID), data)
+	})
+}
+
+// ExpireSilence завершает заглушение с идентификатором id в момент now.
+func (storage *BoltStorage) ExpireSilence(_ context.Context, id string, now time.Time) (models.Silence, error) {
+	var silence models.Silence
+
+	err := storage.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(silencesBucket)
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return models.ErrNotFound
+		}
+
+		var item silenceItem
+
+		err := easyjson.Unmarshal(data, &item)
+		if err != nil {
+			return err
+		}
+
+		silence = item.toSilence()
+
+		err = silence.Expire(now)
+		if err != nil {
+			return err
+		}
+
+		data, err = easyjson.Marshal(newSilenceItem(silence))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(id), data)
+	})
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	return silence, nil
+}
+
+// DeleteExpiredSilences удаляет заглушения, завершившиеся раньше endedBefore.
+//
+// Возвращает количество удалённых заглушений.
+func (storage *BoltStorage) DeleteExpiredSilences(_ context.Context, endedBefore time.Time) (int, error) {
+	var expired [][]byte
+
+	err := storage.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(silencesBucket)
+
+		err := bucket.ForEach(func(key, data []byte) error {
+			var item silenceItem
+
+			err := easyjson.Unmarshal(data, &item)
+			if err != nil {
+				return err
+			}
+
+			if item.EndsAt.Before(endedBefore) {
+				// Ключ действителен только до конца транзакции.
+				expired = append(expired, bytes.Clone(key))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Бакет нельзя изменять во время обхода ForEach.
+		for _, key := range expired {
+			err = bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(expired), nil
+}
@@ -0,0 +1,40 @@
+package memstorage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/repository/metrics/storagetest"
+)
+
+func TestMemStorage_Conformance(t *testing.T) {
+	logger.Init(true)
+
+	tests := []struct {
+		name string
+		wal  bool
+	}{
+		{name: "без журнала", wal: false},
+		{name: "с журналом", wal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagetest.Run(t, func(t *testing.T) storagetest.Opener {
+				path := t.TempDir() + "/metrix.db"
+
+				return func() (metrics.MetricsStorage, error) {
+					return memstorage.NewMemStorage(memstorage.MemStorageOptions{
+						Path:             path,
+						Restore:          true,
+						HistoryRetention: time.Hour,
+						WAL:              tt.wal,
+					})
+				}
+			})
+		})
+	}
+}
@@ -2,8 +2,7 @@ package memstorage
 
 import (
 	"context"
-	"os"
-	"sync"
+	"fmt"
 
 	"github.com/mailru/easyjson"
 
@@ -11,38 +10,196 @@ import (
 )
 
 type metricItem struct {
-	ID    string  `json:"id"`
-	Type  string  `json:"type"`
-	Delta int64   `json:"delta"`
-	Value float64 `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Histogram *histogramItem    `json:"histogram,omitempty"`
+	Summary   *summaryItem      `json:"summary,omitempty"`
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Delta     int64             `json:"delta"`
+	Value     float64           `json:"value"`
+	// UpdatedAt время последнего обновления метрики (в наносекундах).
+	// Отсутствует в файлах, записанных до его появления.
+	UpdatedAt int64 `json:"updatedAt,omitempty"`
+}
+
+type histogramItem struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+}
+
+func (item histogramItem) toHistogramValue() models.HistogramValue {
+	return models.HistogramValue{
+		Bounds: item.Bounds,
+		Counts: item.Counts,
+		Count:  item.Count,
+		Sum:    item.Sum,
+	}
+}
+
+type summaryItem struct {
+	Positive       []int64 `json:"positive,omitempty"`
+	Negative       []int64 `json:"negative,omitempty"`
+	PositiveOffset int     `json:"positiveOffset"`
+	NegativeOffset int     `json:"negativeOffset"`
+	Accuracy       float64 `json:"accuracy"`
+	ZeroCount      int64   `json:"zeroCount"`
+	Count          int64   `json:"count"`
+	Sum            float64 `json:"sum"`
+	Min            float64 `json:"min"`
+	Max            float64 `json:"max"`
+}
+
+func newSummaryItem(value models.SummaryValue) *summaryItem {
+	return &summaryItem{
+		Positive:       value.Positive.Counts,
+		Negative:       value.Negative.Counts,
+		PositiveOffset: value.Positive.Offset,
+		NegativeOffset: value.Negative.Offset,
+		Accuracy:       value.Accuracy,
+		ZeroCount:      value.ZeroCount,
+		Count:          value.Count,
+		Sum:            value.Sum,
+		Min:            value.Min,
+		Max:            value.Max,
+	}
+}
+
+func (item summaryItem) toSummaryValue() models.SummaryValue {
+	return models.SummaryValue{
+		Positive:  models.SummaryBins{Offset: item.PositiveOffset, Counts: item.Positive},
+		Negative:  models.SummaryBins{Offset: item.NegativeOffset, Counts: item.Negative},
+		Accuracy:  item.Accuracy,
+		ZeroCount: item.ZeroCount,
+		Count:     item.Count,
+		Sum:       item.Sum,
+		Min:       item.Min,
+		Max:       item.Max,
+	}
+}
+
+// newMetricItem преобразует метрику в элемент файла.
+func newMetricItem(metric models.MetricInfo) metricItem {
+	item := metricItem{
+		Labels: metric.Labels(),
+		ID:     metric.ID(),
+		Type:   string(metric.Type()),
+	}
+
+	switch metric.Type() {
+	case models.Gauge:
+		item.Value = metric.GaugeValue()
+	case models.Counter:
+		item.Delta = metric.CounterValue()
+	case models.Histogram:
+		value := metric.HistogramValue()
+		item.Histogram = &histogramItem{
+			Bounds: value.Bounds,
+			Counts: value.Counts,
+			Count:  value.Count,
+			Sum:    value.Sum,
+		}
+	case models.Summary:
+		item.Summary = newSummaryItem(metric.SummaryValue())
+	}
+
+	return item
+}
+
+// toMetricInfo преобразует элемент файла в метрику, проверяя её значение.
+func (item metricItem) toMetricInfo() (models.MetricInfo, error) {
+	var metric models.MetricInfo
+
+	switch item.Type {
+	case string(models.Gauge):
+		metric = models.NewGaugeMetric(item.ID, item.Value)
+	case string(models.Counter):
+		metric = models.NewCounterMetric(item.ID, item.Delta)
+	case string(models.Histogram):
+		if item.Histogram == nil {
+			return models.MetricInfo{}, fmt.Errorf("histogram metric %q has no value", item.ID)
+		}
+
+		value := item.Histogram.toHistogramValue()
+
+		err := value.Validate()
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid histogram metric %q: %v", item.ID, err)
+		}
+
+		metric = models.NewHistogramMetric(item.ID, value)
+	case string(models.Summary):
+		if item.Summary == nil {
+			return models.MetricInfo{}, fmt.Errorf("summary metric %q has no value", item.ID)
+		}
+
+		value := item.Summary.toSummaryValue()
+
+		err := value.Validate()
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid summary metric %q: %v", item.ID, err)
+		}
+
+		metric = models.NewSummaryMetric(item.ID, value)
+	default:
+		return models.MetricInfo{}, fmt.Errorf("metric %q has unknown type %q", item.ID, item.Type)
+	}
+
+	return metric.WithLabels(item.Labels), nil
 }
 
 //easyjson:json
 type metricsStruct struct {
-	Metrics []metricItem `json:"metrics"`
+	Metrics  []metricItem  `json:"metrics"`
+	Silences []silenceItem `json:"silences,omitempty"`
+	// WALSegment номер первого сегмента журнала,
+	// записи которого не вошли в файл.
+	WALSegment uint64 `json:"walSegment,omitempty"`
 }
 
 // SaveMetrics сохраняет текущие значения метрик в файл.
-func (storage *MemStorage) SaveMetrics(ctx context.Context) error {
-	metrics, err := storage.GetAllMetrics(ctx)
+//
+// Если ведётся журнал упреждающей записи, начинается его новый сегмент,
+// а после записи файла удаляются сегменты, вошедшие во все сохранённые
+// поколения файла.
+func (storage *MemStorage) SaveMetrics(_ context.Context) error {
+	// Сохранения выполняются последовательно, чтобы более
+	// старый снимок не перезаписал более новый.
+	storage.saveMx.Lock()
+	defer storage.saveMx.Unlock()
+
+	var err error
+
+	// Снимок метрик и начало нового сегмента журнала
+	// выполняются атомарно относительно мутаций.
+	unlock := storage.lockAllShards()
+	storage.silencesMx.Lock()
+	metrics := storage.allMetrics(nil)
+	updatedAt := storage.metricsUpdatedAt(metrics)
+	silences := storage.allSilences()
+	if storage.wal != nil {
+		storage.walSegment, err = storage.wal.Rotate()
+	}
+	walSegment := storage.walSegment
+	storage.silencesMx.Unlock()
+	unlock()
+
 	if err != nil {
 		return err
 	}
 
-	metrisToSave := metricsStruct{Metrics: make([]metricItem, len(metrics))}
+	metrisToSave := metricsStruct{
+		Metrics:    make([]metricItem, len(metrics)),
+		Silences:   make([]silenceItem, len(silences)),
+		WALSegment: walSegment,
+	}
 	for i := range metrics {
-		item := metricItem{
-			ID:   metrics[i].ID(),
-			Type: string(metrics[i].Type()),
-		}
-		switch metrics[i].Type() {
-		case models.Gauge:
-			item.Value = metrics[i].GaugeValue()
-		case models.Counter:
-			item.Delta = metrics[i].CounterValue()
-		}
-
-		metrisToSave.Metrics[i] = item
+		metrisToSave.Metrics[i] = newMetricItem(metrics[i])
+		metrisToSave.Metrics[i].UpdatedAt = updatedAt[i]
+	}
+	for i := range silences {
+		metrisToSave.Silences[i] = newSilenceItem(silences[i])
 	}
 
 	bytes, err := easyjson.Marshal(metrisToSave)
@@ -50,33 +207,16 @@ func (storage *MemStorage) SaveMetrics(ctx context.Context) error {
 		return err
 	}
 
-	return storage.fileW.Write(bytes)
-}
-
-type fileWriter struct {
-	path string
-	mx   sync.Mutex
-	wg   sync.WaitGroup
-}
-
-func (w *fileWriter) Write(data []byte) error {
-	w.wg.Add(1)
-	w.mx.Lock()
-	defer func() {
-		w.mx.Unlock()
-		w.wg.Done()
-	}()
-
-	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	err = storage.fileW.Write(bytes)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	_, err = file.Write(data)
-	return err
-}
+	// Удаляются только сегменты, вошедшие во все сохранённые поколения.
+	storage.snapshotCuts = append([]uint64{walSegment}, storage.snapshotCuts...)
+	if len(storage.snapshotCuts) > storage.fileW.generations+1 {
+		storage.snapshotCuts = storage.snapshotCuts[:storage.fileW.generations+1]
+	}
 
-func (w *fileWriter) Wait() {
-	w.wg.Wait()
+	return removeWALSegments(storage.fileW.path, storage.snapshotCuts[len(storage.snapshotCuts)-1])
 }
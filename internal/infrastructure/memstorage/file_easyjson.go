@@ -45,7 +45,7 @@ func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 				in.Delim('[')
 				if out.Metrics == nil {
 					if !in.IsDelim(']') {
-						out.Metrics = make([]metricItem, 0, 1)
+						out.Metrics = make([]metricItem, 0, 0)
 					} else {
 						out.Metrics = []metricItem{}
 					}
@@ -60,6 +60,31 @@ func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 				}
 				in.Delim(']')
 			}
+		case "silences":
+			if in.IsNull() {
+				in.Skip()
+				out.Silences = nil
+			} else {
+				in.Delim('[')
+				if out.Silences == nil {
+					if !in.IsDelim(']') {
+						out.Silences = make([]silenceItem, 0, 0)
+					} else {
+						out.Silences = []silenceItem{}
+					}
+				} else {
+					out.Silences = (out.Silences)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 silenceItem
+					easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage2(in, &v2)
+					out.Silences = append(out.Silences, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "walSegment":
+			out.WALSegment = uint64(in.Uint64())
 		default:
 			in.SkipRecursive()
 		}
@@ -81,15 +106,34 @@ func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v2, v3 := range in.Metrics {
-				if v2 > 0 {
+			for v3, v4 := range in.Metrics {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage1(out, v4)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Silences) != 0 {
+		const prefix string = ",\"silences\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Silences {
+				if v5 > 0 {
 					out.RawByte(',')
 				}
-				easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage1(out, v3)
+				easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage2(out, v6)
 			}
 			out.RawByte(']')
 		}
 	}
+	if in.WALSegment != 0 {
+		const prefix string = ",\"walSegment\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.WALSegment))
+	}
 	out.RawByte('}')
 }
 
@@ -116,7 +160,125 @@ func (v *metricsStruct) UnmarshalJSON(data []byte) error {
 func (v *metricsStruct) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage(l, v)
 }
-func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage1(in *jlexer.Lexer, out *metricItem) {
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage2(in *jlexer.Lexer, out *silenceItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "startsAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.StartsAt).UnmarshalJSON(data))
+			}
+		case "endsAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.EndsAt).UnmarshalJSON(data))
+			}
+		case "matchers":
+			if in.IsNull() {
+				in.Skip()
+				out.Matchers = nil
+			} else {
+				in.Delim('[')
+				if out.Matchers == nil {
+					if !in.IsDelim(']') {
+						out.Matchers = make([]silenceMatcher, 0, 1)
+					} else {
+						out.Matchers = []silenceMatcher{}
+					}
+				} else {
+					out.Matchers = (out.Matchers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 silenceMatcher
+					easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage3(in, &v7)
+					out.Matchers = append(out.Matchers, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "ruleName":
+			out.RuleName = string(in.String())
+		case "comment":
+			out.Comment = string(in.String())
+		case "createdBy":
+			out.CreatedBy = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage2(out *jwriter.Writer, in silenceItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"startsAt\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.StartsAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"endsAt\":"
+		out.RawString(prefix)
+		out.Raw((in.EndsAt).MarshalJSON())
+	}
+	if len(in.Matchers) != 0 {
+		const prefix string = ",\"matchers\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v8, v9 := range in.Matchers {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage3(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	if in.RuleName != "" {
+		const prefix string = ",\"ruleName\":"
+		out.RawString(prefix)
+		out.String(string(in.RuleName))
+	}
+	if in.Comment != "" {
+		const prefix string = ",\"comment\":"
+		out.RawString(prefix)
+		out.String(string(in.Comment))
+	}
+	if in.CreatedBy != "" {
+		const prefix string = ",\"createdBy\":"
+		out.RawString(prefix)
+		out.String(string(in.CreatedBy))
+	}
+	out.RawByte('}')
+}
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage3(in *jlexer.Lexer, out *silenceMatcher) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -136,6 +298,102 @@ func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 		}
 		switch key {
 		case "name":
+			out.Name = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage3(out *jwriter.Writer, in silenceMatcher) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	out.RawByte('}')
+}
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage1(in *jlexer.Lexer, out *metricItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v10 string
+					v10 = string(in.String())
+					(out.Labels)[key] = v10
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "histogram":
+			if in.IsNull() {
+				in.Skip()
+				out.Histogram = nil
+			} else {
+				if out.Histogram == nil {
+					out.Histogram = new(histogramItem)
+				}
+				easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage4(in, out.Histogram)
+			}
+		case "summary":
+			if in.IsNull() {
+				in.Skip()
+				out.Summary = nil
+			} else {
+				if out.Summary == nil {
+					out.Summary = new(summaryItem)
+				}
+				easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage5(in, out.Summary)
+			}
+		case "id":
 			out.ID = string(in.String())
 		case "type":
 			out.Type = string(in.String())
@@ -143,6 +401,8 @@ func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 			out.Delta = int64(in.Int64())
 		case "value":
 			out.Value = float64(in.Float64())
+		case "updatedAt":
+			out.UpdatedAt = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -157,9 +417,54 @@ func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"name\":"
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
 		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v11First := true
+			for v11Name, v11Value := range in.Labels {
+				if v11First {
+					v11First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v11Name))
+				out.RawByte(':')
+				out.String(string(v11Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.Histogram != nil {
+		const prefix string = ",\"histogram\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage4(out, *in.Histogram)
+	}
+	if in.Summary != nil {
+		const prefix string = ",\"summary\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage5(out, *in.Summary)
+	}
+	{
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.ID))
 	}
 	{
@@ -177,5 +482,313 @@ func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstor
 		out.RawString(prefix)
 		out.Float64(float64(in.Value))
 	}
+	if in.UpdatedAt != 0 {
+		const prefix string = ",\"updatedAt\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.UpdatedAt))
+	}
+	out.RawByte('}')
+}
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage5(in *jlexer.Lexer, out *summaryItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "positive":
+			if in.IsNull() {
+				in.Skip()
+				out.Positive = nil
+			} else {
+				in.Delim('[')
+				if out.Positive == nil {
+					if !in.IsDelim(']') {
+						out.Positive = make([]int64, 0, 8)
+					} else {
+						out.Positive = []int64{}
+					}
+				} else {
+					out.Positive = (out.Positive)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 int64
+					v12 = int64(in.Int64())
+					out.Positive = append(out.Positive, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "negative":
+			if in.IsNull() {
+				in.Skip()
+				out.Negative = nil
+			} else {
+				in.Delim('[')
+				if out.Negative == nil {
+					if !in.IsDelim(']') {
+						out.Negative = make([]int64, 0, 8)
+					} else {
+						out.Negative = []int64{}
+					}
+				} else {
+					out.Negative = (out.Negative)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 int64
+					v13 = int64(in.Int64())
+					out.Negative = append(out.Negative, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "positiveOffset":
+			out.PositiveOffset = int(in.Int())
+		case "negativeOffset":
+			out.NegativeOffset = int(in.Int())
+		case "accuracy":
+			out.Accuracy = float64(in.Float64())
+		case "zeroCount":
+			out.ZeroCount = int64(in.Int64())
+		case "count":
+			out.Count = int64(in.Int64())
+		case "sum":
+			out.Sum = float64(in.Float64())
+		case "min":
+			out.Min = float64(in.Float64())
+		case "max":
+			out.Max = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage5(out *jwriter.Writer, in summaryItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Positive) != 0 {
+		const prefix string = ",\"positive\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v14, v15 := range in.Positive {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v15))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Negative) != 0 {
+		const prefix string = ",\"negative\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v16, v17 := range in.Negative {
+				if v16 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v17))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"positiveOffset\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.PositiveOffset))
+	}
+	{
+		const prefix string = ",\"negativeOffset\":"
+		out.RawString(prefix)
+		out.Int(int(in.NegativeOffset))
+	}
+	{
+		const prefix string = ",\"accuracy\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Accuracy))
+	}
+	{
+		const prefix string = ",\"zeroCount\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ZeroCount))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Count))
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Sum))
+	}
+	{
+		const prefix string = ",\"min\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Min))
+	}
+	{
+		const prefix string = ",\"max\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Max))
+	}
+	out.RawByte('}')
+}
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalInfrastructureMemstorage4(in *jlexer.Lexer, out *histogramItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "bounds":
+			if in.IsNull() {
+				in.Skip()
+				out.Bounds = nil
+			} else {
+				in.Delim('[')
+				if out.Bounds == nil {
+					if !in.IsDelim(']') {
+						out.Bounds = make([]float64, 0, 8)
+					} else {
+						out.Bounds = []float64{}
+					}
+				} else {
+					out.Bounds = (out.Bounds)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v18 float64
+					v18 = float64(in.Float64())
+					out.Bounds = append(out.Bounds, v18)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "counts":
+			if in.IsNull() {
+				in.Skip()
+				out.Counts = nil
+			} else {
+				in.Delim('[')
+				if out.Counts == nil {
+					if !in.IsDelim(']') {
+						out.Counts = make([]int64, 0, 8)
+					} else {
+						out.Counts = []int64{}
+					}
+				} else {
+					out.Counts = (out.Counts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 int64
+					v19 = int64(in.Int64())
+					out.Counts = append(out.Counts, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "count":
+			out.Count = int64(in.Int64())
+		case "sum":
+			out.Sum = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalInfrastructureMemstorage4(out *jwriter.Writer, in histogramItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bounds\":"
+		out.RawString(prefix[1:])
+		if in.Bounds == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Bounds {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				out.Float64(float64(v21))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"counts\":"
+		out.RawString(prefix)
+		if in.Counts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v22, v23 := range in.Counts {
+				if v22 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v23))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Count))
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Sum))
+	}
 	out.RawByte('}')
 }
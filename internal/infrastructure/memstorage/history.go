@@ -0,0 +1,120 @@
+package memstorage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// seriesKey ключ, однозначно определяющий метрику.
+type seriesKey struct {
+	id         string
+	labels     string // каноническое представление меток, см. models.Labels.Key
+	metricType models.MetricType
+}
+
+func newSeriesKey(metricType models.MetricType, id string, labels models.Labels) seriesKey {
+	return seriesKey{
+		id:         id,
+		labels:     labels.Key(),
+		metricType: metricType,
+	}
+}
+
+// appendSample добавляет значение метрики в историю шарда s.
+// Значения, вышедшие за время хранения, удаляются периодически,
+// см. PruneHistory, чтобы запись не копировала историю ряда.
+//
+// Вызывающая сторона должна удерживать блокировку шарда.
+func (storage *MemStorage) appendSample(s *shard, key seriesKey, sample models.MetricSample) {
+	if storage.historyRetention == 0 {
+		return
+	}
+
+	s.historyMx.Lock()
+	defer s.historyMx.Unlock()
+
+	sample.Timestamp = time.Now()
+	s.history[key] = append(s.history[key], sample)
+}
+
+// PruneHistory удаляет значения истории, полученные раньше now
+// за вычетом времени хранения, в том числе у метрик, которые
+// больше не обновляются. Шарды обрабатываются по очереди.
+//
+// Возвращает количество удалённых значений.
+func (storage *MemStorage) PruneHistory(_ context.Context, now time.Time) (int, error) {
+	if storage.historyRetention == 0 {
+		return 0, nil
+	}
+
+	cutoff := now.Add(-storage.historyRetention)
+
+	var pruned int
+	for _, s := range storage.shards {
+		s.historyMx.Lock()
+		for key, samples := range s.history {
+			samples, expired := pruneSamples(samples, cutoff)
+			if len(samples) == 0 {
+				delete(s.history, key)
+			} else {
+				s.history[key] = samples
+			}
+			pruned += expired
+		}
+		s.historyMx.Unlock()
+	}
+
+	return pruned, nil
+}
+
+// pruneSamples удаляет значения, полученные раньше cutoff.
+// Возвращает оставшиеся значения и количество удалённых.
+func pruneSamples(samples []models.MetricSample, cutoff time.Time) ([]models.MetricSample, int) {
+	// Значения добавляются в хронологическом порядке,
+	// поэтому устаревшие всегда находятся в начале.
+	expired := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].Timestamp.Before(cutoff)
+	})
+	if expired > 0 {
+		samples = slices.Delete(samples, 0, expired)
+	}
+
+	return samples, expired
+}
+
+// GetMetricHistory возвращает значения метрики с идентификатором id,
+// типом metricType и набором меток labels, полученные в промежутке [from, to].
+// Значения, вышедшие за время хранения, но ещё не удалённые, не возвращаются.
+// Если время хранения истории не задано, возвращается models.ErrHistoryDisabled.
+func (storage *MemStorage) GetMetricHistory(_ context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	if storage.historyRetention == 0 {
+		return nil, models.ErrHistoryDisabled
+	}
+
+	if cutoff := time.Now().Add(-storage.historyRetention); from.Before(cutoff) {
+		from = cutoff
+	}
+
+	s := storage.shardOf(id)
+
+	s.historyMx.RLock()
+	defer s.historyMx.RUnlock()
+
+	samples := s.history[newSeriesKey(metricType, id, labels)]
+
+	start := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].Timestamp.Before(from)
+	})
+	end := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Timestamp.After(to)
+	})
+	if start >= end {
+		return []models.MetricSample{}, nil
+	}
+
+	return slices.Clone(samples[start:end]), nil
+}
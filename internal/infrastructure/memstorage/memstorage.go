@@ -6,76 +6,264 @@ package memstorage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"hash/maphash"
+	"math"
 	"os"
 	"sync"
-
-	"github.com/mailru/easyjson"
+	"sync/atomic"
+	"time"
 
 	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
 )
 
+// MemStorageOptions параметры хранилища метрик.
+type MemStorageOptions struct {
+	// Path путь до файла для записи/чтения метрик.
+	Path string
+	// HistoryRetention время хранения истории значений метрик.
+	// Если равно нулю, история не сохраняется.
+	HistoryRetention time.Duration
+	// Generations количество сохраняемых предыдущих версий файла метрик
+	// (Path.1, Path.2 и т.д.), используемых при восстановлении,
+	// если более новые версии повреждены.
+	Generations int
+	// Restore нужно ли восстанавливать метрики из файла.
+	Restore bool
+	// Shards количество шардов, между которыми распределяются метрики.
+	// Если равно нулю, используется значение по умолчанию.
+	Shards int
+	// WAL нужно ли вести журнал упреждающей записи (файлы Path.wal.N).
+	// Каждая мутация метрик записывается в журнал до подтверждения,
+	// а при восстановлении журнал применяется поверх файла метрик.
+	WAL bool
+}
+
 // NewMemStorage создаёт новое хранилище метрик.
 // При необходимости, восстанавливает предыдущие знаениченя метрик.
-func NewMemStorage(path string, restore bool) (*MemStorage, error) {
+func NewMemStorage(opts MemStorageOptions) (*MemStorage, error) {
 	var err error
 
+	shards := opts.Shards
+	if shards <= 0 {
+		shards = defaultShards
+	}
+
 	storage := &MemStorage{
-		fileW:          &fileWriter{path: path},
-		gaugeMetrics:   make(map[string]float64),
-		counterMetrics: make(map[string]int64),
+		fileW:      &fileWriter{path: opts.Path, generations: opts.Generations},
+		shards:     newShards(shards),
+		silences:   make(map[string]models.Silence),
+		seed:       maphash.MakeSeed(),
+		walSegment: 1,
+	}
+
+	if opts.Restore {
+		err = storage.restore(opts.Path, opts.Generations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = storage.replayWAL(opts.Path, opts.Restore)
+	if err != nil {
+		return nil, err
 	}
 
-	if restore {
-		err = storage.restore(path)
+	if opts.WAL {
+		storage.wal, err = newWAL(opts.Path, storage.walSegment)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// История восстановленных значений не сохраняется,
+	// поэтому время хранения устанавливается после восстановления.
+	storage.historyRetention = opts.HistoryRetention
+
 	return storage, nil
 }
 
 // MemStorage структура, реализующая хранилище метрик.
 type MemStorage struct {
-	gaugeMetrics   map[string]float64
-	counterMetrics map[string]int64
-	fileW          *fileWriter
-	mx             sync.RWMutex
+	shards   []*shard
+	silences map[string]models.Silence
+	fileW    *fileWriter
+	wal      *wal
+	seed     maphash.Seed
+	// walSegment номер первого сегмента журнала,
+	// записи которого не вошли в файл метрик.
+	walSegment uint64
+	// snapshotCuts значения walSegment сохранённых поколений
+	// файла метрик, начиная с самого нового.
+	snapshotCuts     []uint64
+	historyRetention time.Duration
+	// silencesMx защищает заглушения и номер сегмента журнала.
+	silencesMx sync.RWMutex
+	saveMx     sync.Mutex
 }
 
-func (storage *MemStorage) restore(path string) error {
-	_, err := os.Stat(path)
-	if err != nil {
-		// Если файл не существует, то просто выходим.
+// restore восстанавливает метрики из самого нового корректного поколения
+// файла метрик. Повреждённые поколения (например, оборванные при аварийном
+// завершении или не прошедшие проверку контрольной суммы) пропускаются.
+func (storage *MemStorage) restore(path string, generations int) error {
+	var (
+		restored bool
+		errs     []error
+	)
+
+	for generation := 0; generation <= generations; generation++ {
+		snapshotPath := generationPath(path, generation)
+
+		snapshot, err := readSnapshot(snapshotPath)
 		if errors.Is(err, os.ErrNotExist) {
-			return nil
+			continue
+		}
+		if err == nil && !restored {
+			err = storage.load(snapshot)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore metrics from %q: %w", snapshotPath, err))
+			continue
 		}
 
-		return err
+		// Сегменты журнала хранятся, пока нужны
+		// для восстановления из любого поколения.
+		storage.snapshotCuts = append(storage.snapshotCuts, snapshot.WALSegment)
+
+		if !restored && len(errs) > 0 {
+			logger.Errorf("restored metrics from previous generation %q: %v", snapshotPath, errors.Join(errs...))
+		}
+		restored = true
 	}
 
-	rawMetrics, err := os.ReadFile(path)
-	if err != nil {
-		return err
+	if !restored {
+		// Если файлов нет, errs пуст и хранилище остаётся пустым.
+		return errors.Join(errs...)
 	}
 
-	metrics := new(metricsStruct)
-	err = easyjson.Unmarshal(rawMetrics, metrics)
+	return nil
+}
+
+// load заменяет значения метрик и заглушений содержимым файла метрик.
+// Если содержимое некорректно, хранилище не изменяется.
+//
+// Вызывается только при создании хранилища.
+func (storage *MemStorage) load(snapshot *metricsStruct) error {
+	shards := newShards(len(storage.shards))
+	silences := make(map[string]models.Silence)
+	now := time.Now()
+
+	for _, item := range snapshot.Metrics {
+		metric, err := item.toMetricInfo()
+		if err != nil {
+			return err
+		}
+
+		key := newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
+		s := shards[storage.shardIndex(metric.ID())]
+		// Метрики из файлов без времени обновления
+		// считаются обновлёнными сейчас.
+		if item.UpdatedAt != 0 {
+			s.touch(key, time.Unix(0, item.UpdatedAt))
+		} else {
+			s.touch(key, now)
+		}
+
+		switch metric.Type() {
+		case models.Gauge:
+			s.gaugeMetrics[key] = metric.GaugeValue()
+		case models.Counter:
+			counter := new(atomic.Int64)
+			counter.Store(metric.CounterValue())
+			s.counterMetrics[key] = counter
+		case models.Histogram:
+			s.histogramMetrics[key] = metric.HistogramValue()
+		case models.Summary:
+			s.summaryMetrics[key] = metric.SummaryValue()
+		}
+	}
+
+	for _, item := range snapshot.Silences {
+		silence := item.toSilence()
+
+		// Завершённое заглушение может иметь пустой промежуток времени.
+		if silence.EndsAt.After(silence.StartsAt) {
+			err := silence.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid silence %q: %v", silence.ID, err)
+			}
+		}
+
+		silences[silence.ID] = silence
+	}
+
+	storage.silencesMx.Lock()
+	defer storage.silencesMx.Unlock()
+
+	storage.shards = shards
+	storage.silences = silences
+	storage.walSegment = max(storage.walSegment, snapshot.WALSegment)
+
+	return nil
+}
+
+// replayWAL применяет поверх восстановленных значений записи
+// сегментов журнала, не вошедших в файл метрик. Сегменты, вошедшие
+// в файл, удаляются при следующем сохранении метрик.
+//
+// Если replay == false, все сегменты удаляются без применения.
+func (storage *MemStorage) replayWAL(path string, replay bool) error {
+	segments, err := listWALSegments(path)
 	if err != nil {
 		return err
 	}
 
-	storage.mx.Lock()
-	defer storage.mx.Unlock()
+	if !replay {
+		return removeWALSegments(path, math.MaxUint64)
+	}
+
+	for _, segment := range segments {
+		if segment < storage.walSegment {
+			continue
+		}
 
-	for _, metric := range metrics.Metrics {
-		switch metric.Type {
-		case string(models.Gauge):
-			storage.gaugeMetrics[metric.ID] = metric.Value
-		case string(models.Counter):
-			storage.counterMetrics[metric.ID] = metric.Delta
+		records, err := readWALSegment(walSegmentPath(path, segment))
+		if err != nil {
+			return err
 		}
+
+		for _, record := range records {
+			metrics := make([]models.MetricInfo, len(record.Metrics))
+			for i, item := range record.Metrics {
+				metrics[i], err = item.toMetricInfo()
+				if err != nil {
+					return fmt.Errorf("wal segment %d: %v", segment, err)
+				}
+			}
+
+			for _, series := range record.Deleted {
+				key := series.seriesKey()
+				storage.shardOf(key.id).deleteMetric(key)
+			}
+
+			// Журнал ещё не открыт, поэтому записи не дублируются в нём.
+			_, err = storage.updateMetrics(metrics)
+			if err != nil {
+				return fmt.Errorf("wal segment %d: %v", segment, err)
+			}
+
+			// Время обновления восстанавливается из записи,
+			// чтобы перезапуск не продлевал жизнь метрик.
+			if record.UpdatedAt != 0 {
+				for _, metric := range metrics {
+					key := newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
+					storage.shardOf(metric.ID()).touch(key, time.Unix(0, record.UpdatedAt))
+				}
+			}
+		}
+
+		storage.walSegment = segment + 1
 	}
 
 	return nil
@@ -89,4 +277,11 @@ func (storage *MemStorage) Destroy(ctx context.Context) {
 	}
 
 	storage.fileW.Wait()
+
+	if storage.wal != nil {
+		err = storage.wal.Close()
+		if err != nil {
+			logger.Errorf("failed to close wal: %v", err)
+		}
+	}
 }
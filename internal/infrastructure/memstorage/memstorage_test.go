@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -16,7 +17,7 @@ func TestMemStorage(t *testing.T) {
 
 	ctx := context.Background()
 
-	storage, err := memstorage.NewMemStorage("metrix.db", false)
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -29,7 +30,7 @@ func TestMemStorage(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for range 20 {
-				storage.UpdateGaugeMetric(ctx, "Alloc", 5)
+				storage.UpdateGaugeMetric(ctx, "Alloc", nil, 5)
 			}
 		}()
 
@@ -39,7 +40,7 @@ func TestMemStorage(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for range 20 {
-				storage.UpdateCounterMetric(ctx, "PollCount", 2)
+				storage.UpdateCounterMetric(ctx, "PollCount", nil, 2)
 			}
 		}()
 	}
@@ -52,17 +53,17 @@ func TestMemStorage(t *testing.T) {
 		metrics       []models.MetricInfo
 	)
 
-	gaugeMetric, err = storage.GetGaugeMetric(ctx, "Alloc")
+	gaugeMetric, err = storage.GetGaugeMetric(ctx, "Alloc", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	counterMetric, err = storage.GetCounterMetric(ctx, "PollCount")
+	counterMetric, err = storage.GetCounterMetric(ctx, "PollCount", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	metrics, err = storage.GetAllMetrics(ctx)
+	metrics, err = storage.GetAllMetrics(ctx, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -70,4 +71,285 @@ func TestMemStorage(t *testing.T) {
 	require.Len(t, metrics, 2)
 	require.Equal(t, float64(5), gaugeMetric)
 	require.Equal(t, int64(200), counterMetric)
+
+	// История не хранится, если время её хранения не задано.
+	_, err = storage.GetMetricHistory(ctx, models.Gauge, "Alloc", nil, time.Now().Add(-time.Hour), time.Now())
+	require.ErrorIs(t, err, models.ErrHistoryDisabled)
+}
+
+func TestMemStorage_History(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+		Path:             "metrix.db",
+		HistoryRetention: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	_, err = storage.UpdateGaugeMetric(ctx, "HeapAlloc", nil, 1)
+	require.NoError(t, err)
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 3)
+	require.NoError(t, err)
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("HeapAlloc", 2),
+		models.NewCounterMetric("PollCount", 4),
+	})
+	require.NoError(t, err)
+
+	samples, err := storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, float64(1), samples[0].GaugeValue)
+	require.Equal(t, float64(2), samples[1].GaugeValue)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Counter, "PollCount", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, int64(3), samples[0].CounterValue)
+	require.Equal(t, int64(4), samples[1].CounterValue)
+
+	// Значения за пределами запрошенного промежутка не возвращаются.
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	// Значения, вышедшие за время хранения, не возвращаются ещё до очистки.
+	time.Sleep(300 * time.Millisecond)
+
+	_, err = storage.UpdateGaugeMetric(ctx, "HeapAlloc", nil, 3)
+	require.NoError(t, err)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	require.Equal(t, float64(3), samples[0].GaugeValue)
+
+	// Устаревшие значения удаляются при периодической очистке истории.
+	pruned, err := storage.PruneHistory(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 4, pruned)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Counter, "PollCount", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	samples, err = storage.GetMetricHistory(ctx, models.Gauge, "HeapAlloc", nil, start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+}
+
+func TestMemStorage_Labels(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path})
+	require.NoError(t, err)
+
+	hostA := models.Labels{"host": "a", "service": "api"}
+	hostB := models.Labels{"host": "b", "service": "api"}
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization", 10).WithLabels(hostA),
+		models.NewGaugeMetric("CPUutilization", 20).WithLabels(hostB),
+		models.NewGaugeMetric("CPUutilization", 30),
+		models.NewCounterMetric("PollCount", 1).WithLabels(hostA),
+		models.NewCounterMetric("PollCount", 2).WithLabels(hostA),
+	})
+	require.NoError(t, err)
+
+	value, err := storage.GetGaugeMetric(ctx, "CPUutilization", hostB)
+	require.NoError(t, err)
+	require.Equal(t, float64(20), value)
+
+	value, err = storage.GetGaugeMetric(ctx, "CPUutilization", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(30), value)
+
+	_, err = storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	metrics, err := storage.GetAllMetrics(ctx, models.Labels{"host": "a"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization", 10).WithLabels(hostA),
+		models.NewCounterMetric("PollCount", 3).WithLabels(hostA),
+	}, metrics)
+
+	metrics, err = storage.GetAllMetrics(ctx, models.Labels{"service": "api"})
+	require.NoError(t, err)
+	require.Len(t, metrics, 3)
+
+	// Метки сохраняются в файл и восстанавливаются из него.
+	require.NoError(t, storage.SaveMetrics(ctx))
+
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true})
+	require.NoError(t, err)
+
+	restoredMetrics, err := restored.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+
+	metrics, err = storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, metrics, restoredMetrics)
+}
+
+func TestMemStorage_Histogram(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path})
+	require.NoError(t, err)
+
+	first, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+	second, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{0, 1, 1}, 1.4)
+	require.NoError(t, err)
+	mismatched, err := models.NewHistogramValue([]float64{1}, []int64{1, 0}, 0.5)
+	require.NoError(t, err)
+
+	_, err = storage.UpdateHistogramMetric(ctx, "Latency", nil, first)
+	require.NoError(t, err)
+
+	// Интервалы объединяются так же, как суммируются значения счётчиков.
+	value, err := storage.UpdateHistogramMetric(ctx, "Latency", nil, second)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3, 1}, value.Counts)
+	require.Equal(t, int64(5), value.Count)
+	require.InDelta(t, 2.3, value.Sum, 1e-9)
+
+	_, err = storage.UpdateHistogramMetric(ctx, "Latency", nil, mismatched)
+	require.ErrorIs(t, err, models.ErrHistogramBoundsMismatch)
+
+	// Батч с несовместимой гистограммой не применяется целиком.
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 1),
+		models.NewHistogramMetric("Latency", mismatched),
+	})
+	require.ErrorIs(t, err, models.ErrHistogramBoundsMismatch)
+
+	_, err = storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	// Гистограммы сохраняются в файл и восстанавливаются из него.
+	require.NoError(t, storage.SaveMetrics(ctx))
+
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true})
+	require.NoError(t, err)
+
+	restoredValue, err := restored.GetHistogramMetric(ctx, "Latency", nil)
+	require.NoError(t, err)
+	require.Equal(t, value, restoredValue)
+}
+
+func TestMemStorage_Summary(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path})
+	require.NoError(t, err)
+
+	newSummary := func(accuracy float64, values ...float64) models.SummaryValue {
+		value, summaryErr := models.NewSummaryValue(accuracy)
+		require.NoError(t, summaryErr)
+
+		for _, v := range values {
+			require.NoError(t, value.Add(v))
+		}
+
+		return value
+	}
+
+	// Скетчи, полученные от разных агентов, объединяются.
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewSummaryMetric("Latency", newSummary(0.01, 1, 2, 3)),
+		models.NewSummaryMetric("Latency", newSummary(0.01, 4, 5)),
+	})
+	require.NoError(t, err)
+
+	value, err := storage.UpdateSummaryMetric(ctx, "Latency", nil, newSummary(0.01, 100))
+	require.NoError(t, err)
+	require.Equal(t, int64(6), value.Count)
+	require.Equal(t, float64(1), value.Min)
+	require.Equal(t, float64(100), value.Max)
+	require.InEpsilon(t, 4, value.Quantile(0.6), 0.01)
+
+	_, err = storage.UpdateSummaryMetric(ctx, "Latency", nil, newSummary(0.05, 1))
+	require.ErrorIs(t, err, models.ErrSummaryAccuracyMismatch)
+
+	// Скетчи сохраняются в файл и восстанавливаются из него.
+	require.NoError(t, storage.SaveMetrics(ctx))
+
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true})
+	require.NoError(t, err)
+
+	restoredValue, err := restored.GetSummaryMetric(ctx, "Latency", nil)
+	require.NoError(t, err)
+	require.Equal(t, value, restoredValue)
+}
+
+func TestMemStorage_Silences(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+	start := time.Unix(1000, 0).UTC()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path})
+	require.NoError(t, err)
+
+	deploy := models.Silence{
+		StartsAt: start,
+		EndsAt:   start.Add(time.Hour),
+		Matchers: []models.LabelMatcher{{Name: models.MetricNameLabel, Value: "CPUutilization.*", Type: models.MatchRegexp}},
+		ID:       "deploy",
+		Comment:  "deploy",
+	}
+	maintenance := models.Silence{
+		StartsAt: start.Add(time.Hour),
+		EndsAt:   start.Add(2 * time.Hour),
+		ID:       "maintenance",
+		RuleName: "HighCPU",
+	}
+
+	require.NoError(t, storage.CreateSilence(ctx, maintenance))
+	require.NoError(t, storage.CreateSilence(ctx, deploy))
+	require.Error(t, storage.CreateSilence(ctx, deploy))
+
+	silences, err := storage.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.Silence{deploy, maintenance}, silences)
+
+	// Завершение действующего заглушения.
+	expired, err := storage.ExpireSilence(ctx, "deploy", start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, start.Add(time.Minute), expired.EndsAt)
+
+	_, err = storage.ExpireSilence(ctx, "deploy", start.Add(2*time.Minute))
+	require.ErrorIs(t, err, models.ErrSilenceExpired)
+
+	_, err = storage.ExpireSilence(ctx, "unknown", start)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	// Завершение ещё не начавшегося заглушения.
+	expired, err = storage.ExpireSilence(ctx, "maintenance", start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, expired.StartsAt, expired.EndsAt)
+
+	require.NoError(t, storage.SaveMetrics(ctx))
+
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true})
+	require.NoError(t, err)
+
+	restoredSilences, err := restored.GetSilences(ctx)
+	require.NoError(t, err)
+
+	silences, err = storage.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, restoredSilences, 2)
+	for i := range silences {
+		require.Equal(t, silences[i].ID, restoredSilences[i].ID)
+		require.True(t, silences[i].StartsAt.Equal(restoredSilences[i].StartsAt))
+		require.True(t, silences[i].EndsAt.Equal(restoredSilences[i].EndsAt))
+		require.Equal(t, silences[i].Matchers, restoredSilences[i].Matchers)
+	}
 }
@@ -2,52 +2,373 @@ package memstorage
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
 )
 
 // UpdateGaugeMetric обновляет текущее значение метрики типа Gauge
-// с идентификатором id, перезаписывая его значением value.
+// с идентификатором id и набором меток labels, перезаписывая его значением value.
 //
 // Возвращает обновлённое значение метрики.
-func (storage *MemStorage) UpdateGaugeMetric(_ context.Context, id string, value float64) (float64, error) {
-	storage.mx.Lock()
-	defer storage.mx.Unlock()
+func (storage *MemStorage) UpdateGaugeMetric(_ context.Context, id string, labels models.Labels, value float64) (float64, error) {
+	s := storage.shardOf(id)
 
-	storage.gaugeMetrics[id] = value
+	s.mx.Lock()
+	seq, err := storage.logMutation(models.NewGaugeMetric(id, value).WithLabels(labels))
+	if err == nil {
+		value = storage.updateGaugeMetric(s, newSeriesKey(models.Gauge, id, labels), value)
+	}
+	s.mx.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
 
-	return storage.gaugeMetrics[id], nil
+	return value, storage.syncWAL(seq)
 }
 
 // UpdateCounterMetric обновляет текущее значение метрики типа Counter
-// с идентификатором id, добавляя к нему значение value.
+// с идентификатором id и набором меток labels, добавляя к нему значение value.
+//
+// Существующая метрика обновляется атомарно под блокировкой шарда на чтение,
+// поэтому обновления одной метрики не ожидают друг друга.
+// Порядок их записей в журнале может не совпадать с порядком применения,
+// но результат от этого не зависит.
+//
+// Возвращает обновлённое значение метрики.
+func (storage *MemStorage) UpdateCounterMetric(_ context.Context, id string, labels models.Labels, value int64) (int64, error) {
+	var (
+		s      = storage.shardOf(id)
+		key    = newSeriesKey(models.Counter, id, labels)
+		metric = models.NewCounterMetric(id, value).WithLabels(labels)
+	)
+
+	s.mx.RLock()
+	counter, exists := s.counterMetrics[key]
+	if !exists {
+		s.mx.RUnlock()
+		return storage.createCounterMetric(s, key, metric)
+	}
+
+	seq, err := storage.logMutation(metric)
+	if err == nil {
+		value = counter.Add(value)
+		s.touch(key, time.Now())
+		storage.appendSample(s, key, models.MetricSample{CounterValue: metric.CounterValue()})
+	}
+	s.mx.RUnlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return value, storage.syncWAL(seq)
+}
+
+// createCounterMetric обновляет значение метрики типа Counter
+// под блокировкой шарда s на запись, создавая метрику при её отсутствии.
+func (storage *MemStorage) createCounterMetric(s *shard, key seriesKey, metric models.MetricInfo) (int64, error) {
+	var value int64
+
+	s.mx.Lock()
+	seq, err := storage.logMutation(metric)
+	if err == nil {
+		value = storage.updateCounterMetric(s, key, metric.CounterValue())
+	}
+	s.mx.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return value, storage.syncWAL(seq)
+}
+
+// UpdateHistogramMetric обновляет текущее значение метрики типа Histogram
+// с идентификатором id и набором меток labels, объединяя его со значением value.
 //
 // Возвращает обновлённое значение метрики.
-func (storage *MemStorage) UpdateCounterMetric(_ context.Context, id string, value int64) (int64, error) {
-	storage.mx.Lock()
-	defer storage.mx.Unlock()
+// История значений гистограмм не сохраняется.
+func (storage *MemStorage) UpdateHistogramMetric(_ context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error) {
+	s := storage.shardOf(id)
+
+	s.mx.Lock()
+
+	key := newSeriesKey(models.Histogram, id, labels)
+
+	merged, err := s.histogramMetrics[key].Merge(value)
+	if err != nil {
+		s.mx.Unlock()
+		return models.HistogramValue{}, err
+	}
+
+	seq, err := storage.logMutation(models.NewHistogramMetric(id, value).WithLabels(labels))
+	if err != nil {
+		s.mx.Unlock()
+		return models.HistogramValue{}, err
+	}
 
-	storage.counterMetrics[id] += value
+	s.histogramMetrics[key] = merged
+	s.touch(key, time.Now())
+	s.mx.Unlock()
 
-	return storage.counterMetrics[id], nil
+	return merged.Clone(), storage.syncWAL(seq)
+}
+
+// UpdateSummaryMetric обновляет текущее значение метрики типа Summary
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+//
+// Возвращает обновлённое значение метрики.
+// История значений скетчей не сохраняется.
+func (storage *MemStorage) UpdateSummaryMetric(_ context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error) {
+	s := storage.shardOf(id)
+
+	s.mx.Lock()
+
+	key := newSeriesKey(models.Summary, id, labels)
+
+	merged, err := s.summaryMetrics[key].Merge(value)
+	if err != nil {
+		s.mx.Unlock()
+		return models.SummaryValue{}, err
+	}
+
+	seq, err := storage.logMutation(models.NewSummaryMetric(id, value).WithLabels(labels))
+	if err != nil {
+		s.mx.Unlock()
+		return models.SummaryValue{}, err
+	}
+
+	s.summaryMetrics[key] = merged
+	s.touch(key, time.Now())
+	s.mx.Unlock()
+
+	return merged.Clone(), storage.syncWAL(seq)
 }
 
 // UpdateMetrics обновляет текущее значение метрик.
-func (storage *MemStorage) UpdateMetrics(_ context.Context, metric []models.MetricInfo) error {
-	storage.mx.Lock()
-	defer storage.mx.Unlock()
+//
+// Если какую-либо из гистограмм или скетчей невозможно объединить
+// с текущим значением, ни одна метрика не обновляется.
+func (storage *MemStorage) UpdateMetrics(_ context.Context, metrics []models.MetricInfo) error {
+	seq, err := storage.updateMetrics(metrics)
+	if err != nil {
+		return err
+	}
+
+	return storage.syncWAL(seq)
+}
+
+// updateMetrics обновляет текущее значение метрик и записывает
+// мутацию в журнал. Возвращает номер записи журнала.
+//
+// Шарды всех метрик блокируются на запись на время мутации.
+func (storage *MemStorage) updateMetrics(metrics []models.MetricInfo) (uint64, error) {
+	unlock := storage.lockShards(metrics)
+	defer unlock()
+
+	histograms, err := mergeValues(storage, metrics, models.Histogram,
+		func(s *shard) map[seriesKey]models.HistogramValue { return s.histogramMetrics },
+		models.MetricInfo.HistogramValue, models.HistogramValue.Merge)
+	if err != nil {
+		return 0, err
+	}
+
+	summaries, err := mergeValues(storage, metrics, models.Summary,
+		func(s *shard) map[seriesKey]models.SummaryValue { return s.summaryMetrics },
+		models.MetricInfo.SummaryValue, models.SummaryValue.Merge)
+	if err != nil {
+		return 0, err
+	}
+
+	seq, err := storage.logMutation(metrics...)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	for _, metric := range metrics {
+		s := storage.shardOf(metric.ID())
+		key := newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
 
-	for _, metric := range metric {
 		switch metric.Type() {
 		case models.Gauge:
-			storage.gaugeMetrics[metric.ID()] = metric.GaugeValue()
+			storage.updateGaugeMetric(s, key, metric.GaugeValue())
 		case models.Counter:
-			storage.counterMetrics[metric.ID()] += metric.CounterValue()
+			storage.updateCounterMetric(s, key, metric.CounterValue())
+		case models.Histogram:
+			s.histogramMetrics[key] = histograms[key]
+			s.touch(key, now)
+		case models.Summary:
+			s.summaryMetrics[key] = summaries[key]
+			s.touch(key, now)
 		default:
 			logger.Info("unknown metric type", logger.Field{Name: "type", Value: metric.Type()})
 		}
 	}
 
-	return nil
+	return seq, nil
+}
+
+// logMutation записывает мутацию в журнал упреждающей записи,
+// если он ведётся. Возвращает номер записи для syncWAL.
+//
+// Запись выполняется под блокировкой шардов метрик, чтобы порядок
+// записей журнала совпадал с порядком применения мутаций
+// к каждой из метрик, а сохранение метрик не разделяло их.
+// Вызывающая сторона должна удерживать блокировку шардов метрик.
+func (storage *MemStorage) logMutation(metrics ...models.MetricInfo) (uint64, error) {
+	if storage.wal == nil {
+		return 0, nil
+	}
+
+	return storage.wal.Append(metrics)
+}
+
+// logDeletion записывает удаление метрик keys в журнал
+// упреждающей записи, если он ведётся.
+//
+// Вызывающая сторона должна удерживать блокировку шардов метрик на запись.
+func (storage *MemStorage) logDeletion(keys ...seriesKey) (uint64, error) {
+	if storage.wal == nil {
+		return 0, nil
+	}
+
+	return storage.wal.AppendDeletion(keys)
+}
+
+// syncWAL ожидает сброса записи журнала с номером seq на диск.
+// Вызывается без блокировки хранилища, чтобы fsync
+// не задерживал остальные операции.
+func (storage *MemStorage) syncWAL(seq uint64) error {
+	if storage.wal == nil {
+		return nil
+	}
+
+	return storage.wal.Sync(seq)
+}
+
+// Вызывающая сторона должна удерживать блокировку шарда s на запись.
+func (storage *MemStorage) updateGaugeMetric(s *shard, key seriesKey, value float64) float64 {
+	s.gaugeMetrics[key] = value
+	s.touch(key, time.Now())
+	storage.appendSample(s, key, models.MetricSample{GaugeValue: value})
+
+	return value
+}
+
+// Вызывающая сторона должна удерживать блокировку шарда s на запись.
+func (storage *MemStorage) updateCounterMetric(s *shard, key seriesKey, value int64) int64 {
+	counter, exists := s.counterMetrics[key]
+	if !exists {
+		counter = new(atomic.Int64)
+		s.counterMetrics[key] = counter
+	}
+
+	s.touch(key, time.Now())
+	storage.appendSample(s, key, models.MetricSample{CounterValue: value})
+
+	return counter.Add(value)
+}
+
+// mergeValues объединяет значения метрик типа metricType из metrics
+// с текущими значениями шардов current, не изменяя хранилище.
+//
+// Вызывающая сторона должна удерживать блокировку шардов метрик.
+func mergeValues[T any](
+	storage *MemStorage,
+	metrics []models.MetricInfo,
+	metricType models.MetricType,
+	current func(*shard) map[seriesKey]T,
+	valueOf func(models.MetricInfo) T,
+	merge func(T, T) (T, error),
+) (map[seriesKey]T, error) {
+	merged := make(map[seriesKey]T)
+
+	for _, metric := range metrics {
+		if metric.Type() != metricType {
+			continue
+		}
+
+		key := newSeriesKey(metricType, metric.ID(), metric.Labels())
+
+		value, exists := merged[key]
+		if !exists {
+			value = current(storage.shardOf(metric.ID()))[key]
+		}
+
+		value, err := merge(value, valueOf(metric))
+		if err != nil {
+			return nil, err
+		}
+
+		merged[key] = value
+	}
+
+	return merged, nil
+}
+
+// DeleteMetric удаляет метрику типа metricType с идентификатором id
+// и набором меток labels вместе с её историей.
+func (storage *MemStorage) DeleteMetric(_ context.Context, metricType models.MetricType, id string, labels models.Labels) error {
+	s := storage.shardOf(id)
+	key := newSeriesKey(metricType, id, labels)
+
+	s.mx.Lock()
+	if !s.hasMetric(key) {
+		s.mx.Unlock()
+		return models.ErrNotFound
+	}
+
+	seq, err := storage.logDeletion(key)
+	if err == nil {
+		s.deleteMetric(key)
+	}
+	s.mx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return storage.syncWAL(seq)
+}
+
+// DeleteStaleMetrics удаляет метрики, последнее обновление которых
+// было раньше updatedBefore, вместе с их историей.
+// Шарды обрабатываются по очереди.
+//
+// Возвращает количество удалённых метрик.
+func (storage *MemStorage) DeleteStaleMetrics(_ context.Context, updatedBefore time.Time) (int, error) {
+	var (
+		err     error
+		seq     uint64
+		deleted int
+	)
+
+	for _, s := range storage.shards {
+		s.mx.Lock()
+		stale := s.staleMetrics(updatedBefore)
+		if len(stale) > 0 {
+			seq, err = storage.logDeletion(stale...)
+		}
+		if err == nil {
+			for _, key := range stale {
+				s.deleteMetric(key)
+			}
+		}
+		s.mx.Unlock()
+
+		if err != nil {
+			return deleted, err
+		}
+
+		deleted += len(stale)
+	}
+
+	// Запись с наибольшим номером сбрасывается вместе с предыдущими.
+	return deleted, storage.syncWAL(seq)
 }
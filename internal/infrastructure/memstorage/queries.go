@@ -3,15 +3,19 @@ package memstorage
 import (
 	"context"
 
+	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
 )
 
-// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id.
-func (storage *MemStorage) GetGaugeMetric(_ context.Context, id string) (float64, error) {
-	storage.mx.RLock()
-	defer storage.mx.RUnlock()
+// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id
+// и набору меток labels.
+func (storage *MemStorage) GetGaugeMetric(_ context.Context, id string, labels models.Labels) (float64, error) {
+	s := storage.shardOf(id)
 
-	value, exists := storage.gaugeMetrics[id]
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	value, exists := s.gaugeMetrics[newSeriesKey(models.Gauge, id, labels)]
 	if !exists {
 		return 0, models.ErrNotFound
 	}
@@ -19,12 +23,15 @@ func (storage *MemStorage) GetGaugeMetric(_ context.Context, id string) (float64
 	return value, nil
 }
 
-// GetCounterMetric возвращает метрику типа Counter по идентификатору id.
-func (storage *MemStorage) GetCounterMetric(_ context.Context, id string) (int64, error) {
-	storage.mx.RLock()
-	defer storage.mx.RUnlock()
+// GetCounterMetric возвращает метрику типа Counter по идентификатору id
+// и набору меток labels.
+func (storage *MemStorage) GetCounterMetric(_ context.Context, id string, labels models.Labels) (int64, error) {
+	s := storage.shardOf(id)
+
+	s.mx.RLock()
+	defer s.mx.RUnlock()
 
-	value, exists := storage.counterMetrics[id]
+	value, exists := s.counterValue(newSeriesKey(models.Counter, id, labels))
 	if !exists {
 		return 0, models.ErrNotFound
 	}
@@ -32,22 +39,99 @@ func (storage *MemStorage) GetCounterMetric(_ context.Context, id string) (int64
 	return value, nil
 }
 
-// GetAllMetrics возвращает все существующие метрики.
-func (storage *MemStorage) GetAllMetrics(_ context.Context) ([]models.MetricInfo, error) {
-	storage.mx.RLock()
-	defer storage.mx.RUnlock()
+// GetHistogramMetric возвращает метрику типа Histogram по идентификатору id
+// и набору меток labels.
+func (storage *MemStorage) GetHistogramMetric(_ context.Context, id string, labels models.Labels) (models.HistogramValue, error) {
+	s := storage.shardOf(id)
+
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	value, exists := s.histogramMetrics[newSeriesKey(models.Histogram, id, labels)]
+	if !exists {
+		return models.HistogramValue{}, models.ErrNotFound
+	}
+
+	return value.Clone(), nil
+}
+
+// GetSummaryMetric возвращает метрику типа Summary по идентификатору id
+// и набору меток labels.
+func (storage *MemStorage) GetSummaryMetric(_ context.Context, id string, labels models.Labels) (models.SummaryValue, error) {
+	s := storage.shardOf(id)
 
-	metrics := make([]models.MetricInfo, len(storage.gaugeMetrics)+len(storage.counterMetrics))
+	s.mx.RLock()
+	defer s.mx.RUnlock()
 
-	i := 0
-	for id, value := range storage.gaugeMetrics {
-		metrics[i] = models.NewGaugeMetric(id, value)
-		i++
+	value, exists := s.summaryMetrics[newSeriesKey(models.Summary, id, labels)]
+	if !exists {
+		return models.SummaryValue{}, models.ErrNotFound
 	}
-	for id, value := range storage.counterMetrics {
-		metrics[i] = models.NewCounterMetric(id, value)
-		i++
+
+	return value.Clone(), nil
+}
+
+// GetAllMetrics возвращает все существующие метрики,
+// содержащие метки из filter.
+//
+// Значения всех метрик согласованы: ни одна мутация
+// не применяется к хранилищу частично относительно результата.
+func (storage *MemStorage) GetAllMetrics(_ context.Context, filter models.Labels) ([]models.MetricInfo, error) {
+	unlock := storage.lockAllShards()
+	defer unlock()
+
+	return storage.allMetrics(filter), nil
+}
+
+// Вызывающая сторона должна удерживать блокировку всех шардов на запись.
+func (storage *MemStorage) allMetrics(filter models.Labels) []models.MetricInfo {
+	var size int
+	for _, s := range storage.shards {
+		size += len(s.gaugeMetrics) + len(s.counterMetrics) + len(s.histogramMetrics) + len(s.summaryMetrics)
+	}
+
+	metrics := make([]models.MetricInfo, 0, size)
+
+	for _, s := range storage.shards {
+		for key, value := range s.gaugeMetrics {
+			labels, ok := key.matchLabels(filter)
+			if ok {
+				metrics = append(metrics, models.NewGaugeMetric(key.id, value).WithLabels(labels))
+			}
+		}
+		for key, value := range s.counterMetrics {
+			labels, ok := key.matchLabels(filter)
+			if ok {
+				metrics = append(metrics, models.NewCounterMetric(key.id, value.Load()).WithLabels(labels))
+			}
+		}
+		for key, value := range s.histogramMetrics {
+			labels, ok := key.matchLabels(filter)
+			if ok {
+				metrics = append(metrics, models.NewHistogramMetric(key.id, value).WithLabels(labels))
+			}
+		}
+		for key, value := range s.summaryMetrics {
+			labels, ok := key.matchLabels(filter)
+			if ok {
+				metrics = append(metrics, models.NewSummaryMetric(key.id, value).WithLabels(labels))
+			}
+		}
+	}
+
+	return metrics
+}
+
+// matchLabels восстанавливает метки метрики и
+// проверяет их соответствие фильтру.
+func (key seriesKey) matchLabels(filter models.Labels) (models.Labels, bool) {
+	labels, err := models.ParseLabelsKey(key.labels)
+	if err != nil {
+		// Ключи формируются только через models.Labels.Key,
+		// поэтому попасть сюда невозможно.
+		logger.Errorf("failed to parse labels of metric %q: %v", key.id, err)
+		return nil, false
 	}
 
-	return metrics, nil
+	return labels, labels.Matches(filter)
 }
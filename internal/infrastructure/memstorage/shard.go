@@ -0,0 +1,197 @@
+package memstorage
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// defaultShards количество шардов хранилища по умолчанию.
+const defaultShards = 32
+
+// shard часть метрик хранилища со своей блокировкой.
+// Метрика попадает в шард по хешу идентификатора, поэтому
+// обновления разных метрик, как правило, не конкурируют за блокировку.
+//
+// Значения counter изменяются атомарно под блокировкой на чтение,
+// если метрика уже существует. Остальные мутации выполняются
+// под блокировкой на запись. Снимок всех метрик выполняется
+// под блокировкой на запись всех шардов, поэтому он согласован.
+type shard struct {
+	gaugeMetrics     map[seriesKey]float64
+	counterMetrics   map[seriesKey]*atomic.Int64
+	histogramMetrics map[seriesKey]models.HistogramValue
+	summaryMetrics   map[seriesKey]models.SummaryValue
+	history          map[seriesKey][]models.MetricSample
+	// updatedAt время последнего обновления метрик (в наносекундах).
+	updatedAt map[seriesKey]*atomic.Int64
+	mx        sync.RWMutex
+	// historyMx защищает историю, которая пополняется
+	// и при блокировке шарда на чтение.
+	historyMx sync.RWMutex
+}
+
+func newShard() *shard {
+	return &shard{
+		gaugeMetrics:     make(map[seriesKey]float64),
+		counterMetrics:   make(map[seriesKey]*atomic.Int64),
+		histogramMetrics: make(map[seriesKey]models.HistogramValue),
+		summaryMetrics:   make(map[seriesKey]models.SummaryValue),
+		history:          make(map[seriesKey][]models.MetricSample),
+		updatedAt:        make(map[seriesKey]*atomic.Int64),
+	}
+}
+
+// newShards создаёт n пустых шардов.
+func newShards(n int) []*shard {
+	shards := make([]*shard, n)
+	for i := range shards {
+		shards[i] = newShard()
+	}
+
+	return shards
+}
+
+// shardIndex возвращает номер шарда метрики с идентификатором id.
+func (storage *MemStorage) shardIndex(id string) int {
+	return int(maphash.String(storage.seed, id) % uint64(len(storage.shards)))
+}
+
+// shardOf возвращает шард метрики с идентификатором id.
+func (storage *MemStorage) shardOf(id string) *shard {
+	return storage.shards[storage.shardIndex(id)]
+}
+
+// lockShards блокирует на запись шарды метрик metrics
+// в порядке возрастания номеров, чтобы избежать взаимоблокировок.
+// Возвращает функцию для снятия блокировок.
+func (storage *MemStorage) lockShards(metrics []models.MetricInfo) func() {
+	indexes := make([]int, 0, len(metrics))
+	for _, metric := range metrics {
+		indexes = append(indexes, storage.shardIndex(metric.ID()))
+	}
+
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	for _, i := range indexes {
+		storage.shards[i].mx.Lock()
+	}
+
+	return func() {
+		for _, i := range indexes {
+			storage.shards[i].mx.Unlock()
+		}
+	}
+}
+
+// lockAllShards блокирует на запись все шарды.
+// Возвращает функцию для снятия блокировок.
+func (storage *MemStorage) lockAllShards() func() {
+	for _, s := range storage.shards {
+		s.mx.Lock()
+	}
+
+	return func() {
+		for _, s := range storage.shards {
+			s.mx.Unlock()
+		}
+	}
+}
+
+// counterValue возвращает значение метрики типа Counter.
+//
+// Вызывающая сторона должна удерживать блокировку шарда.
+func (s *shard) counterValue(key seriesKey) (int64, bool) {
+	counter, exists := s.counterMetrics[key]
+	if !exists {
+		return 0, false
+	}
+
+	return counter.Load(), true
+}
+
+// touch обновляет время последнего обновления метрики.
+//
+// Вызывающая сторона должна удерживать блокировку шарда на запись
+// или, если метрика уже существует, на чтение.
+func (s *shard) touch(key seriesKey, now time.Time) {
+	updatedAt, exists := s.updatedAt[key]
+	if !exists {
+		updatedAt = new(atomic.Int64)
+		s.updatedAt[key] = updatedAt
+	}
+
+	updatedAt.Store(now.UnixNano())
+}
+
+// metricsUpdatedAt возвращает время последнего обновления
+// метрик metrics (в наносекундах).
+//
+// Вызывающая сторона должна удерживать блокировку шардов метрик.
+func (storage *MemStorage) metricsUpdatedAt(metrics []models.MetricInfo) []int64 {
+	updatedAt := make([]int64, len(metrics))
+	for i, metric := range metrics {
+		key := newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
+
+		if ts, exists := storage.shardOf(metric.ID()).updatedAt[key]; exists {
+			updatedAt[i] = ts.Load()
+		}
+	}
+
+	return updatedAt
+}
+
+// hasMetric проверяет существование метрики.
+//
+// Вызывающая сторона должна удерживать блокировку шарда.
+func (s *shard) hasMetric(key seriesKey) bool {
+	var exists bool
+
+	switch key.metricType {
+	case models.Gauge:
+		_, exists = s.gaugeMetrics[key]
+	case models.Counter:
+		_, exists = s.counterMetrics[key]
+	case models.Histogram:
+		_, exists = s.histogramMetrics[key]
+	case models.Summary:
+		_, exists = s.summaryMetrics[key]
+	}
+
+	return exists
+}
+
+// deleteMetric удаляет метрику вместе с историей.
+//
+// Вызывающая сторона должна удерживать блокировку шарда на запись.
+func (s *shard) deleteMetric(key seriesKey) {
+	delete(s.gaugeMetrics, key)
+	delete(s.counterMetrics, key)
+	delete(s.histogramMetrics, key)
+	delete(s.summaryMetrics, key)
+	delete(s.updatedAt, key)
+
+	s.historyMx.Lock()
+	delete(s.history, key)
+	s.historyMx.Unlock()
+}
+
+// staleMetrics возвращает метрики, последнее обновление
+// которых было раньше updatedBefore.
+//
+// Вызывающая сторона должна удерживать блокировку шарда на запись.
+func (s *shard) staleMetrics(updatedBefore time.Time) []seriesKey {
+	var stale []seriesKey
+	for key, updatedAt := range s.updatedAt {
+		if updatedAt.Load() < updatedBefore.UnixNano() {
+			stale = append(stale, key)
+		}
+	}
+
+	return stale
+}
@@ -0,0 +1,136 @@
+package memstorage_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestMemStorage_ConsistentSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+		Path:   t.TempDir() + "/metrix.db",
+		Shards: 8,
+	})
+	require.NoError(t, err)
+
+	// Метрики пакета, скорее всего, попадают в разные шарды.
+	batch := make([]models.MetricInfo, 16)
+	for i := range batch {
+		batch[i] = models.NewCounterMetric("Requests"+strconv.Itoa(i), 1)
+	}
+
+	const writers = 4
+
+	var (
+		wg   sync.WaitGroup
+		done atomic.Bool
+	)
+
+	// Ошибки записи проверяются после завершения горутин,
+	// т.к. require нельзя вызывать вне горутины теста.
+	errs := make(chan error, writers)
+
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				err := storage.UpdateMetrics(ctx, batch)
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	// Пакет применяется целиком, поэтому в любом снимке
+	// значения всех метрик пакета совпадают.
+	for range 200 {
+		metrics, err := storage.GetAllMetrics(ctx, nil)
+		require.NoError(t, err)
+
+		for _, metric := range metrics {
+			require.Equal(t, metrics[0].CounterValue(), metric.CounterValue())
+		}
+	}
+
+	done.Store(true)
+	wg.Wait()
+
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func BenchmarkMemStorage_UpdateCounterMetric(b *testing.B) {
+	benchmarkShards(b, func(storage *memstorage.MemStorage, id string) error {
+		_, err := storage.UpdateCounterMetric(context.Background(), id, nil, 1)
+		return err
+	})
+}
+
+func BenchmarkMemStorage_UpdateGaugeMetric(b *testing.B) {
+	benchmarkShards(b, func(storage *memstorage.MemStorage, id string) error {
+		_, err := storage.UpdateGaugeMetric(context.Background(), id, nil, 1)
+		return err
+	})
+}
+
+// benchmarkShards измеряет параллельные обновления метрик, равномерно
+// распределённых между горутинами, при одном шарде и при шардах по умолчанию.
+func benchmarkShards(b *testing.B, update func(storage *memstorage.MemStorage, id string) error) {
+	const metricsCount = 1000
+
+	ids := make([]string, metricsCount)
+	for i := range ids {
+		ids[i] = "Metric" + strconv.Itoa(i)
+	}
+
+	benchmarks := []struct {
+		name   string
+		shards int
+	}{
+		{name: "один шард", shards: 1},
+		{name: "шарды по умолчанию", shards: 0},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+				Path:   b.TempDir() + "/metrix.db",
+				Shards: bb.shards,
+			})
+			require.NoError(b, err)
+
+			for _, id := range ids {
+				require.NoError(b, update(storage, id))
+			}
+
+			var next atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(next.Add(1)) * 97
+				for pb.Next() {
+					err := update(storage, ids[i%metricsCount])
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					i++
+				}
+			})
+		})
+	}
+}
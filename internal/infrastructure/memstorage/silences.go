@@ -0,0 +1,150 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// GetSilences возвращает все заглушения, упорядоченные по времени начала.
+func (storage *MemStorage) GetSilences(_ context.Context) ([]models.Silence, error) {
+	storage.silencesMx.RLock()
+	defer storage.silencesMx.RUnlock()
+
+	return storage.allSilences(), nil
+}
+
+// Вызывающая сторона должна удерживать блокировку silencesMx.
+func (storage *MemStorage) allSilences() []models.Silence {
+	silences := make([]models.Silence, 0, len(storage.silences))
+	for _, silence := range storage.silences {
+		silence.Matchers = slices.Clone(silence.Matchers)
+		silences = append(silences, silence)
+	}
+
+	slices.SortFunc(silences, func(a, b models.Silence) int {
+		if c := a.StartsAt.Compare(b.StartsAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return silences
+}
+
+// CreateSilence сохраняет новое заглушение.
+func (storage *MemStorage) CreateSilence(_ context.Context, silence models.Silence) error {
+	storage.silencesMx.Lock()
+	defer storage.silencesMx.Unlock()
+
+	if _, exists := storage.silences[silence.ID]; exists {
+		return fmt.Errorf("silence %q already exists", silence.ID)
+	}
+
+	silence.Matchers = slices.Clone(silence.Matchers)
+	storage.silences[silence.ID] = silence
+
+	return nil
+}
+
+// ExpireSilence завершает заглушение с идентификатором id в момент now.
+func (storage *MemStorage) ExpireSilence(_ context.Context, id string, now time.Time) (models.Silence, error) {
+	storage.silencesMx.Lock()
+	defer storage.silencesMx.Unlock()
+
+	silence, exists := storage.silences[id]
+	if !exists {
+		return models.Silence{}, models.ErrNotFound
+	}
+
+	err := silence.Expire(now)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	storage.silences[id] = silence
+	silence.Matchers = slices.Clone(silence.Matchers)
+
+	return silence, nil
+}
+
+type silenceItem struct {
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	Matchers  []silenceMatcher `json:"matchers,omitempty"`
+	ID        string           `json:"id"`
+	RuleName  string           `json:"ruleName,omitempty"`
+	Comment   string           `json:"comment,omitempty"`
+	CreatedBy string           `json:"createdBy,omitempty"`
+}
+
+type silenceMatcher struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+func newSilenceItem(silence models.Silence) silenceItem {
+	item := silenceItem{
+		StartsAt:  silence.StartsAt,
+		EndsAt:    silence.EndsAt,
+		Matchers:  make([]silenceMatcher, len(silence.Matchers)),
+		ID:        silence.ID,
+		RuleName:  silence.RuleName,
+		Comment:   silence.Comment,
+		CreatedBy: silence.CreatedBy,
+	}
+
+	for i, matcher := range silence.Matchers {
+		item.Matchers[i] = silenceMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  string(matcher.Type),
+		}
+	}
+
+	return item
+}
+
+func (item silenceItem) toSilence() models.Silence {
+	silence := models.Silence{
+		StartsAt:  item.StartsAt,
+		EndsAt:    item.EndsAt,
+		ID:        item.ID,
+		RuleName:  item.RuleName,
+		Comment:   item.Comment,
+		CreatedBy: item.CreatedBy,
+	}
+
+	for _, matcher := range item.Matchers {
+		silence.Matchers = append(silence.Matchers, models.LabelMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  models.MatchType(matcher.Type),
+		})
+	}
+
+	return silence
+}
+
+// DeleteExpiredSilences удаляет заглушения, завершившиеся раньше endedBefore.
+//
+// Возвращает количество удалённых заглушений.
+func (storage *MemStorage) DeleteExpiredSilences(_ context.Context, endedBefore time.Time) (int, error) {
+	storage.silencesMx.Lock()
+	defer storage.silencesMx.Unlock()
+
+	var deleted int
+	for id, silence := range storage.silences {
+		if silence.EndsAt.Before(endedBefore) {
+			delete(storage.silences, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,140 @@
+package memstorage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// checksumPrefix префикс строки с контрольной суммой,
+// завершающей файл метрик.
+const checksumPrefix = "sha256:"
+
+// errChecksumMismatch ошибка, возвращаемая, если содержимое
+// файла метрик не соответствует его контрольной сумме.
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// generationPath возвращает путь до поколения файла метрик path.
+// Поколение 0 - текущий файл, поколение N - N-й предыдущий.
+func generationPath(path string, generation int) string {
+	if generation == 0 {
+		return path
+	}
+
+	return path + "." + strconv.Itoa(generation)
+}
+
+// encodeSnapshot дописывает к содержимому файла метрик
+// строку с его контрольной суммой.
+func encodeSnapshot(data []byte) []byte {
+	sum := sha256.Sum256(data)
+
+	encoded := make([]byte, 0, len(data)+1+len(checksumPrefix)+hex.EncodedLen(len(sum)))
+	encoded = append(encoded, data...)
+	encoded = append(encoded, '\n')
+	encoded = append(encoded, checksumPrefix...)
+	encoded = hex.AppendEncode(encoded, sum[:])
+
+	return encoded
+}
+
+// decodeSnapshot проверяет контрольную сумму файла метрик
+// и возвращает его содержимое без неё. Файлы, записанные
+// до появления контрольных сумм, возвращаются как есть.
+func decodeSnapshot(encoded []byte) ([]byte, error) {
+	i := bytes.LastIndexByte(encoded, '\n')
+	if i < 0 || !bytes.HasPrefix(encoded[i+1:], []byte(checksumPrefix)) {
+		return encoded, nil
+	}
+
+	data := encoded[:i]
+
+	want, err := hex.DecodeString(string(encoded[i+1+len(checksumPrefix):]))
+	if err != nil {
+		return nil, errChecksumMismatch
+	}
+
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], want) {
+		return nil, errChecksumMismatch
+	}
+
+	return data, nil
+}
+
+// readSnapshot читает и проверяет файл метрик.
+func readSnapshot(path string) (*metricsStruct, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeSnapshot(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := new(metricsStruct)
+	err = easyjson.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// fileWriter записывает файл метрик атомарно: содержимое
+// записывается во временный файл, сбрасывается на диск
+// и переименовывается в path. Предыдущие generations
+// версий файла сохраняются как path.1, path.2 и т.д.
+type fileWriter struct {
+	path        string
+	generations int
+	mx          sync.Mutex
+	wg          sync.WaitGroup
+}
+
+func (w *fileWriter) Write(data []byte) error {
+	w.wg.Add(1)
+	w.mx.Lock()
+	defer func() {
+		w.mx.Unlock()
+		w.wg.Done()
+	}()
+
+	tmpPath := w.path + ".tmp"
+
+	err := tools.WriteFileSync(tmpPath, encodeSnapshot(data))
+	if err != nil {
+		return err
+	}
+
+	// Если завершение произойдёт между переименованиями,
+	// при восстановлении будет использовано предыдущее поколение.
+	for generation := w.generations; generation > 0; generation-- {
+		err = os.Rename(generationPath(w.path, generation-1), generationPath(w.path, generation))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	err = os.Rename(tmpPath, w.path)
+	if err != nil {
+		return err
+	}
+
+	return tools.SyncDir(filepath.Dir(w.path))
+}
+
+func (w *fileWriter) Wait() {
+	w.wg.Wait()
+}
@@ -0,0 +1,170 @@
+package memstorage_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestMemStorage_SnapshotGenerations(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// corrupt портит файлы поколений после трёх сохранений
+		// значений счётчика 1, 2 и 3.
+		corrupt func(t *testing.T, path string)
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "Корректный файл",
+			corrupt: func(*testing.T, string) {},
+			want:    3,
+		},
+		{
+			name: "Оборванная запись",
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, data[:len(data)/2], 0666))
+			},
+			want: 2,
+		},
+		{
+			name: "Несовпадение контрольной суммы",
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data = bytes.Replace(data, []byte(`"delta":3`), []byte(`"delta":9`), 1)
+				require.NoError(t, os.WriteFile(path, data, 0666))
+			},
+			want: 2,
+		},
+		{
+			name: "Отсутствующий текущий файл",
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.Remove(path))
+			},
+			want: 2,
+		},
+		{
+			name: "Файл без контрольной суммы",
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte(`{"metrics":[{"id":"PollCount","type":"counter","delta":7}]}`), 0666))
+			},
+			want: 7,
+		},
+		{
+			name: "Все поколения повреждены",
+			corrupt: func(t *testing.T, path string) {
+				for _, p := range []string{path, path + ".1", path + ".2"} {
+					require.NoError(t, os.WriteFile(p, []byte(`{"metrics":[`), 0666))
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/metrix.db"
+
+			storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Generations: 2})
+			require.NoError(t, err)
+
+			for range 3 {
+				_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 1)
+				require.NoError(t, err)
+				require.NoError(t, storage.SaveMetrics(ctx))
+			}
+
+			require.FileExists(t, path+".1")
+			require.FileExists(t, path+".2")
+			require.NoFileExists(t, path+".3")
+			require.NoFileExists(t, path+".tmp")
+
+			tt.corrupt(t, path)
+
+			restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true, Generations: 2})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			counter, err := restored.GetCounterMetric(ctx, "PollCount", nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, counter)
+		})
+	}
+}
+
+func TestMemStorage_SnapshotGenerationsWAL(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	opts := memstorage.MemStorageOptions{Path: path, Restore: true, Generations: 1, WAL: true}
+
+	storage, err := memstorage.NewMemStorage(opts)
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 1)
+		require.NoError(t, err)
+		require.NoError(t, storage.SaveMetrics(ctx))
+	}
+
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 1)
+	require.NoError(t, err)
+
+	// Текущий файл повреждён: значения восстанавливаются из предыдущего
+	// поколения и сегментов журнала, сохранённых для него.
+	require.NoError(t, os.WriteFile(path, []byte(`{"metrics":[`), 0666))
+
+	restored, err := memstorage.NewMemStorage(opts)
+	require.NoError(t, err)
+
+	counter, err := restored.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), counter)
+
+	restoredMetrics, err := restored.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []models.MetricInfo{models.NewCounterMetric("PollCount", 4)}, restoredMetrics)
+}
+
+func TestMemStorage_RestoreBaselineFile(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	// Файл в формате версий без меток и контрольной суммы.
+	data := `{"metrics":[` +
+		`{"id":"Alloc","type":"gauge","delta":0,"value":1.5},` +
+		`{"id":"HeapAlloc","type":"gauge","delta":0,"value":2.5},` +
+		`{"id":"PollCount","type":"counter","delta":7,"value":0},` +
+		`{"id":"RequestCount","type":"counter","delta":3,"value":0}]}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0666))
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true})
+	require.NoError(t, err)
+
+	metrics, err := storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 1.5),
+		models.NewGaugeMetric("HeapAlloc", 2.5),
+		models.NewCounterMetric("PollCount", 7),
+		models.NewCounterMetric("RequestCount", 3),
+	}, metrics)
+}
@@ -0,0 +1,309 @@
+package memstorage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// walRecord запись журнала упреждающей записи: метрики одной мутации.
+// Значения применяются так же, как в UpdateMetrics: gauge перезаписывается,
+// counter увеличивается, гистограммы и скетчи объединяются.
+// Метрики из Deleted удаляются вместе с историей.
+//
+//easyjson:json
+type walRecord struct {
+	Metrics []metricItem `json:"metrics"`
+	Deleted []walSeries  `json:"deleted,omitempty"`
+	// UpdatedAt время мутации (в наносекундах).
+	UpdatedAt int64 `json:"updatedAt,omitempty"`
+}
+
+// walSeries удалённая метрика.
+type walSeries struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Labels string `json:"labels,omitempty"` // каноническое представление меток, см. models.Labels.Key
+}
+
+func newWALSeries(key seriesKey) walSeries {
+	return walSeries{
+		ID:     key.id,
+		Type:   string(key.metricType),
+		Labels: key.labels,
+	}
+}
+
+func (series walSeries) seriesKey() seriesKey {
+	return seriesKey{
+		id:         series.ID,
+		labels:     series.Labels,
+		metricType: models.MetricType(series.Type),
+	}
+}
+
+// walSegmentPath возвращает путь до сегмента журнала с номером segment.
+func walSegmentPath(path string, segment uint64) string {
+	return path + ".wal." + strconv.FormatUint(segment, 10)
+}
+
+// listWALSegments возвращает номера существующих сегментов журнала
+// для файла метрик path в порядке возрастания.
+func listWALSegments(path string) ([]uint64, error) {
+	matches, err := filepath.Glob(path + ".wal.*")
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := path + ".wal."
+
+	var segments []uint64
+	for _, match := range matches {
+		segment, err := strconv.ParseUint(strings.TrimPrefix(match, prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	slices.Sort(segments)
+
+	return segments, nil
+}
+
+// removeWALSegments удаляет сегменты журнала для файла
+// метрик path с номерами меньше before.
+func removeWALSegments(path string, before uint64) error {
+	segments, err := listWALSegments(path)
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if segment >= before {
+			break
+		}
+
+		err = os.Remove(walSegmentPath(path, segment))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readWALSegment читает записи сегмента журнала. Запись, оборванная
+// при аварийном завершении (без перевода строки или с некорректным JSON),
+// и все последующие записи отбрасываются: подтверждение о них
+// не могло быть отправлено.
+func readWALSegment(path string) ([]walRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []walRecord
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			logger.Errorf("wal segment %q: dropping incomplete record", path)
+			break
+		}
+
+		var record walRecord
+		err = easyjson.Unmarshal(data[:end], &record)
+		if err != nil {
+			logger.Errorf("wal segment %q: dropping corrupted records: %v", path, err)
+			break
+		}
+
+		records = append(records, record)
+		data = data[end+1:]
+	}
+
+	return records, nil
+}
+
+// newWAL создаёт журнал упреждающей записи,
+// начиная новый сегмент с номером segment.
+func newWAL(path string, segment uint64) (*wal, error) {
+	w := &wal{path: path}
+
+	err := w.openSegment(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// wal журнал упреждающей записи MemStorage.
+//
+// Журнал состоит из сегментов path.wal.N. При каждом сохранении метрик
+// начинается новый сегмент, а сегменты, вошедшие в сохранённый файл,
+// удаляются (см. MemStorage.SaveMetrics). Вызовы fsync группируются: запись, ожидающая синхронизации,
+// подтверждается ближайшим fsync, выполненным любым из писателей.
+type wal struct {
+	file    *os.File
+	path    string
+	segment uint64
+	// written количество записанных записей.
+	written uint64
+	// synced количество записей, сброшенных на диск.
+	synced uint64
+	// size размер текущего сегмента.
+	size int64
+	// mx защищает запись в текущий сегмент.
+	mx sync.Mutex
+	// syncMx сериализует вызовы fsync.
+	syncMx sync.Mutex
+}
+
+// Append записывает мутацию метрик в журнал без ожидания fsync.
+// Возвращает номер записи для последующего вызова Sync.
+func (w *wal) Append(metrics []models.MetricInfo) (uint64, error) {
+	record := walRecord{
+		Metrics:   make([]metricItem, len(metrics)),
+		UpdatedAt: time.Now().UnixNano(),
+	}
+	for i, metric := range metrics {
+		record.Metrics[i] = newMetricItem(metric)
+	}
+
+	return w.append(record)
+}
+
+// AppendDeletion записывает удаление метрик в журнал без ожидания fsync.
+// Возвращает номер записи для последующего вызова Sync.
+func (w *wal) AppendDeletion(keys []seriesKey) (uint64, error) {
+	record := walRecord{Deleted: make([]walSeries, len(keys))}
+	for i, key := range keys {
+		record.Deleted[i] = newWALSeries(key)
+	}
+
+	return w.append(record)
+}
+
+func (w *wal) append(record walRecord) (uint64, error) {
+	data, err := easyjson.Marshal(record)
+	if err != nil {
+		return 0, err
+	}
+	data = append(data, '\n')
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	_, err = w.file.Write(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write wal record: %w", err)
+	}
+
+	w.size += int64(len(data))
+	w.written++
+
+	return w.written, nil
+}
+
+// Sync ожидает, пока запись с номером seq будет сброшена на диск.
+func (w *wal) Sync(seq uint64) error {
+	w.syncMx.Lock()
+	defer w.syncMx.Unlock()
+
+	// Запись уже сброшена fsync другого писателя.
+	if w.synced >= seq {
+		return nil
+	}
+
+	w.mx.Lock()
+	file, written := w.file, w.written
+	w.mx.Unlock()
+
+	err := file.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync wal: %w", err)
+	}
+
+	w.synced = written
+
+	return nil
+}
+
+// Rotate начинает новый сегмент, если текущий не пуст.
+// Возвращает номер сегмента, с которого начнутся последующие записи.
+//
+// Вызывающая сторона должна гарантировать отсутствие
+// одновременных вызовов Append.
+func (w *wal) Rotate() (uint64, error) {
+	w.syncMx.Lock()
+	defer w.syncMx.Unlock()
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if w.size == 0 {
+		return w.segment, nil
+	}
+
+	err := w.file.Sync()
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync wal: %w", err)
+	}
+	w.synced = w.written
+
+	err = w.file.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	err = w.openSegment(w.segment + 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return w.segment, nil
+}
+
+// Close сбрасывает записи на диск и закрывает текущий сегмент.
+func (w *wal) Close() error {
+	w.syncMx.Lock()
+	defer w.syncMx.Unlock()
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	err := w.file.Sync()
+	if err != nil {
+		w.file.Close()
+		return err
+	}
+
+	return w.file.Close()
+}
+
+// Вызывающая сторона должна удерживать блокировку mx.
+func (w *wal) openSegment(segment uint64) error {
+	file, err := os.OpenFile(walSegmentPath(w.path, segment), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	w.file = file
+	w.segment = segment
+	w.size = 0
+
+	return nil
+}
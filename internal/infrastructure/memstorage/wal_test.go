@@ -0,0 +1,177 @@
+package memstorage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestMemStorage_WAL(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+	hostA := models.Labels{"host": "a"}
+
+	histogram, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+
+	open := func(restore bool) *memstorage.MemStorage {
+		storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+			Path:    path,
+			Restore: restore,
+			WAL:     true,
+		})
+		require.NoError(t, err)
+		return storage
+	}
+
+	segments := func() []string {
+		matches, err := filepath.Glob(path + ".wal.*")
+		require.NoError(t, err)
+		return matches
+	}
+
+	storage := open(true)
+
+	_, err = storage.UpdateGaugeMetric(ctx, "Alloc", nil, 1)
+	require.NoError(t, err)
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", hostA, 2)
+	require.NoError(t, err)
+	_, err = storage.UpdateHistogramMetric(ctx, "Latency", nil, histogram)
+	require.NoError(t, err)
+	require.NoError(t, storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 5),
+		models.NewCounterMetric("PollCount", 3).WithLabels(hostA),
+	}))
+
+	// Аварийное завершение без сохранения метрик:
+	// значения восстанавливаются из журнала.
+	storage = open(true)
+
+	want := []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 5),
+		models.NewCounterMetric("PollCount", 5).WithLabels(hostA),
+		models.NewHistogramMetric("Latency", histogram),
+	}
+
+	metrics, err := storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, want, metrics)
+
+	// После сохранения метрик вошедшие в файл сегменты удаляются,
+	// а последующие мутации записываются в новый сегмент.
+	require.NoError(t, storage.SaveMetrics(ctx))
+	require.Len(t, segments(), 1)
+
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", hostA, 10)
+	require.NoError(t, err)
+
+	// Значения из файла и журнала не применяются повторно.
+	storage = open(true)
+
+	counter, err := storage.GetCounterMetric(ctx, "PollCount", hostA)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), counter)
+
+	storage = open(true)
+
+	counter, err = storage.GetCounterMetric(ctx, "PollCount", hostA)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), counter)
+
+	// Удаления также записываются в журнал.
+	require.NoError(t, storage.DeleteMetric(ctx, models.Counter, "PollCount", hostA))
+
+	storage = open(true)
+
+	_, err = storage.GetCounterMetric(ctx, "PollCount", hostA)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	deleted, err := storage.DeleteStaleMetrics(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	storage = open(true)
+
+	metrics, err = storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+
+	// Без восстановления журнал очищается.
+	storage = open(false)
+	require.Len(t, segments(), 1)
+
+	metrics, err = storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+}
+
+func TestMemStorage_WALTornRecord(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, WAL: true})
+	require.NoError(t, err)
+
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 2)
+	require.NoError(t, err)
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 3)
+	require.NoError(t, err)
+
+	// Запись, оборванная при аварийном завершении.
+	file, err := os.OpenFile(path+".wal.1", os.O_WRONLY|os.O_APPEND, 0666)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"metrics":[{"id":"PollCount","type":"counter","delta":`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true, WAL: true})
+	require.NoError(t, err)
+
+	counter, err := restored.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), counter)
+
+	// Новые записи не попадают в сегмент с оборванной записью.
+	_, err = restored.UpdateCounterMetric(ctx, "PollCount", nil, 1)
+	require.NoError(t, err)
+
+	restored, err = memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true, WAL: true})
+	require.NoError(t, err)
+
+	counter, err = restored.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), counter)
+}
+
+func TestMemStorage_WALUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/metrix.db"
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, WAL: true})
+	require.NoError(t, err)
+
+	_, err = storage.UpdateGaugeMetric(ctx, "Alloc", nil, 1)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	updatedBefore := time.Now()
+
+	// Аварийное завершение без сохранения метрик: время обновления
+	// восстанавливается из журнала, а не считается равным моменту запуска.
+	restored, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: path, Restore: true, WAL: true})
+	require.NoError(t, err)
+
+	deleted, err := restored.DeleteStaleMetrics(ctx, updatedBefore)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+}
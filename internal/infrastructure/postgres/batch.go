@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// Запросы пакетного обновления. Значения пакета передаются массивами
+// и разворачиваются через unnest, поэтому пакет любого размера
+// записывается одним запросом. Метки передаются как text[]
+// и приводятся к JSONB на стороне БД.
+const (
+	upsertGaugesQuery = "INSERT INTO metrics (id, type, labels_key, labels, gauge_value, counter_value)" +
+		" SELECT batch.id, $1::smallint, batch.labels_key, batch.labels::jsonb, batch.value, 0" +
+		" FROM unnest($2::text[], $3::text[], $4::text[], $5::double precision[])" +
+		" AS batch(id, labels_key, labels, value)" +
+		" ON CONFLICT (id, type, labels_key) DO UPDATE SET gauge_value = EXCLUDED.gauge_value, updated_at = now();"
+
+	upsertCountersQuery = "INSERT INTO metrics (id, type, labels_key, labels, gauge_value, counter_value)" +
+		" SELECT batch.id, $1::smallint, batch.labels_key, batch.labels::jsonb, 0, batch.delta" +
+		" FROM unnest($2::text[], $3::text[], $4::text[], $5::bigint[])" +
+		" AS batch(id, labels_key, labels, delta)" +
+		" ON CONFLICT (id, type, labels_key) DO UPDATE SET counter_value = metrics.counter_value + EXCLUDED.counter_value, updated_at = now();"
+
+	insertSamplesQuery = "INSERT INTO metrics_history (id, type, labels_key, ts, gauge_value, counter_value)" +
+		" SELECT batch.id, batch.type, batch.labels_key, $1::timestamptz, batch.gauge_value, batch.counter_value" +
+		" FROM unnest($2::text[], $3::smallint[], $4::text[], $5::double precision[], $6::bigint[])" +
+		" AS batch(id, type, labels_key, gauge_value, counter_value);"
+)
+
+// batchStatements подготовленные запросы пакетного обновления.
+type batchStatements struct {
+	upsertGauges   *sql.Stmt
+	upsertCounters *sql.Stmt
+	insertSamples  *sql.Stmt
+}
+
+// prepareBatchStatements подготавливает запросы пакетного обновления.
+func prepareBatchStatements(ctx context.Context, db *sql.DB) (*batchStatements, error) {
+	var (
+		err   error
+		stmts batchStatements
+	)
+
+	for _, prepare := range []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		{stmt: &stmts.upsertGauges, query: upsertGaugesQuery},
+		{stmt: &stmts.upsertCounters, query: upsertCountersQuery},
+		{stmt: &stmts.insertSamples, query: insertSamplesQuery},
+	} {
+		*prepare.stmt, err = db.PrepareContext(ctx, prepare.query)
+		if err != nil {
+			stmts.Close()
+			return nil, err
+		}
+	}
+
+	return &stmts, nil
+}
+
+// Close освобождает подготовленные запросы.
+func (stmts *batchStatements) Close() error {
+	var errs []error
+
+	for _, stmt := range []*sql.Stmt{stmts.upsertGauges, stmts.upsertCounters, stmts.insertSamples} {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// metricsBatch метрики пакета, объединённые по идентификатору,
+// типу и меткам: для gauge остаётся последнее значение,
+// приращения counter суммируются, гистограммы и скетчи объединяются.
+//
+// Метрики каждого типа упорядочены по ключу, чтобы параллельные
+// транзакции блокировали строки в одном порядке и не взаимоблокировались.
+type metricsBatch struct {
+	gauges     []models.MetricInfo
+	counters   []models.MetricInfo
+	histograms []models.MetricInfo
+	summaries  []models.MetricInfo
+}
+
+func newMetricsBatch(metrics []models.MetricInfo) (metricsBatch, error) {
+	type batchKey struct {
+		id         string
+		labels     string
+		metricType models.MetricType
+	}
+
+	var batch metricsBatch
+
+	merged := make(map[batchKey]models.MetricInfo, len(metrics))
+
+	for _, metric := range metrics {
+		key := batchKey{id: metric.ID(), labels: metric.Labels().Key(), metricType: metric.Type()}
+
+		current, exists := merged[key]
+		if !exists {
+			merged[key] = metric
+			continue
+		}
+
+		switch metric.Type() {
+		case models.Gauge:
+			merged[key] = metric
+		case models.Counter:
+			merged[key] = models.NewCounterMetric(metric.ID(), current.CounterValue()+metric.CounterValue()).
+				WithLabels(metric.Labels())
+		case models.Histogram:
+			value, err := current.HistogramValue().Merge(metric.HistogramValue())
+			if err != nil {
+				return metricsBatch{}, err
+			}
+
+			merged[key] = models.NewHistogramMetric(metric.ID(), value).WithLabels(metric.Labels())
+		case models.Summary:
+			value, err := current.SummaryValue().Merge(metric.SummaryValue())
+			if err != nil {
+				return metricsBatch{}, err
+			}
+
+			merged[key] = models.NewSummaryMetric(metric.ID(), value).WithLabels(metric.Labels())
+		}
+	}
+
+	for _, metric := range merged {
+		switch metric.Type() {
+		case models.Gauge:
+			batch.gauges = append(batch.gauges, metric)
+		case models.Counter:
+			batch.counters = append(batch.counters, metric)
+		case models.Histogram:
+			batch.histograms = append(batch.histograms, metric)
+		case models.Summary:
+			batch.summaries = append(batch.summaries, metric)
+		}
+	}
+
+	for _, metrics := range [][]models.MetricInfo{batch.gauges, batch.counters, batch.histograms, batch.summaries} {
+		slices.SortFunc(metrics, func(a, b models.MetricInfo) int {
+			if c := strings.Compare(a.ID(), b.ID()); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Labels().Key(), b.Labels().Key())
+		})
+	}
+
+	return batch, nil
+}
+
+// batchColumns значения метрик пакета, разложенные по колонкам.
+type batchColumns struct {
+	ids           []string
+	labelsKeys    []string
+	labels        []string
+	types         []int16
+	gaugeValues   []float64
+	counterValues []int64
+}
+
+func newBatchColumns(metrics ...[]models.MetricInfo) (batchColumns, error) {
+	var columns batchColumns
+
+	for _, group := range metrics {
+		for _, metric := range group {
+			labels, err := serializeLabels(metric.Labels())
+			if err != nil {
+				return batchColumns{}, err
+			}
+
+			columns.ids = append(columns.ids, metric.ID())
+			columns.labelsKeys = append(columns.labelsKeys, metric.Labels().Key())
+			columns.labels = append(columns.labels, labels)
+			columns.types = append(columns.types, int16(serializeMetricType(metric.Type())))
+			columns.gaugeValues = append(columns.gaugeValues, metric.GaugeValue())
+			columns.counterValues = append(columns.counterValues, metric.CounterValue())
+		}
+	}
+
+	return columns, nil
+}
+
+// updateBatch записывает пакет метрик в транзакции tx.
+//
+// Gauge и counter записываются одним запросом на тип, гистограммы
+// и скетчи объединяются с сохранёнными значениями по одной.
+func (client *PostgresClient) updateBatch(ctx context.Context, tx *sql.Tx, batch metricsBatch) error {
+	if len(batch.gauges) > 0 {
+		gauges, err := newBatchColumns(batch.gauges)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.StmtContext(ctx, client.stmts.upsertGauges).ExecContext(ctx,
+			serializeMetricType(models.Gauge), gauges.ids, gauges.labelsKeys, gauges.labels, gauges.gaugeValues)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(batch.counters) > 0 {
+		counters, err := newBatchColumns(batch.counters)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.StmtContext(ctx, client.stmts.upsertCounters).ExecContext(ctx,
+			serializeMetricType(models.Counter), counters.ids, counters.labelsKeys, counters.labels, counters.counterValues)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, metric := range batch.histograms {
+		_, err := client.updateHistogramMetric(ctx, tx, metric)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, metric := range batch.summaries {
+		_, err := client.updateSummaryMetric(ctx, tx, metric)
+		if err != nil {
+			return err
+		}
+	}
+
+	return client.appendSamples(ctx, tx, batch.gauges, batch.counters)
+}
+
+// appendSamples сохраняет значения пакета в историю.
+// Для метрик типа Counter сохраняется суммарное приращение в пакете.
+func (client *PostgresClient) appendSamples(ctx context.Context, tx *sql.Tx, metrics ...[]models.MetricInfo) error {
+	if client.historyRetention == 0 {
+		return nil
+	}
+
+	samples, err := newBatchColumns(metrics...)
+	if err != nil {
+		return err
+	}
+
+	if len(samples.ids) == 0 {
+		return nil
+	}
+
+	_, err = tx.StmtContext(ctx, client.stmts.insertSamples).ExecContext(ctx,
+		time.Now(), samples.ids, samples.types, samples.labelsKeys, samples.gaugeValues, samples.counterValues)
+
+	return err
+}
@@ -0,0 +1,87 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/postgres"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// benchmarkBatchSize размер пакета, отправляемого агентами.
+const benchmarkBatchSize = 1000
+
+// newBenchmarkBatch создаёт пакет из benchmarkBatchSize метрик:
+// поровну gauge и counter с различными метками.
+func newBenchmarkBatch() []models.MetricInfo {
+	metrics := make([]models.MetricInfo, 0, benchmarkBatchSize)
+	for i := range benchmarkBatchSize / 2 {
+		labels := models.Labels{"host": fmt.Sprintf("host-%d", i%10)}
+
+		metrics = append(metrics,
+			models.NewGaugeMetric(fmt.Sprintf("BenchGauge%d", i), float64(i)).WithLabels(labels),
+			models.NewCounterMetric(fmt.Sprintf("BenchCounter%d", i), 1).WithLabels(labels),
+		)
+	}
+
+	return metrics
+}
+
+// BenchmarkPostgresClient_UpdateMetrics сравнивает пакетную запись
+// с записью по одной метрике, требующей отдельного запроса на каждую.
+func BenchmarkPostgresClient_UpdateMetrics(b *testing.B) {
+	connStr := os.Getenv(testDatabaseEnv)
+	if connStr == "" {
+		b.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	logger.Init(true)
+
+	ctx := context.Background()
+
+	client, err := postgres.NewPostgresClient(ctx, postgres.PostgresClientOptions{
+		ConnStr:          connStr,
+		HistoryRetention: time.Hour,
+	})
+	require.NoError(b, err)
+	defer client.Destroy(ctx)
+
+	metrics := newBenchmarkBatch()
+
+	b.Run("пакет", func(b *testing.B) {
+		for range b.N {
+			err := client.UpdateMetrics(ctx, metrics)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.ReportMetric(float64(b.N*len(metrics))/b.Elapsed().Seconds(), "metrics/s")
+	})
+
+	b.Run("по одной метрике", func(b *testing.B) {
+		for range b.N {
+			for _, metric := range metrics {
+				var err error
+
+				switch metric.Type() {
+				case models.Gauge:
+					_, err = client.UpdateGaugeMetric(ctx, metric.ID(), metric.Labels(), metric.GaugeValue())
+				case models.Counter:
+					_, err = client.UpdateCounterMetric(ctx, metric.ID(), metric.Labels(), metric.CounterValue())
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+
+		b.ReportMetric(float64(b.N*len(metrics))/b.Elapsed().Seconds(), "metrics/s")
+	})
+}
@@ -0,0 +1,341 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLockID ключ рекомендательной блокировки, под которой
+// применяются миграции. Блокировка не даёт нескольким экземплярам
+// сервера одновременно изменять схему БД.
+const migrationsLockID = 0x6d6574726978 // "metrix"
+
+// migrationFileRe шаблон названия файла миграции: <версия>_<название>.<up|down>.sql.
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration версионированная миграция схемы БД.
+type Migration struct {
+	Name    string
+	up      string
+	down    string
+	Version int64
+}
+
+// MigrationStatus состояние миграции в БД.
+type MigrationStatus struct {
+	// AppliedAt момент применения миграции.
+	// Нулевой, если миграция не применена.
+	AppliedAt time.Time
+	Migration
+	Applied bool
+}
+
+// Migrations возвращает встроенные миграции, упорядоченные по версии.
+func Migrations() ([]Migration, error) {
+	return loadMigrations(migrationsFS, "migrations")
+}
+
+// loadMigrations читает миграции из каталога dir.
+// Каждая миграция должна содержать файлы применения и отката.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.up = string(data)
+		} else {
+			migration.down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	return migrations, nil
+}
+
+// NewMigrator создаёт мигратор схемы БД по строке подключения connStr.
+func NewMigrator(connStr string) (*Migrator, error) {
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
+func newMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		retrier:    tools.DefaulRetrier,
+		migrations: migrations,
+	}, nil
+}
+
+// Migrator применяет и откатывает миграции схемы БД.
+//
+// Применённые миграции учитываются в таблице schema_migrations.
+// Каждая миграция выполняется в отдельной транзакции, а весь процесс -
+// под рекомендательной блокировкой migrationsLockID.
+type Migrator struct {
+	db         *sql.DB
+	retrier    *tools.Retrier
+	migrations []Migration
+}
+
+// Close закрывает соединение с БД.
+func (migrator *Migrator) Close() {
+	migrator.db.Close()
+}
+
+// Up применяет все неприменённые миграции в порядке версий.
+// Возвращает применённые миграции.
+func (migrator *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := migrator.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrator.migrations {
+			if _, exists := versions[migration.Version]; exists {
+				continue
+			}
+
+			err = applyMigration(ctx, conn, migration.up,
+				"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, now());",
+				migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций.
+// Возвращает откаченные миграции.
+func (migrator *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := migrator.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range slices.Backward(migrator.migrations) {
+			if len(reverted) == steps {
+				break
+			}
+
+			if _, exists := versions[migration.Version]; !exists {
+				continue
+			}
+
+			err = applyMigration(ctx, conn, migration.down,
+				"DELETE FROM schema_migrations WHERE version = $1;",
+				migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status возвращает состояние всех встроенных миграций.
+func (migrator *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := migrator.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]MigrationStatus, len(migrator.migrations))
+		for i, migration := range migrator.migrations {
+			appliedAt, applied := versions[migration.Version]
+			statuses[i] = MigrationStatus{
+				AppliedAt: appliedAt,
+				Migration: migration,
+				Applied:   applied,
+			}
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock выполняет f на выделенном соединении под рекомендательной
+// блокировкой migrationsLockID, предварительно создав таблицу schema_migrations.
+func (migrator *Migrator) withLock(ctx context.Context, f func(conn *sql.Conn) error) error {
+	var (
+		err  error
+		conn *sql.Conn
+	)
+
+	// Повторяем только установку соединения: миграции
+	// выполняются в транзакциях и могут не быть идемпотентными.
+	migrator.retrier.Exec(func() bool {
+		conn, err = migrator.db.Conn(ctx)
+		if err != nil {
+			return shouldRetry(err)
+		}
+
+		err = conn.PingContext(ctx)
+		if err != nil {
+			conn.Close()
+		}
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return convertError(err)
+	}
+	defer conn.Close()
+
+	// Сессионная блокировка принадлежит соединению,
+	// поэтому все запросы выполняются через conn.
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1);", migrationsLockID)
+	if err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1);", migrationsLockID)
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations ("+
+		"version BIGINT PRIMARY KEY,"+
+		"name TEXT NOT NULL,"+
+		"applied_at TIMESTAMPTZ NOT NULL"+
+		");")
+	if err != nil {
+		return err
+	}
+
+	return f(conn)
+}
+
+// appliedVersions возвращает версии применённых миграций и моменты их применения.
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
+
+// applyMigration выполняет скрипт миграции и запрос учёта
+// в schema_migrations в одной транзакции.
+func applyMigration(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, record, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS metrics (
+    id VARCHAR(50) NOT NULL,
+    type SMALLINT NOT NULL,
+    labels_key TEXT NOT NULL DEFAULT '',
+    labels JSONB NOT NULL DEFAULT '{}',
+    gauge_value DOUBLE PRECISION NOT NULL,
+    counter_value BIGINT NOT NULL,
+    histogram JSONB,
+    summary JSONB,
+    PRIMARY KEY (id, type, labels_key)
+);
+
+-- Таблицы, созданные до появления миграций, могут не содержать
+-- новых колонок, а их первичный ключ (id, type) необходимо расширить.
+ALTER TABLE metrics
+    ADD COLUMN IF NOT EXISTS labels_key TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS histogram JSONB,
+    ADD COLUMN IF NOT EXISTS summary JSONB;
+
+DO $$ BEGIN
+    IF (SELECT count(*) FROM information_schema.key_column_usage
+        WHERE table_name = 'metrics' AND constraint_name = 'metrics_pkey') = 2 THEN
+        ALTER TABLE metrics DROP CONSTRAINT metrics_pkey;
+        ALTER TABLE metrics ADD PRIMARY KEY (id, type, labels_key);
+    END IF;
+END $$;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS metrics_history (
+    id VARCHAR(50) NOT NULL,
+    type SMALLINT NOT NULL,
+    labels_key TEXT NOT NULL DEFAULT '',
+    ts TIMESTAMPTZ NOT NULL,
+    gauge_value DOUBLE PRECISION NOT NULL,
+    counter_value BIGINT NOT NULL
+);
+
+ALTER TABLE metrics_history
+    ADD COLUMN IF NOT EXISTS labels_key TEXT NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS metrics_history_id_type_ts_idx;
+
+CREATE INDEX IF NOT EXISTS metrics_history_id_type_labels_ts_idx
+    ON metrics_history (id, type, labels_key, ts);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS silences (
+    id TEXT PRIMARY KEY,
+    rule_name TEXT NOT NULL DEFAULT '',
+    matchers JSONB NOT NULL DEFAULT '[]',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,4 @@
+-- Откат завершится ошибкой, если в таблицах есть
+-- идентификаторы длиннее 50 символов.
+ALTER TABLE metrics_history ALTER COLUMN id TYPE VARCHAR(50);
+ALTER TABLE metrics ALTER COLUMN id TYPE VARCHAR(50);
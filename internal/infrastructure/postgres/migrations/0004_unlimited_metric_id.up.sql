@@ -0,0 +1,4 @@
+-- Длина идентификатора метрики не ограничивается при приёме,
+-- поэтому ограничение VARCHAR(50) снимается.
+ALTER TABLE metrics ALTER COLUMN id TYPE TEXT;
+ALTER TABLE metrics_history ALTER COLUMN id TYPE TEXT;
@@ -0,0 +1,6 @@
+-- Время последнего обновления метрики используется
+-- для удаления рядов, которые давно не обновлялись.
+ALTER TABLE metrics
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS metrics_updated_at_idx ON metrics (updated_at);
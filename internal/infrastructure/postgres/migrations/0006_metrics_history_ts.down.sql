@@ -0,0 +1 @@
+DROP INDEX IF EXISTS metrics_history_ts_idx;
@@ -0,0 +1,3 @@
+-- Значения истории, вышедшие за время хранения,
+-- удаляются периодически одним запросом по времени.
+CREATE INDEX IF NOT EXISTS metrics_history_ts_idx ON metrics_history (ts);
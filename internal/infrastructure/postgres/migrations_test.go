@@ -0,0 +1,70 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/postgres"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := postgres.Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	// Версии идут подряд, начиная с единицы.
+	for i, migration := range migrations {
+		require.Equal(t, int64(i+1), migration.Version)
+		require.NotEmpty(t, migration.Name)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	connStr := os.Getenv(testDatabaseEnv)
+	if connStr == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	ctx := context.Background()
+
+	migrations, err := postgres.Migrations()
+	require.NoError(t, err)
+
+	migrator, err := postgres.NewMigrator(connStr)
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// Повторное применение ничего не делает.
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	reverted, err := migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, reverted, 2)
+	require.Equal(t, migrations[len(migrations)-1].Version, reverted[0].Version)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(migrations))
+	for i, status := range statuses {
+		require.Equal(t, i < len(migrations)-2, status.Applied, status.Name)
+	}
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.True(t, status.Applied, status.Name)
+		require.False(t, status.AppliedAt.IsZero())
+	}
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
@@ -14,17 +16,17 @@ func (client *PostgresClient) SaveMetrics(ctx context.Context) error {
 }
 
 // UpdateGaugeMetric обновляет текущее значение метрики типа Gauge
-// с идентификатором id, перезаписывая его значением value.
+// с идентификатором id и набором меток labels, перезаписывая его значением value.
 //
 // Возвращает обновлённое значение метрики.
-func (client *PostgresClient) UpdateGaugeMetric(ctx context.Context, id string, value float64) (float64, error) {
+func (client *PostgresClient) UpdateGaugeMetric(ctx context.Context, id string, labels models.Labels, value float64) (float64, error) {
 	var (
 		err    error
 		metric models.MetricInfo
 	)
 
 	client.retrier.Exec(func() bool {
-		metric, err = client.updateMetric(ctx, models.NewGaugeMetric(id, value))
+		metric, err = client.updateMetricInTx(ctx, models.NewGaugeMetric(id, value).WithLabels(labels))
 		return shouldRetry(err)
 	})
 
@@ -32,47 +34,89 @@ func (client *PostgresClient) UpdateGaugeMetric(ctx context.Context, id string,
 }
 
 // UpdateCounterMetric обновляет текущее значение метрики типа Counter
-// с идентификатором id, добавляя к нему значение value.
+// с идентификатором id и набором меток labels, добавляя к нему значение value.
 //
 // Возвращает обновлённое значение метрики.
-func (client *PostgresClient) UpdateCounterMetric(ctx context.Context, id string, value int64) (int64, error) {
+func (client *PostgresClient) UpdateCounterMetric(ctx context.Context, id string, labels models.Labels, value int64) (int64, error) {
 	var (
 		err    error
 		metric models.MetricInfo
 	)
 
 	client.retrier.Exec(func() bool {
-		metric, err = client.updateMetric(ctx, models.NewCounterMetric(id, value))
+		metric, err = client.updateMetricInTx(ctx, models.NewCounterMetric(id, value).WithLabels(labels))
 		return shouldRetry(err)
 	})
 
 	return metric.CounterValue(), err
 }
 
-// UpdateMetrics обновляет текущее значение метрик.
-// Используется батчевое обновление через транзакцию.
+// UpdateHistogramMetric обновляет текущее значение метрики типа Histogram
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+//
+// Возвращает обновлённое значение метрики.
+// История значений гистограмм не сохраняется.
+func (client *PostgresClient) UpdateHistogramMetric(ctx context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error) {
+	var (
+		err    error
+		metric models.MetricInfo
+	)
+
+	client.retrier.Exec(func() bool {
+		metric, err = client.updateMetricInTx(ctx, models.NewHistogramMetric(id, value).WithLabels(labels))
+		return shouldRetry(err)
+	})
+
+	return metric.HistogramValue(), err
+}
+
+// UpdateSummaryMetric обновляет текущее значение метрики типа Summary
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+//
+// Возвращает обновлённое значение метрики.
+// История значений скетчей не сохраняется.
+func (client *PostgresClient) UpdateSummaryMetric(ctx context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error) {
+	var (
+		err    error
+		metric models.MetricInfo
+	)
+
+	client.retrier.Exec(func() bool {
+		metric, err = client.updateMetricInTx(ctx, models.NewSummaryMetric(id, value).WithLabels(labels))
+		return shouldRetry(err)
+	})
+
+	return metric.SummaryValue(), err
+}
+
+// UpdateMetrics обновляет текущее значение метрик в одной транзакции.
+//
+// Метрики с одинаковыми идентификатором, типом и метками предварительно
+// объединяются, после чего gauge и counter записываются одним запросом
+// на тип. Если какую-либо из гистограмм или скетчей невозможно объединить
+// с текущим значением, ни одна метрика не обновляется.
 func (client *PostgresClient) UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error {
 	if len(metrics) == 0 {
 		return nil
 	}
 
-	var (
-		err error
-		tx  *sql.Tx
-	)
+	batch, err := newMetricsBatch(metrics)
+	if err != nil {
+		return err
+	}
 
 	client.retrier.Exec(func() bool {
+		var tx *sql.Tx
+
 		tx, err = client.db.BeginTx(ctx, nil)
 		if err != nil {
 			return shouldRetry(err)
 		}
+		defer tx.Rollback()
 
-		for _, metric := range metrics {
-			_, err = client.updateMetric(ctx, metric)
-			if err != nil {
-				tx.Rollback()
-				return shouldRetry(err)
-			}
+		err = client.updateBatch(ctx, tx, batch)
+		if err != nil {
+			return shouldRetry(err)
 		}
 
 		err = tx.Commit()
@@ -82,10 +126,35 @@ func (client *PostgresClient) UpdateMetrics(ctx context.Context, metrics []model
 	return convertError(err)
 }
 
-// updateMetric обновляет текущее значение метрики с идентификатором id.
+// updateMetricInTx обновляет текущее значение метрики в отдельной транзакции.
 //
 // Возвращает обновлённую структуру метрики.
-func (client *PostgresClient) updateMetric(ctx context.Context, metric models.MetricInfo) (models.MetricInfo, error) {
+func (client *PostgresClient) updateMetricInTx(ctx context.Context, metric models.MetricInfo) (models.MetricInfo, error) {
+	tx, err := client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+	defer tx.Rollback()
+
+	newMetric, err := client.updateMetric(ctx, tx, metric)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	return newMetric, tx.Commit()
+}
+
+// updateMetric обновляет текущее значение метрики в транзакции tx.
+//
+// Возвращает обновлённую структуру метрики.
+func (client *PostgresClient) updateMetric(ctx context.Context, tx *sql.Tx, metric models.MetricInfo) (models.MetricInfo, error) {
+	switch metric.Type() {
+	case models.Histogram:
+		return client.updateHistogramMetric(ctx, tx, metric)
+	case models.Summary:
+		return client.updateSummaryMetric(ctx, tx, metric)
+	}
+
 	var (
 		err       error
 		newMetric models.MetricInfo
@@ -96,36 +165,281 @@ func (client *PostgresClient) updateMetric(ctx context.Context, metric models.Me
 
 		switch metric.Type() {
 		case models.Gauge:
-			expression = " DO UPDATE SET gauge_value = $3"
+			expression = " DO UPDATE SET gauge_value = $5, updated_at = now()"
 		case models.Counter:
-			expression = " DO UPDATE SET counter_value = metrics.counter_value + $4"
+			expression = " DO UPDATE SET counter_value = metrics.counter_value + $6, updated_at = now()"
 		}
 
 		return expression
 	}
 
-	row := client.db.QueryRowContext(ctx, "INSERT INTO metrics (id, type, gauge_value, counter_value)"+
-		" VALUES ($1, $2, $3, $4)"+
-		" ON CONFLICT (id, type)"+
+	labels, err := serializeLabels(metric.Labels())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	row := tx.QueryRowContext(ctx, "INSERT INTO metrics (id, type, labels_key, labels, gauge_value, counter_value)"+
+		" VALUES ($1, $2, $3, $4::jsonb, $5, $6)"+
+		" ON CONFLICT (id, type, labels_key)"+
 		getOnConflictExpression()+
-		" RETURNING counter_value;",
+		" RETURNING gauge_value, counter_value;",
 		metric.ID(),
 		serializeMetricType(metric.Type()),
+		metric.Labels().Key(),
+		labels,
 		metric.GaugeValue(),
 		metric.CounterValue())
 
+	var (
+		gaugeValue   float64
+		counterValue int64
+	)
+
+	err = row.Scan(&gaugeValue, &counterValue)
+
 	switch metric.Type() {
 	case models.Gauge:
-		var newValue float64
-		err = row.Scan(&newValue)
-		newMetric = models.NewGaugeMetric(metric.ID(), newValue)
+		newMetric = models.NewGaugeMetric(metric.ID(), gaugeValue).WithLabels(metric.Labels())
 	case models.Counter:
-		var newValue int64
-		err = row.Scan(&newValue)
-		newMetric = models.NewCounterMetric(metric.ID(), newValue)
+		newMetric = models.NewCounterMetric(metric.ID(), counterValue).WithLabels(metric.Labels())
 	default:
 		logger.Info("unknown metric type", logger.Field{Name: "type", Value: metric.Type()})
 	}
 
+	if err == nil {
+		err = client.appendSample(ctx, tx, metric)
+	}
+
 	return newMetric, convertError(err)
 }
+
+// histogramMergeExpression объединяет гистограмму из запроса с сохранённой,
+// складывая количество значений в интервалах. Обновление выполняется
+// только при совпадении границ интервалов.
+const histogramMergeExpression = " DO UPDATE SET histogram = jsonb_build_object(" +
+	"'bounds', metrics.histogram->'bounds'," +
+	" 'counts', (SELECT jsonb_agg(cur.value::bigint + inc.value::bigint ORDER BY cur.ord)" +
+	" FROM jsonb_array_elements_text(metrics.histogram->'counts') WITH ORDINALITY AS cur(value, ord)" +
+	" JOIN jsonb_array_elements_text(EXCLUDED.histogram->'counts') WITH ORDINALITY AS inc(value, ord)" +
+	" ON cur.ord = inc.ord)," +
+	" 'count', (metrics.histogram->>'count')::bigint + (EXCLUDED.histogram->>'count')::bigint," +
+	" 'sum', (metrics.histogram->>'sum')::double precision + (EXCLUDED.histogram->>'sum')::double precision)," +
+	" updated_at = now()" +
+	" WHERE metrics.histogram->'bounds' = EXCLUDED.histogram->'bounds'"
+
+// updateHistogramMetric обновляет текущее значение метрики типа Histogram.
+//
+// Возвращает обновлённую структуру метрики.
+func (client *PostgresClient) updateHistogramMetric(ctx context.Context, tx *sql.Tx, metric models.MetricInfo) (models.MetricInfo, error) {
+	labels, err := serializeLabels(metric.Labels())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	value, err := serializeHistogram(metric.HistogramValue())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	var rawValue []byte
+
+	err = tx.QueryRowContext(ctx, "INSERT INTO metrics (id, type, labels_key, labels, gauge_value, counter_value, histogram)"+
+		" VALUES ($1, $2, $3, $4::jsonb, 0, 0, $5::jsonb)"+
+		" ON CONFLICT (id, type, labels_key)"+
+		histogramMergeExpression+
+		" RETURNING histogram;",
+		metric.ID(),
+		serializeMetricType(models.Histogram),
+		metric.Labels().Key(),
+		labels,
+		value).Scan(&rawValue)
+	if err != nil {
+		// Строка не возвращается, если границы интервалов не совпали.
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.MetricInfo{}, models.ErrHistogramBoundsMismatch
+		}
+
+		return models.MetricInfo{}, err
+	}
+
+	newValue, err := deserializeHistogram(rawValue)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	return models.NewHistogramMetric(metric.ID(), newValue).WithLabels(metric.Labels()), nil
+}
+
+// updateSummaryMetric обновляет текущее значение метрики типа Summary.
+//
+// Объединение скетчей выполняется на стороне приложения, поэтому
+// строка метрики создаётся заранее и блокируется до конца транзакции tx.
+//
+// Возвращает обновлённую структуру метрики.
+func (client *PostgresClient) updateSummaryMetric(ctx context.Context, tx *sql.Tx, metric models.MetricInfo) (models.MetricInfo, error) {
+	labels, err := serializeLabels(metric.Labels())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO metrics (id, type, labels_key, labels, gauge_value, counter_value)"+
+		" VALUES ($1, $2, $3, $4::jsonb, 0, 0)"+
+		" ON CONFLICT (id, type, labels_key) DO NOTHING;",
+		metric.ID(),
+		serializeMetricType(models.Summary),
+		metric.Labels().Key(),
+		labels)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	var rawValue []byte
+
+	err = tx.QueryRowContext(ctx, "SELECT summary FROM metrics"+
+		" WHERE id = $1 AND type = $2 AND labels_key = $3"+
+		" FOR UPDATE;",
+		metric.ID(),
+		serializeMetricType(models.Summary),
+		metric.Labels().Key()).Scan(&rawValue)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	// Только что созданная строка не содержит значения.
+	var current models.SummaryValue
+	if rawValue != nil {
+		current, err = deserializeSummary(rawValue)
+		if err != nil {
+			return models.MetricInfo{}, err
+		}
+	}
+
+	newValue, err := current.Merge(metric.SummaryValue())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	value, err := serializeSummary(newValue)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE metrics SET summary = $4::jsonb, updated_at = now()"+
+		" WHERE id = $1 AND type = $2 AND labels_key = $3;",
+		metric.ID(),
+		serializeMetricType(models.Summary),
+		metric.Labels().Key(),
+		value)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	return models.NewSummaryMetric(metric.ID(), newValue).WithLabels(metric.Labels()), nil
+}
+
+// appendSample сохраняет значение метрики в историю. Значения,
+// вышедшие за время хранения, удаляются периодически, см. PruneHistory.
+func (client *PostgresClient) appendSample(ctx context.Context, tx *sql.Tx, metric models.MetricInfo) error {
+	if client.historyRetention == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO metrics_history (id, type, labels_key, ts, gauge_value, counter_value)"+
+		" VALUES ($1, $2, $3, $4, $5, $6);",
+		metric.ID(),
+		serializeMetricType(metric.Type()),
+		metric.Labels().Key(),
+		time.Now(),
+		metric.GaugeValue(),
+		metric.CounterValue())
+
+	return err
+}
+
+// deleteMetricsQuery возвращает запрос, удаляющий метрики, удовлетворяющие
+// условию condition, вместе с их историей. Запрос возвращает количество
+// удалённых метрик.
+func deleteMetricsQuery(condition string) string {
+	return "WITH deleted AS (DELETE FROM metrics WHERE " + condition +
+		" RETURNING id, type, labels_key)," +
+		" history AS (DELETE FROM metrics_history USING deleted" +
+		" WHERE metrics_history.id = deleted.id AND metrics_history.type = deleted.type" +
+		" AND metrics_history.labels_key = deleted.labels_key)" +
+		" SELECT count(*) FROM deleted;"
+}
+
+// DeleteMetric удаляет метрику типа metricType с идентификатором id
+// и набором меток labels вместе с её историей.
+func (client *PostgresClient) DeleteMetric(ctx context.Context, metricType models.MetricType, id string, labels models.Labels) error {
+	var (
+		err     error
+		deleted int
+	)
+
+	client.retrier.Exec(func() bool {
+		err = client.db.QueryRowContext(ctx, deleteMetricsQuery("id = $1 AND type = $2 AND labels_key = $3"),
+			id,
+			serializeMetricType(metricType),
+			labels.Key()).Scan(&deleted)
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	if deleted == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteStaleMetrics удаляет метрики, последнее обновление которых
+// было раньше updatedBefore, вместе с их историей.
+//
+// Возвращает количество удалённых метрик.
+func (client *PostgresClient) DeleteStaleMetrics(ctx context.Context, updatedBefore time.Time) (int, error) {
+	var (
+		err     error
+		deleted int
+	)
+
+	client.retrier.Exec(func() bool {
+		err = client.db.QueryRowContext(ctx, deleteMetricsQuery("updated_at < $1"), updatedBefore).Scan(&deleted)
+		return shouldRetry(err)
+	})
+
+	return deleted, convertError(err)
+}
+
+// PruneHistory удаляет значения истории, полученные раньше now
+// за вычетом времени хранения, одним запросом для всех метрик.
+//
+// Возвращает количество удалённых значений.
+func (client *PostgresClient) PruneHistory(ctx context.Context, now time.Time) (int, error) {
+	if client.historyRetention == 0 {
+		return 0, nil
+	}
+
+	var (
+		err    error
+		result sql.Result
+		pruned int64
+	)
+
+	client.retrier.Exec(func() bool {
+		result, err = client.db.ExecContext(ctx, "DELETE FROM metrics_history WHERE ts < $1;",
+			now.Add(-client.historyRetention))
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+
+	pruned, err = result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(pruned), nil
+}
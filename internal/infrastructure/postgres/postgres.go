@@ -5,28 +5,48 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 
+	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
 	"github.com/xantinium/metrix/internal/tools"
 )
 
+// PostgresClientOptions параметры клиента для работы с PostgreSQL.
+type PostgresClientOptions struct {
+	// ConnStr строка подключения к БД.
+	ConnStr string
+	// HistoryRetention время хранения истории значений метрик.
+	// Если равно нулю, история не сохраняется.
+	HistoryRetention time.Duration
+}
+
 // NewPostgresClient создаёт новый клиент для работы с PostgreSQL.
-func NewPostgresClient(ctx context.Context, connStr string) (*PostgresClient, error) {
-	db, err := sql.Open("pgx", connStr)
+func NewPostgresClient(ctx context.Context, opts PostgresClientOptions) (*PostgresClient, error) {
+	db, err := sql.Open("pgx", opts.ConnStr)
 	if err != nil {
 		return nil, err
 	}
 
 	client := &PostgresClient{
-		db:      db,
-		retrier: tools.DefaulRetrier,
+		db:               db,
+		retrier:          tools.DefaulRetrier,
+		historyRetention: opts.HistoryRetention,
+	}
+
+	err = client.migrate(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	err = client.initTables(ctx)
+	// Запросы подготавливаются после миграций,
+	// так как зависят от схемы таблиц.
+	client.stmts, err = prepareBatchStatements(ctx, db)
 	if err != nil {
 		db.Close()
 		return nil, err
@@ -37,8 +57,29 @@ func NewPostgresClient(ctx context.Context, connStr string) (*PostgresClient, er
 
 // PostgresClient клиент для работы с PostgreSQL.
 type PostgresClient struct {
-	db      *sql.DB
-	retrier *tools.Retrier
+	db               *sql.DB
+	stmts            *batchStatements
+	retrier          *tools.Retrier
+	historyRetention time.Duration
+}
+
+// migrate применяет неприменённые миграции схемы БД.
+func (client *PostgresClient) migrate(ctx context.Context) error {
+	migrator, err := newMigrator(client.db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		logger.Info("migration applied",
+			logger.Field{Name: "entity", Value: "postgres"},
+			logger.Field{Name: "version", Value: migration.Version},
+			logger.Field{Name: "name", Value: migration.Name},
+		)
+	}
+
+	return err
 }
 
 // Ping проверка соединения.
@@ -55,6 +96,7 @@ func (client *PostgresClient) Ping(ctx context.Context) error {
 
 // Destroy уничтожает клиент.
 func (client *PostgresClient) Destroy(_ context.Context) {
+	client.stmts.Close()
 	client.db.Close()
 }
 
@@ -0,0 +1,52 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/postgres"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/repository/metrics/storagetest"
+)
+
+// testDatabaseEnv переменная окружения со строкой подключения к тестовой БД.
+// Все данные в БД удаляются перед каждым тестом.
+const testDatabaseEnv = "TEST_DATABASE_DSN"
+
+func TestPostgresClient_Conformance(t *testing.T) {
+	connStr := os.Getenv(testDatabaseEnv)
+	if connStr == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	logger.Init(true)
+
+	open := func() (metrics.MetricsStorage, error) {
+		return postgres.NewPostgresClient(context.Background(), postgres.PostgresClientOptions{
+			ConnStr:          connStr,
+			HistoryRetention: time.Hour,
+		})
+	}
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Opener {
+		// Открываем клиент, чтобы таблицы были созданы до очистки.
+		client, err := open()
+		require.NoError(t, err)
+		client.Destroy(context.Background())
+
+		db, err := sql.Open("pgx", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		_, err = db.Exec("TRUNCATE metrics, metrics_history, silences;")
+		require.NoError(t, err)
+
+		return open
+	})
+}
@@ -3,12 +3,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/xantinium/metrix/internal/models"
 )
 
-// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id.
-func (client *PostgresClient) GetGaugeMetric(ctx context.Context, id string) (float64, error) {
+// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id
+// и набору меток labels.
+func (client *PostgresClient) GetGaugeMetric(ctx context.Context, id string, labels models.Labels) (float64, error) {
 	var (
 		err   error
 		value float64
@@ -16,9 +18,10 @@ func (client *PostgresClient) GetGaugeMetric(ctx context.Context, id string) (fl
 
 	client.retrier.Exec(func() bool {
 		row := client.db.QueryRowContext(ctx, "SELECT gauge_value FROM metrics"+
-			" WHERE id = $1 AND type = $2;",
+			" WHERE id = $1 AND type = $2 AND labels_key = $3;",
 			id,
-			serializeMetricType(models.Gauge))
+			serializeMetricType(models.Gauge),
+			labels.Key())
 
 		err = row.Scan(&value)
 		return shouldRetry(err)
@@ -27,8 +30,9 @@ func (client *PostgresClient) GetGaugeMetric(ctx context.Context, id string) (fl
 	return value, convertError(err)
 }
 
-// GetCounterMetric возвращает метрику типа Counter по идентификатору id.
-func (client *PostgresClient) GetCounterMetric(ctx context.Context, id string) (int64, error) {
+// GetCounterMetric возвращает метрику типа Counter по идентификатору id
+// и набору меток labels.
+func (client *PostgresClient) GetCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error) {
 	var (
 		err   error
 		value int64
@@ -36,9 +40,10 @@ func (client *PostgresClient) GetCounterMetric(ctx context.Context, id string) (
 
 	client.retrier.Exec(func() bool {
 		row := client.db.QueryRowContext(ctx, "SELECT counter_value FROM metrics"+
-			" WHERE id = $1 AND type = $2;",
+			" WHERE id = $1 AND type = $2 AND labels_key = $3;",
 			id,
-			serializeMetricType(models.Counter))
+			serializeMetricType(models.Counter),
+			labels.Key())
 
 		err = row.Scan(&value)
 		return shouldRetry(err)
@@ -47,16 +52,74 @@ func (client *PostgresClient) GetCounterMetric(ctx context.Context, id string) (
 	return value, convertError(err)
 }
 
-// GetAllMetrics возвращает все существующие метрики.
-func (client *PostgresClient) GetAllMetrics(ctx context.Context) ([]models.MetricInfo, error) {
+// GetHistogramMetric возвращает метрику типа Histogram по идентификатору id
+// и набору меток labels.
+func (client *PostgresClient) GetHistogramMetric(ctx context.Context, id string, labels models.Labels) (models.HistogramValue, error) {
+	var (
+		err      error
+		rawValue []byte
+	)
+
+	client.retrier.Exec(func() bool {
+		row := client.db.QueryRowContext(ctx, "SELECT histogram FROM metrics"+
+			" WHERE id = $1 AND type = $2 AND labels_key = $3;",
+			id,
+			serializeMetricType(models.Histogram),
+			labels.Key())
+
+		err = row.Scan(&rawValue)
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return models.HistogramValue{}, convertError(err)
+	}
+
+	return deserializeHistogram(rawValue)
+}
+
+// GetSummaryMetric возвращает метрику типа Summary по идентификатору id
+// и набору меток labels.
+func (client *PostgresClient) GetSummaryMetric(ctx context.Context, id string, labels models.Labels) (models.SummaryValue, error) {
+	var (
+		err      error
+		rawValue []byte
+	)
+
+	client.retrier.Exec(func() bool {
+		row := client.db.QueryRowContext(ctx, "SELECT summary FROM metrics"+
+			" WHERE id = $1 AND type = $2 AND labels_key = $3;",
+			id,
+			serializeMetricType(models.Summary),
+			labels.Key())
+
+		err = row.Scan(&rawValue)
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return models.SummaryValue{}, convertError(err)
+	}
+
+	return deserializeSummary(rawValue)
+}
+
+// GetAllMetrics возвращает все существующие метрики,
+// содержащие метки из filter.
+func (client *PostgresClient) GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error) {
 	var (
 		err     error
 		rows    *sql.Rows
 		metrics []models.MetricInfo
 	)
 
+	labelsFilter, err := serializeLabels(filter)
+	if err != nil {
+		return nil, err
+	}
+
 	client.retrier.Exec(func() bool {
-		rows, err = client.db.QueryContext(ctx, "SELECT id, type, gauge_value, counter_value FROM metrics;")
+		rows, err = client.db.QueryContext(ctx, "SELECT id, type, labels, gauge_value, counter_value, histogram, summary FROM metrics"+
+			" WHERE labels @> $1::jsonb;",
+			labelsFilter)
 		if err != nil {
 			return shouldRetry(err)
 		}
@@ -74,15 +137,24 @@ func (client *PostgresClient) GetAllMetrics(ctx context.Context) ([]models.Metri
 				metricID        string
 				metricType      models.MetricType
 				maybeMetricType psqlMetricType
+				rawLabels       []byte
+				labels          models.Labels
+				rawHistogram    []byte
+				rawSummary      []byte
 				gaugeValue      float64
 				counterValue    int64
 			)
 
-			err = rows.Scan(&metricID, &maybeMetricType, &gaugeValue, &counterValue)
+			err = rows.Scan(&metricID, &maybeMetricType, &rawLabels, &gaugeValue, &counterValue, &rawHistogram, &rawSummary)
 			if err != nil {
 				return shouldRetry(err)
 			}
 
+			labels, err = deserializeLabels(rawLabels)
+			if err != nil {
+				return false
+			}
+
 			metricType, err = deserializeMetricType(maybeMetricType)
 			if err != nil {
 				return shouldRetry(err)
@@ -90,9 +162,25 @@ func (client *PostgresClient) GetAllMetrics(ctx context.Context) ([]models.Metri
 
 			switch metricType {
 			case models.Gauge:
-				metrics = append(metrics, models.NewGaugeMetric(metricID, gaugeValue))
+				metrics = append(metrics, models.NewGaugeMetric(metricID, gaugeValue).WithLabels(labels))
 			case models.Counter:
-				metrics = append(metrics, models.NewCounterMetric(metricID, counterValue))
+				metrics = append(metrics, models.NewCounterMetric(metricID, counterValue).WithLabels(labels))
+			case models.Histogram:
+				var histogramValue models.HistogramValue
+				histogramValue, err = deserializeHistogram(rawHistogram)
+				if err != nil {
+					return false
+				}
+
+				metrics = append(metrics, models.NewHistogramMetric(metricID, histogramValue).WithLabels(labels))
+			case models.Summary:
+				var summaryValue models.SummaryValue
+				summaryValue, err = deserializeSummary(rawSummary)
+				if err != nil {
+					return false
+				}
+
+				metrics = append(metrics, models.NewSummaryMetric(metricID, summaryValue).WithLabels(labels))
 			}
 		}
 
@@ -101,3 +189,51 @@ func (client *PostgresClient) GetAllMetrics(ctx context.Context) ([]models.Metri
 
 	return metrics, convertError(err)
 }
+
+// GetMetricHistory возвращает значения метрики с идентификатором id,
+// типом metricType и набором меток labels, полученные в промежутке [from, to].
+// Если время хранения истории не задано, возвращается models.ErrHistoryDisabled.
+func (client *PostgresClient) GetMetricHistory(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	if client.historyRetention == 0 {
+		return nil, models.ErrHistoryDisabled
+	}
+
+	var (
+		err     error
+		rows    *sql.Rows
+		samples []models.MetricSample
+	)
+
+	client.retrier.Exec(func() bool {
+		rows, err = client.db.QueryContext(ctx, "SELECT ts, gauge_value, counter_value FROM metrics_history"+
+			" WHERE id = $1 AND type = $2 AND labels_key = $3 AND ts >= $4 AND ts <= $5"+
+			" ORDER BY ts;",
+			id,
+			serializeMetricType(metricType),
+			labels.Key(),
+			from,
+			to)
+		if err != nil {
+			return shouldRetry(err)
+		}
+		defer rows.Close()
+
+		samples = make([]models.MetricSample, 0)
+
+		for rows.Next() {
+			var sample models.MetricSample
+
+			err = rows.Scan(&sample.Timestamp, &sample.GaugeValue, &sample.CounterValue)
+			if err != nil {
+				return shouldRetry(err)
+			}
+
+			samples = append(samples, sample)
+		}
+
+		err = rows.Err()
+		return shouldRetry(err)
+	})
+
+	return samples, convertError(err)
+}
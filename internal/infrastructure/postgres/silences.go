@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// psqlMatcher представление условия заглушения в колонке типа JSONB.
+type psqlMatcher struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+func serializeMatchers(matchers []models.LabelMatcher) (string, error) {
+	items := make([]psqlMatcher, len(matchers))
+	for i, matcher := range matchers {
+		items[i] = psqlMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  string(matcher.Type),
+		}
+	}
+
+	matchersBytes, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+
+	return string(matchersBytes), nil
+}
+
+func deserializeMatchers(matchersBytes []byte) ([]models.LabelMatcher, error) {
+	var items []psqlMatcher
+
+	err := json.Unmarshal(matchersBytes, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := make([]models.LabelMatcher, len(items))
+	for i, item := range items {
+		matchers[i] = models.LabelMatcher{
+			Name:  item.Name,
+			Value: item.Value,
+			Type:  models.MatchType(item.Type),
+		}
+	}
+
+	return matchers, nil
+}
+
+const silenceColumns = "id, rule_name, matchers, starts_at, ends_at, comment, created_by"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSilence(row rowScanner) (models.Silence, error) {
+	var (
+		silence     models.Silence
+		rawMatchers []byte
+	)
+
+	err := row.Scan(
+		&silence.ID,
+		&silence.RuleName,
+		&rawMatchers,
+		&silence.StartsAt,
+		&silence.EndsAt,
+		&silence.Comment,
+		&silence.CreatedBy,
+	)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	silence.Matchers, err = deserializeMatchers(rawMatchers)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	return silence, nil
+}
+
+// GetSilences возвращает все заглушения, упорядоченные по времени начала.
+func (client *PostgresClient) GetSilences(ctx context.Context) ([]models.Silence, error) {
+	var (
+		err      error
+		silences []models.Silence
+	)
+
+	client.retrier.Exec(func() bool {
+		var rows *sql.Rows
+
+		silences = nil
+
+		rows, err = client.db.QueryContext(ctx, "SELECT "+silenceColumns+
+			" FROM silences ORDER BY starts_at, id;")
+		if err != nil {
+			return shouldRetry(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var silence models.Silence
+
+			silence, err = scanSilence(rows)
+			if err != nil {
+				return false
+			}
+
+			silences = append(silences, silence)
+		}
+
+		err = rows.Err()
+		return shouldRetry(err)
+	})
+
+	return silences, convertError(err)
+}
+
+// CreateSilence сохраняет новое заглушение.
+func (client *PostgresClient) CreateSilence(ctx context.Context, silence models.Silence) error {
+	matchers, err := serializeMatchers(silence.Matchers)
+	if err != nil {
+		return err
+	}
+
+	client.retrier.Exec(func() bool {
+		_, err = client.db.ExecContext(ctx, "INSERT INTO silences ("+silenceColumns+")"+
+			" VALUES ($1, $2, $3, $4, $5, $6, $7);",
+			silence.ID,
+			silence.RuleName,
+			matchers,
+			silence.StartsAt,
+			silence.EndsAt,
+			silence.Comment,
+			silence.CreatedBy)
+		return shouldRetry(err)
+	})
+
+	return convertError(err)
+}
+
+// ExpireSilence завершает заглушение с идентификатором id в момент now.
+func (client *PostgresClient) ExpireSilence(ctx context.Context, id string, now time.Time) (models.Silence, error) {
+	var (
+		err     error
+		silence models.Silence
+	)
+
+	client.retrier.Exec(func() bool {
+		var tx *sql.Tx
+
+		tx, err = client.db.BeginTx(ctx, nil)
+		if err != nil {
+			return shouldRetry(err)
+		}
+		defer tx.Rollback()
+
+		silence, err = scanSilence(tx.QueryRowContext(ctx, "SELECT "+silenceColumns+
+			" FROM silences WHERE id = $1 FOR UPDATE;", id))
+		if err != nil {
+			return shouldRetry(err)
+		}
+
+		err = silence.Expire(now)
+		if err != nil {
+			return false
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE silences SET starts_at = $2, ends_at = $3 WHERE id = $1;",
+			id,
+			silence.StartsAt,
+			silence.EndsAt)
+		if err != nil {
+			return shouldRetry(err)
+		}
+
+		err = tx.Commit()
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return models.Silence{}, convertError(err)
+	}
+
+	return silence, nil
+}
+
+// DeleteExpiredSilences удаляет заглушения, завершившиеся раньше endedBefore.
+//
+// Возвращает количество удалённых заглушений.
+func (client *PostgresClient) DeleteExpiredSilences(ctx context.Context, endedBefore time.Time) (int, error) {
+	var (
+		err     error
+		result  sql.Result
+		deleted int64
+	)
+
+	client.retrier.Exec(func() bool {
+		result, err = client.db.ExecContext(ctx, "DELETE FROM silences WHERE ends_at < $1;", endedBefore)
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+
+	deleted, err = result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
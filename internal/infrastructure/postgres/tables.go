@@ -1,7 +1,7 @@
 package postgres
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/xantinium/metrix/internal/models"
@@ -13,6 +13,8 @@ const (
 	unknown psqlMetricType = iota
 	gauge
 	counter
+	histogram
+	summary
 )
 
 func serializeMetricType(metricType models.MetricType) psqlMetricType {
@@ -21,6 +23,10 @@ func serializeMetricType(metricType models.MetricType) psqlMetricType {
 		return gauge
 	case models.Counter:
 		return counter
+	case models.Histogram:
+		return histogram
+	case models.Summary:
+		return summary
 	default:
 		return unknown
 	}
@@ -32,33 +38,134 @@ func deserializeMetricType(metricType psqlMetricType) (models.MetricType, error)
 		return models.Gauge, nil
 	case counter:
 		return models.Counter, nil
+	case histogram:
+		return models.Histogram, nil
+	case summary:
+		return models.Summary, nil
 	default:
 		return "", fmt.Errorf("unknown metric type: %q", metricType)
 	}
 }
 
-func (client *PostgresClient) initTables(ctx context.Context) error {
-	err := client.initMetricsTable(ctx)
+// serializeLabels сериализует метки в JSON для хранения в колонке типа JSONB.
+func serializeLabels(labels models.Labels) (string, error) {
+	if len(labels) == 0 {
+		return "{}", nil
+	}
+
+	labelsBytes, err := json.Marshal(labels)
+	if err != nil {
+		return "", err
+	}
+
+	return string(labelsBytes), nil
+}
+
+func deserializeLabels(labelsBytes []byte) (models.Labels, error) {
+	var labels models.Labels
+
+	err := json.Unmarshal(labelsBytes, &labels)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	return labels, nil
+}
+
+// psqlHistogram представление значения гистограммы в колонке типа JSONB.
+type psqlHistogram struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+}
+
+func serializeHistogram(value models.HistogramValue) (string, error) {
+	histogramBytes, err := json.Marshal(psqlHistogram{
+		Bounds: value.Bounds,
+		Counts: value.Counts,
+		Count:  value.Count,
+		Sum:    value.Sum,
+	})
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return string(histogramBytes), nil
 }
 
-func (client *PostgresClient) initMetricsTable(ctx context.Context) error {
-	var err error
-
-	client.retrier.Exec(func() bool {
-		_, err = client.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS metrics ("+
-			"id VARCHAR(50) NOT NULL,"+
-			"type SMALLINT NOT NULL,"+
-			"gauge_value DOUBLE PRECISION NOT NULL,"+
-			"counter_value BIGINT NOT NULL,"+
-			"PRIMARY KEY (id, type)"+
-			");")
-		return shouldRetry(err)
+func deserializeHistogram(histogramBytes []byte) (models.HistogramValue, error) {
+	var value psqlHistogram
+
+	err := json.Unmarshal(histogramBytes, &value)
+	if err != nil {
+		return models.HistogramValue{}, err
+	}
+
+	return models.HistogramValue{
+		Bounds: value.Bounds,
+		Counts: value.Counts,
+		Count:  value.Count,
+		Sum:    value.Sum,
+	}, nil
+}
+
+// psqlSummaryBins представление интервалов скетча в колонке типа JSONB.
+type psqlSummaryBins struct {
+	Offset int     `json:"offset"`
+	Counts []int64 `json:"counts"`
+}
+
+// psqlSummary представление значения скетча в колонке типа JSONB.
+type psqlSummary struct {
+	Positive  psqlSummaryBins `json:"positive"`
+	Negative  psqlSummaryBins `json:"negative"`
+	Accuracy  float64         `json:"accuracy"`
+	ZeroCount int64           `json:"zeroCount"`
+	Count     int64           `json:"count"`
+	Sum       float64         `json:"sum"`
+	Min       float64         `json:"min"`
+	Max       float64         `json:"max"`
+}
+
+func serializeSummary(value models.SummaryValue) (string, error) {
+	summaryBytes, err := json.Marshal(psqlSummary{
+		Positive:  psqlSummaryBins(value.Positive),
+		Negative:  psqlSummaryBins(value.Negative),
+		Accuracy:  value.Accuracy,
+		ZeroCount: value.ZeroCount,
+		Count:     value.Count,
+		Sum:       value.Sum,
+		Min:       value.Min,
+		Max:       value.Max,
 	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(summaryBytes), nil
+}
+
+func deserializeSummary(summaryBytes []byte) (models.SummaryValue, error) {
+	var value psqlSummary
+
+	err := json.Unmarshal(summaryBytes, &value)
+	if err != nil {
+		return models.SummaryValue{}, err
+	}
 
-	return convertError(err)
+	return models.SummaryValue{
+		Positive:  models.SummaryBins(value.Positive),
+		Negative:  models.SummaryBins(value.Negative),
+		Accuracy:  value.Accuracy,
+		ZeroCount: value.ZeroCount,
+		Count:     value.Count,
+		Sum:       value.Sum,
+		Min:       value.Min,
+		Max:       value.Max,
+	}, nil
 }
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrHistogramBoundsMismatch ошибка несовпадения границ интервалов
+// гистограмм при их объединении.
+var ErrHistogramBoundsMismatch = errors.New("histogram bounds mismatch")
+
+// HistogramValue значение метрики типа Histogram.
+//
+// Bounds содержит возрастающие верхние границы интервалов (включительно).
+// Counts содержит количество значений в каждом интервале и имеет длину
+// len(Bounds)+1: последний элемент соответствует интервалу (Bounds[n-1], +Inf).
+// Значения в Counts не накопительные, в отличие от формата Prometheus.
+type HistogramValue struct {
+	Bounds []float64
+	Counts []int64
+	Count  int64
+	Sum    float64
+}
+
+// NewHistogramValue создаёт значение гистограммы и проверяет его корректность.
+// Общее количество значений вычисляется по интервалам.
+func NewHistogramValue(bounds []float64, counts []int64, sum float64) (HistogramValue, error) {
+	value := HistogramValue{
+		Bounds: slices.Clone(bounds),
+		Counts: slices.Clone(counts),
+		Sum:    sum,
+	}
+
+	for _, count := range counts {
+		value.Count += count
+	}
+
+	return value, value.Validate()
+}
+
+// Validate проверяет корректность значения гистограммы.
+func (value HistogramValue) Validate() error {
+	if len(value.Counts) != len(value.Bounds)+1 {
+		return fmt.Errorf("histogram must have %d bucket counts, got %d", len(value.Bounds)+1, len(value.Counts))
+	}
+
+	for i, bound := range value.Bounds {
+		if math.IsNaN(bound) || math.IsInf(bound, 0) {
+			return fmt.Errorf("histogram bound must be finite")
+		}
+		if i > 0 && bound <= value.Bounds[i-1] {
+			return fmt.Errorf("histogram bounds must be strictly increasing")
+		}
+	}
+
+	var total int64
+	for _, count := range value.Counts {
+		if count < 0 {
+			return fmt.Errorf("histogram bucket count cannot be negative")
+		}
+		total += count
+	}
+
+	if total != value.Count {
+		return fmt.Errorf("histogram count %d doesn't match sum of bucket counts %d", value.Count, total)
+	}
+
+	return nil
+}
+
+// Clone возвращает глубокую копию значения.
+func (value HistogramValue) Clone() HistogramValue {
+	value.Bounds = slices.Clone(value.Bounds)
+	value.Counts = slices.Clone(value.Counts)
+	return value
+}
+
+// Merge возвращает сумму двух гистограмм с одинаковыми
+// границами интервалов. Пустое значение (без интервалов и значений)
+// считается нейтральным элементом.
+func (value HistogramValue) Merge(other HistogramValue) (HistogramValue, error) {
+	if value.isZero() {
+		return other.Clone(), nil
+	}
+	if other.isZero() {
+		return value.Clone(), nil
+	}
+
+	if !slices.Equal(value.Bounds, other.Bounds) {
+		return HistogramValue{}, ErrHistogramBoundsMismatch
+	}
+
+	merged := value.Clone()
+	for i := range merged.Counts {
+		merged.Counts[i] += other.Counts[i]
+	}
+	merged.Count += other.Count
+	merged.Sum += other.Sum
+
+	return merged, nil
+}
+
+// Quantile оценивает квантиль q (0 <= q <= 1) при помощи линейной
+// интерполяции внутри интервала, как это делает histogram_quantile в Prometheus.
+// Для значений, попавших в последний интервал, возвращается наибольшая
+// конечная граница. Для пустой гистограммы возвращается NaN.
+func (value HistogramValue) Quantile(q float64) float64 {
+	if value.Count == 0 || math.IsNaN(q) || q < 0 || q > 1 {
+		return math.NaN()
+	}
+
+	rank := q * float64(value.Count)
+
+	var cumulative int64
+	for i, count := range value.Counts {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+
+		if i == len(value.Bounds) {
+			if len(value.Bounds) == 0 {
+				return math.NaN()
+			}
+			return value.Bounds[len(value.Bounds)-1]
+		}
+
+		lower := 0.0
+		if i > 0 {
+			lower = value.Bounds[i-1]
+		} else if value.Bounds[0] <= 0 {
+			return value.Bounds[0]
+		}
+
+		upper := value.Bounds[i]
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(count)
+	}
+
+	// Попасть сюда невозможно, т.к. Count равен сумме Counts.
+	return math.NaN()
+}
+
+func (value HistogramValue) isZero() bool {
+	return len(value.Bounds) == 0 && len(value.Counts) == 0 && value.Count == 0 && value.Sum == 0
+}
@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestNewHistogramValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  []float64
+		counts  []int64
+		wantErr bool
+	}{
+		{
+			name:   "Корректная гистограмма",
+			bounds: []float64{0.1, 0.5, 1},
+			counts: []int64{1, 2, 3, 4},
+		},
+		{
+			name:   "Гистограмма без границ",
+			counts: []int64{5},
+		},
+		{
+			name:    "Неверное количество интервалов",
+			bounds:  []float64{0.1, 0.5},
+			counts:  []int64{1, 2},
+			wantErr: true,
+		},
+		{
+			name:    "Границы не возрастают",
+			bounds:  []float64{0.5, 0.1},
+			counts:  []int64{1, 2, 3},
+			wantErr: true,
+		},
+		{
+			name:    "Бесконечная граница",
+			bounds:  []float64{0.5, math.Inf(1)},
+			counts:  []int64{1, 2, 3},
+			wantErr: true,
+		},
+		{
+			name:    "Отрицательное количество",
+			bounds:  []float64{0.5},
+			counts:  []int64{1, -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := models.NewHistogramValue(tt.bounds, tt.counts, 1)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var total int64
+			for _, count := range tt.counts {
+				total += count
+			}
+			require.Equal(t, total, value.Count)
+		})
+	}
+}
+
+func TestHistogramValue_Merge(t *testing.T) {
+	a, err := models.NewHistogramValue([]float64{0.1, 1}, []int64{1, 2, 0}, 1.5)
+	require.NoError(t, err)
+
+	b, err := models.NewHistogramValue([]float64{0.1, 1}, []int64{0, 1, 4}, 10)
+	require.NoError(t, err)
+
+	merged, err := a.Merge(b)
+	require.NoError(t, err)
+	require.Equal(t, models.HistogramValue{
+		Bounds: []float64{0.1, 1},
+		Counts: []int64{1, 3, 4},
+		Count:  8,
+		Sum:    11.5,
+	}, merged)
+
+	// Исходные значения не изменяются.
+	require.Equal(t, []int64{1, 2, 0}, a.Counts)
+
+	merged, err = models.HistogramValue{}.Merge(a)
+	require.NoError(t, err)
+	require.Equal(t, a, merged)
+
+	c, err := models.NewHistogramValue([]float64{0.5}, []int64{1, 1}, 1)
+	require.NoError(t, err)
+
+	_, err = a.Merge(c)
+	require.ErrorIs(t, err, models.ErrHistogramBoundsMismatch)
+}
+
+func TestHistogramValue_Quantile(t *testing.T) {
+	value, err := models.NewHistogramValue([]float64{0.1, 0.5, 1}, []int64{50, 40, 5, 5}, 30)
+	require.NoError(t, err)
+
+	require.InDelta(t, 0.05, value.Quantile(0.25), 1e-9)
+	require.InDelta(t, 0.1, value.Quantile(0.5), 1e-9)
+	require.InDelta(t, 0.3, value.Quantile(0.7), 1e-9)
+	require.InDelta(t, 1, value.Quantile(0.95), 1e-9)
+	// Значения выше последней границы оцениваются ею.
+	require.InDelta(t, 1, value.Quantile(0.99), 1e-9)
+
+	require.True(t, math.IsNaN(models.HistogramValue{}.Quantile(0.5)))
+	require.True(t, math.IsNaN(value.Quantile(1.5)))
+}
@@ -4,10 +4,25 @@ package models
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrEmptyMetricID ошибка отсутствия идентификатора метрики.
+	ErrEmptyMetricID = errors.New("metric id cannot be empty")
+	// ErrQueueFull ошибка переполнения очереди буферизованных обновлений.
+	// Обновление не принято и может быть повторено позже.
+	ErrQueueFull = errors.New("ingestion queue is full")
+	// ErrInvalidRange ошибка некорректных параметров запроса истории метрики.
+	ErrInvalidRange = errors.New("invalid range")
+	// ErrHistoryDisabled ошибка запроса истории метрики,
+	// когда время хранения истории не задано.
+	ErrHistoryDisabled = errors.New("metric history is disabled")
 )
 
 // MetricType тип метрики.
@@ -18,6 +33,10 @@ const (
 	Gauge MetricType = "gauge"
 	// Counter суммируемая метрика.
 	Counter MetricType = "counter"
+	// Histogram метрика-гистограмма, распределяющая значения по интервалам.
+	Histogram MetricType = "histogram"
+	// Summary метрика-скетч, позволяющая оценивать квантили распределения.
+	Summary MetricType = "summary"
 )
 
 // ParseStringAsMetricType парсит строку в тип метрики.
@@ -27,11 +46,43 @@ func ParseStringAsMetricType(maybeMetricType string) (MetricType, error) {
 		return Gauge, nil
 	case string(Counter):
 		return Counter, nil
+	case string(Histogram):
+		return Histogram, nil
+	case string(Summary):
+		return Summary, nil
 	default:
 		return "", fmt.Errorf("unknown metric type")
 	}
 }
 
+// ValidateMetricID проверяет идентификатор метрики.
+func ValidateMetricID(id string) error {
+	if id == "" {
+		return ErrEmptyMetricID
+	}
+
+	return nil
+}
+
+// ParseMetricIdentity проверяет идентификатор метрики
+// и парсит строку в тип метрики.
+//
+// Используется всеми способами приёма метрик,
+// чтобы правила валидации совпадали.
+func ParseMetricIdentity(id, maybeMetricType string) (MetricType, error) {
+	err := ValidateMetricID(id)
+	if err != nil {
+		return "", err
+	}
+
+	metricType, err := ParseStringAsMetricType(maybeMetricType)
+	if err != nil {
+		return "", fmt.Errorf("unknown metric type: %q", maybeMetricType)
+	}
+
+	return metricType, nil
+}
+
 // NewGaugeMetric создаёт новую метрику типа Gauge.
 func NewGaugeMetric(id string, value float64) MetricInfo {
 	return MetricInfo{
@@ -50,12 +101,142 @@ func NewCounterMetric(id string, value int64) MetricInfo {
 	}
 }
 
+// NewHistogramMetric создаёт новую метрику типа Histogram.
+func NewHistogramMetric(id string, value HistogramValue) MetricInfo {
+	return MetricInfo{
+		metricID:       id,
+		metricType:     Histogram,
+		histogramValue: value.Clone(),
+	}
+}
+
+// NewSummaryMetric создаёт новую метрику типа Summary.
+func NewSummaryMetric(id string, value SummaryValue) MetricInfo {
+	return MetricInfo{
+		metricID:     id,
+		metricType:   Summary,
+		summaryValue: value.Clone(),
+	}
+}
+
+// Labels набор меток метрики.
+//
+// Метки входят в идентичность метрики: метрики с одинаковыми
+// идентификатором и типом, но разными метками, хранятся раздельно.
+type Labels map[string]string
+
+// Key возвращает каноническое строковое представление меток,
+// используемое хранилищами в качестве части ключа метрики.
+// Для пустого набора возвращается пустая строка.
+func (labels Labels) Key() string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	b := strings.Builder{}
+
+	for i, name := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Quote(name))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+	}
+
+	return b.String()
+}
+
+// Matches проверяет, что набор содержит все метки из filter.
+// Пустой фильтр соответствует любому набору.
+func (labels Labels) Matches(filter Labels) bool {
+	for name, value := range filter {
+		if actual, exists := labels[name]; !exists || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseLabelsKey восстанавливает метки из канонического
+// строкового представления, полученного через Labels.Key.
+func ParseLabelsKey(key string) (Labels, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	labels := make(Labels)
+
+	for rest := key; rest != ""; {
+		name, tail, err := unquotePrefix(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels key %q: %v", key, err)
+		}
+		if !strings.HasPrefix(tail, "=") {
+			return nil, fmt.Errorf("invalid labels key %q: missing '='", key)
+		}
+
+		var value string
+		value, rest, err = unquotePrefix(tail[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels key %q: %v", key, err)
+		}
+
+		labels[name] = value
+
+		if rest != "" {
+			if !strings.HasPrefix(rest, ",") {
+				return nil, fmt.Errorf("invalid labels key %q: missing ','", key)
+			}
+			rest = rest[1:]
+		}
+	}
+
+	return labels, nil
+}
+
+// unquotePrefix извлекает строку в кавычках из начала s.
+func unquotePrefix(s string) (string, string, error) {
+	quoted, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", "", err
+	}
+
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", "", err
+	}
+
+	return value, s[len(quoted):], nil
+}
+
 // MetricInfo структура, описывающая метрику.
 type MetricInfo struct {
-	metricID     string
-	metricType   MetricType
-	gaugeValue   float64
-	counterValue int64
+	labels         Labels
+	metricID       string
+	metricType     MetricType
+	histogramValue HistogramValue
+	summaryValue   SummaryValue
+	gaugeValue     float64
+	counterValue   int64
+}
+
+// WithLabels возвращает копию метрики с метками labels.
+// Пустой набор меток равнозначен их отсутствию.
+func (info MetricInfo) WithLabels(labels Labels) MetricInfo {
+	if len(labels) == 0 {
+		info.labels = nil
+		return info
+	}
+
+	info.labels = maps.Clone(labels)
+	return info
+}
+
+// Labels возвращает метки метрики.
+func (info MetricInfo) Labels() Labels {
+	return info.labels
 }
 
 // ID возвращает идентификатор метрики.
@@ -77,3 +258,37 @@ func (info MetricInfo) GaugeValue() float64 {
 func (info MetricInfo) CounterValue() int64 {
 	return info.counterValue
 }
+
+// HistogramValue возвращает значение метрики типа Histogram.
+func (info MetricInfo) HistogramValue() HistogramValue {
+	return info.histogramValue
+}
+
+// SummaryValue возвращает значение метрики типа Summary.
+func (info MetricInfo) SummaryValue() SummaryValue {
+	return info.summaryValue
+}
+
+// MetricSample структура, описывающая значение метрики,
+// полученное сервером в момент времени Timestamp.
+//
+// Для метрик типа Counter хранится переданное приращение,
+// а не накопленное значение.
+type MetricSample struct {
+	Timestamp    time.Time
+	GaugeValue   float64
+	CounterValue int64
+}
+
+// MetricBucket структура, описывающая агрегированные
+// значения метрики на промежутке [Start, Start+step).
+type MetricBucket struct {
+	Start time.Time
+	Min   float64
+	Max   float64
+	Avg   float64
+	Last  float64
+	// Sum сумма приращений. Имеет смысл только для метрик типа Counter.
+	Sum   int64
+	Count int
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// MetricNameLabel название псевдо-метки, значением которой
+// при сопоставлении заглушений является идентификатор метрики.
+const MetricNameLabel = "__name__"
+
+// ErrSilenceExpired ошибка, возвращаемая при попытке
+// завершить уже завершённое заглушение.
+var ErrSilenceExpired = errors.New("silence is already expired")
+
+// MatchType тип сопоставления значения метки.
+type MatchType string
+
+const (
+	// MatchEqual значение метки равно заданному.
+	MatchEqual MatchType = "="
+	// MatchNotEqual значение метки не равно заданному.
+	MatchNotEqual MatchType = "!="
+	// MatchRegexp значение метки полностью соответствует регулярному выражению.
+	MatchRegexp MatchType = "=~"
+	// MatchNotRegexp значение метки не соответствует регулярному выражению.
+	MatchNotRegexp MatchType = "!~"
+)
+
+// LabelMatcher условие на значение метки.
+// Отсутствующая метка считается равной пустой строке.
+type LabelMatcher struct {
+	Name  string
+	Value string
+	Type  MatchType
+}
+
+// Validate проверяет корректность условия.
+func (matcher LabelMatcher) Validate() error {
+	if matcher.Name == "" {
+		return fmt.Errorf("matcher name cannot be empty")
+	}
+
+	switch matcher.Type {
+	case MatchEqual, MatchNotEqual:
+		return nil
+	case MatchRegexp, MatchNotRegexp:
+		_, err := regexp.Compile(matcher.Value)
+		if err != nil {
+			return fmt.Errorf("matcher %q: invalid regexp: %v", matcher.Name, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("matcher %q: unknown match type %q", matcher.Name, matcher.Type)
+	}
+}
+
+// Matches проверяет выполнение условия для значения метки.
+func (matcher LabelMatcher) Matches(value string) bool {
+	switch matcher.Type {
+	case MatchEqual:
+		return value == matcher.Value
+	case MatchNotEqual:
+		return value != matcher.Value
+	case MatchRegexp, MatchNotRegexp:
+		re, err := compileAnchoredRegexp(matcher.Value)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(value) == (matcher.Type == MatchRegexp)
+	default:
+		return false
+	}
+}
+
+// MatchLabels проверяет выполнение всех условий matchers для метрики
+// с идентификатором metricID и метками labels. Идентификатор метрики
+// доступен в условиях как метка MetricNameLabel.
+func MatchLabels(matchers []LabelMatcher, metricID string, labels Labels) bool {
+	for _, matcher := range matchers {
+		value := labels[matcher.Name]
+		if matcher.Name == MetricNameLabel {
+			value = metricID
+		}
+
+		if !matcher.Matches(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// anchoredRegexps кеш скомпилированных регулярных выражений условий.
+var anchoredRegexps sync.Map
+
+// compileAnchoredRegexp компилирует регулярное выражение,
+// которому должно соответствовать всё значение метки.
+func compileAnchoredRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := anchoredRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+
+	anchoredRegexps.Store(expr, re)
+	return re, nil
+}
+
+// Silence заглушение оповещений на промежуток времени [StartsAt, EndsAt).
+//
+// Заглушение применяется к оповещениям, название правила которых
+// соответствует шаблону RuleName (синтаксис path.Match, пустой шаблон
+// соответствует любому правилу), а метки удовлетворяют всем Matchers.
+// Идентификатор метрики доступен в условиях как метка MetricNameLabel.
+type Silence struct {
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Matchers  []LabelMatcher
+	ID        string
+	RuleName  string
+	Comment   string
+	CreatedBy string
+}
+
+// Validate проверяет корректность заглушения.
+func (silence Silence) Validate() error {
+	if silence.RuleName == "" && len(silence.Matchers) == 0 {
+		return fmt.Errorf("silence must have rule name or at least one matcher")
+	}
+
+	if silence.RuleName != "" {
+		_, err := path.Match(silence.RuleName, "")
+		if err != nil {
+			return fmt.Errorf("invalid rule name pattern %q: %v", silence.RuleName, err)
+		}
+	}
+
+	for _, matcher := range silence.Matchers {
+		err := matcher.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if silence.StartsAt.IsZero() || silence.EndsAt.IsZero() {
+		return fmt.Errorf("silence time window must be set")
+	}
+
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return fmt.Errorf("silence must end after it starts")
+	}
+
+	return nil
+}
+
+// IsActive проверяет, действует ли заглушение в момент now.
+func (silence Silence) IsActive(now time.Time) bool {
+	return !now.Before(silence.StartsAt) && now.Before(silence.EndsAt)
+}
+
+// Matches проверяет, относится ли заглушение к оповещению правила ruleName
+// для метрики с идентификатором metricID и метками labels.
+func (silence Silence) Matches(ruleName, metricID string, labels Labels) bool {
+	if silence.RuleName != "" {
+		matched, err := path.Match(silence.RuleName, ruleName)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return MatchLabels(silence.Matchers, metricID, labels)
+}
+
+// Expire завершает заглушение в момент now.
+// Ещё не начавшееся заглушение завершается, не начавшись.
+func (silence *Silence) Expire(now time.Time) error {
+	if !now.Before(silence.EndsAt) {
+		return ErrSilenceExpired
+	}
+
+	if now.Before(silence.StartsAt) {
+		silence.StartsAt = now
+	}
+	silence.EndsAt = now
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestSilence_Validate(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	tests := []struct {
+		name    string
+		silence models.Silence
+		wantErr bool
+	}{
+		{
+			name:    "Корректное заглушение по названию правила",
+			silence: models.Silence{StartsAt: start, EndsAt: start.Add(time.Hour), RuleName: "HighCPU*"},
+		},
+		{
+			name: "Корректное заглушение по меткам",
+			silence: models.Silence{
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+				Matchers: []models.LabelMatcher{{Name: "host", Value: "a|b", Type: models.MatchRegexp}},
+			},
+		},
+		{
+			name:    "Нет ни правила, ни условий",
+			silence: models.Silence{StartsAt: start, EndsAt: start.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "Некорректный шаблон правила",
+			silence: models.Silence{StartsAt: start, EndsAt: start.Add(time.Hour), RuleName: "High["},
+			wantErr: true,
+		},
+		{
+			name: "Некорректное регулярное выражение",
+			silence: models.Silence{
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+				Matchers: []models.LabelMatcher{{Name: "host", Value: "(", Type: models.MatchRegexp}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Неизвестный тип сопоставления",
+			silence: models.Silence{
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+				Matchers: []models.LabelMatcher{{Name: "host", Value: "a", Type: "~"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Окончание раньше начала",
+			silence: models.Silence{StartsAt: start, EndsAt: start, RuleName: "HighCPU"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.silence.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSilence_Matches(t *testing.T) {
+	labels := models.Labels{"host": "a", "env": "prod"}
+
+	tests := []struct {
+		name     string
+		silence  models.Silence
+		ruleName string
+		metricID string
+		want     bool
+	}{
+		{
+			name:     "Шаблон названия правила",
+			silence:  models.Silence{RuleName: "HighCPU*"},
+			ruleName: "HighCPUutilization",
+			metricID: "CPUutilization1",
+			want:     true,
+		},
+		{
+			name:     "Название правила не соответствует шаблону",
+			silence:  models.Silence{RuleName: "HighCPU*"},
+			ruleName: "LowMemory",
+			metricID: "FreeMemory",
+		},
+		{
+			name: "Регулярное выражение по идентификатору метрики",
+			silence: models.Silence{Matchers: []models.LabelMatcher{
+				{Name: models.MetricNameLabel, Value: "CPUutilization.*", Type: models.MatchRegexp},
+			}},
+			ruleName: "HighCPU",
+			metricID: "CPUutilization12",
+			want:     true,
+		},
+		{
+			name: "Регулярное выражение сопоставляется со всем значением",
+			silence: models.Silence{Matchers: []models.LabelMatcher{
+				{Name: models.MetricNameLabel, Value: "CPU", Type: models.MatchRegexp},
+			}},
+			ruleName: "HighCPU",
+			metricID: "CPUutilization1",
+		},
+		{
+			name: "Все условия на метки выполняются",
+			silence: models.Silence{Matchers: []models.LabelMatcher{
+				{Name: "host", Value: "a", Type: models.MatchEqual},
+				{Name: "env", Value: "staging", Type: models.MatchNotEqual},
+				{Name: "dc", Value: "", Type: models.MatchEqual},
+			}},
+			ruleName: "HighCPU",
+			metricID: "CPUutilization1",
+			want:     true,
+		},
+		{
+			name: "Одно из условий на метки не выполняется",
+			silence: models.Silence{Matchers: []models.LabelMatcher{
+				{Name: "host", Value: "a", Type: models.MatchEqual},
+				{Name: "env", Value: "prod|staging", Type: models.MatchNotRegexp},
+			}},
+			ruleName: "HighCPU",
+			metricID: "CPUutilization1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.silence.Matches(tt.ruleName, tt.metricID, labels))
+		})
+	}
+}
+
+func TestSilence_Expire(t *testing.T) {
+	start := time.Unix(1000, 0)
+	silence := models.Silence{StartsAt: start, EndsAt: start.Add(time.Hour), RuleName: "HighCPU"}
+
+	require.True(t, silence.IsActive(start))
+	require.False(t, silence.IsActive(start.Add(time.Hour)))
+
+	require.NoError(t, silence.Expire(start.Add(time.Minute)))
+	require.Equal(t, start.Add(time.Minute), silence.EndsAt)
+	require.False(t, silence.IsActive(start.Add(time.Minute)))
+
+	require.ErrorIs(t, silence.Expire(start.Add(2*time.Minute)), models.ErrSilenceExpired)
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrSummaryAccuracyMismatch ошибка несовпадения точности
+// скетчей при их объединении.
+var ErrSummaryAccuracyMismatch = errors.New("summary accuracy mismatch")
+
+const (
+	// DefaultSummaryAccuracy относительная точность скетча по умолчанию (1%).
+	DefaultSummaryAccuracy = 0.01
+	// MaxSummaryBins максимальное количество интервалов скетча для значений
+	// одного знака. При превышении интервалы с наименьшими индексами
+	// объединяются, что снижает точность для наименьших по модулю значений.
+	MaxSummaryBins = 2048
+	// maxSummaryIndex максимальный модуль индекса интервала скетча.
+	maxSummaryIndex = 1 << 40
+	// minSummaryValue значения, модуль которых меньше этой величины,
+	// учитываются как нулевые.
+	minSummaryValue = 1e-9
+)
+
+// SummaryBins интервалы скетча для значений одного знака.
+//
+// Counts[i] содержит количество значений в интервале с индексом Offset+i.
+// Интервал с индексом k покрывает модули значений (gamma^(k-1), gamma^k],
+// где gamma = (1+Accuracy)/(1-Accuracy).
+type SummaryBins struct {
+	Offset int
+	Counts []int64
+}
+
+// SummaryValue значение метрики типа Summary.
+//
+// Значение представляет собой скетч DDSketch: квантили оцениваются
+// с относительной погрешностью не более Accuracy, а скетчи с одинаковой
+// точностью объединяются без потери точности. Это позволяет агентам
+// отправлять заранее агрегированные распределения, а серверу — объединять их.
+type SummaryValue struct {
+	Positive  SummaryBins
+	Negative  SummaryBins
+	Accuracy  float64
+	ZeroCount int64
+	Count     int64
+	Sum       float64
+	Min       float64
+	Max       float64
+}
+
+// NewSummaryValue создаёт пустой скетч с относительной точностью accuracy.
+func NewSummaryValue(accuracy float64) (SummaryValue, error) {
+	value := SummaryValue{Accuracy: accuracy}
+	return value, value.Validate()
+}
+
+// Add добавляет значение v в скетч.
+func (value *SummaryValue) Add(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("summary value must be finite")
+	}
+
+	switch {
+	case v >= minSummaryValue:
+		value.Positive = value.Positive.add(value.index(v), 1)
+	case v <= -minSummaryValue:
+		value.Negative = value.Negative.add(value.index(-v), 1)
+	default:
+		value.ZeroCount++
+	}
+
+	if value.Count == 0 || v < value.Min {
+		value.Min = v
+	}
+	if value.Count == 0 || v > value.Max {
+		value.Max = v
+	}
+
+	value.Count++
+	value.Sum += v
+
+	return nil
+}
+
+// Validate проверяет корректность значения скетча.
+func (value SummaryValue) Validate() error {
+	if math.IsNaN(value.Accuracy) || value.Accuracy <= 0 || value.Accuracy >= 1 {
+		return fmt.Errorf("summary accuracy must be in range (0, 1)")
+	}
+
+	total := value.ZeroCount
+	if value.ZeroCount < 0 {
+		return fmt.Errorf("summary bin count cannot be negative")
+	}
+
+	for _, bins := range []SummaryBins{value.Positive, value.Negative} {
+		if len(bins.Counts) > MaxSummaryBins {
+			return fmt.Errorf("summary cannot have more than %d bins", MaxSummaryBins)
+		}
+		if bins.Offset < -maxSummaryIndex || bins.Offset > maxSummaryIndex {
+			return fmt.Errorf("summary bin offset is out of range")
+		}
+
+		for _, count := range bins.Counts {
+			if count < 0 {
+				return fmt.Errorf("summary bin count cannot be negative")
+			}
+			total += count
+		}
+	}
+
+	if total != value.Count {
+		return fmt.Errorf("summary count %d doesn't match sum of bin counts %d", value.Count, total)
+	}
+
+	if value.Count > 0 {
+		if math.IsNaN(value.Min) || math.IsNaN(value.Max) || value.Min > value.Max {
+			return fmt.Errorf("summary min must not be greater than max")
+		}
+	}
+
+	return nil
+}
+
+// Clone возвращает глубокую копию значения.
+func (value SummaryValue) Clone() SummaryValue {
+	value.Positive.Counts = slices.Clone(value.Positive.Counts)
+	value.Negative.Counts = slices.Clone(value.Negative.Counts)
+	return value
+}
+
+// Merge возвращает объединение двух скетчей с одинаковой точностью.
+// Пустое значение (без точности и значений) считается нейтральным элементом.
+func (value SummaryValue) Merge(other SummaryValue) (SummaryValue, error) {
+	if value.isZero() {
+		return other.Clone(), nil
+	}
+	if other.isZero() {
+		return value.Clone(), nil
+	}
+
+	if value.Accuracy != other.Accuracy {
+		return SummaryValue{}, ErrSummaryAccuracyMismatch
+	}
+
+	merged := SummaryValue{
+		Positive:  value.Positive.merge(other.Positive),
+		Negative:  value.Negative.merge(other.Negative),
+		Accuracy:  value.Accuracy,
+		ZeroCount: value.ZeroCount + other.ZeroCount,
+		Count:     value.Count + other.Count,
+		Sum:       value.Sum + other.Sum,
+		Min:       value.Min,
+		Max:       value.Max,
+	}
+
+	switch {
+	case value.Count == 0:
+		merged.Min, merged.Max = other.Min, other.Max
+	case other.Count > 0:
+		merged.Min = math.Min(value.Min, other.Min)
+		merged.Max = math.Max(value.Max, other.Max)
+	}
+
+	return merged, nil
+}
+
+// Quantile оценивает квантиль q (0 <= q <= 1) с относительной
+// погрешностью не более Accuracy. Для пустого скетча возвращается NaN.
+func (value SummaryValue) Quantile(q float64) float64 {
+	if value.Count == 0 || math.IsNaN(q) || q < 0 || q > 1 {
+		return math.NaN()
+	}
+
+	if q == 0 {
+		return value.Min
+	}
+	if q == 1 {
+		return value.Max
+	}
+
+	rank := q * float64(value.Count-1)
+
+	var cumulative int64
+	found := func(count int64) bool {
+		cumulative += count
+		return float64(cumulative) > rank
+	}
+
+	// Отрицательные значения упорядочены по убыванию модуля.
+	for i := len(value.Negative.Counts) - 1; i >= 0; i-- {
+		if found(value.Negative.Counts[i]) {
+			return value.clamp(-value.binValue(value.Negative.Offset + i))
+		}
+	}
+
+	if found(value.ZeroCount) {
+		return value.clamp(0)
+	}
+
+	for i, count := range value.Positive.Counts {
+		if found(count) {
+			return value.clamp(value.binValue(value.Positive.Offset + i))
+		}
+	}
+
+	// Попасть сюда невозможно, т.к. Count равен сумме значений в интервалах.
+	return value.Max
+}
+
+// clamp ограничивает оценку v наблюдавшимися минимумом и максимумом.
+func (value SummaryValue) clamp(v float64) float64 {
+	return math.Max(value.Min, math.Min(value.Max, v))
+}
+
+// gamma возвращает основание логарифмической шкалы интервалов.
+func (value SummaryValue) gamma() float64 {
+	return (1 + value.Accuracy) / (1 - value.Accuracy)
+}
+
+// index возвращает индекс интервала для положительного значения v.
+func (value SummaryValue) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / math.Log(value.gamma())))
+}
+
+// binValue возвращает значение, представляющее интервал с индексом index.
+// Его относительное отклонение от любого значения интервала не превышает Accuracy.
+func (value SummaryValue) binValue(index int) float64 {
+	gamma := value.gamma()
+	return 2 * math.Pow(gamma, float64(index)) / (gamma + 1)
+}
+
+func (value SummaryValue) isZero() bool {
+	return value.Accuracy == 0 && value.Count == 0 && len(value.Positive.Counts) == 0 && len(value.Negative.Counts) == 0
+}
+
+// add возвращает интервалы, в которых к интервалу
+// с индексом index добавлено count значений.
+func (bins SummaryBins) add(index int, count int64) SummaryBins {
+	if len(bins.Counts) > 0 && index >= bins.Offset && index < bins.Offset+len(bins.Counts) {
+		bins.Counts[index-bins.Offset] += count
+		return bins
+	}
+
+	return bins.merge(SummaryBins{Offset: index, Counts: []int64{count}})
+}
+
+// merge возвращает сумму интервалов. Если результат содержит больше
+// MaxSummaryBins интервалов, интервалы с наименьшими индексами объединяются.
+func (bins SummaryBins) merge(other SummaryBins) SummaryBins {
+	if len(other.Counts) == 0 {
+		return SummaryBins{Offset: bins.Offset, Counts: slices.Clone(bins.Counts)}
+	}
+	if len(bins.Counts) == 0 {
+		return SummaryBins{Offset: other.Offset, Counts: slices.Clone(other.Counts)}
+	}
+
+	high := max(bins.Offset+len(bins.Counts), other.Offset+len(other.Counts))
+	low := max(min(bins.Offset, other.Offset), high-MaxSummaryBins)
+
+	merged := SummaryBins{Offset: low, Counts: make([]int64, high-low)}
+	for _, source := range []SummaryBins{bins, other} {
+		for i, count := range source.Counts {
+			merged.Counts[max(source.Offset+i, low)-low] += count
+		}
+	}
+
+	return merged
+}
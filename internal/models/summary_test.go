@@ -0,0 +1,223 @@
+package models_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func newTestSummary(t *testing.T, values ...float64) models.SummaryValue {
+	t.Helper()
+
+	value, err := models.NewSummaryValue(models.DefaultSummaryAccuracy)
+	require.NoError(t, err)
+
+	for _, v := range values {
+		require.NoError(t, value.Add(v))
+	}
+
+	return value
+}
+
+func TestNewSummaryValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		accuracy float64
+		wantErr  bool
+	}{
+		{
+			name:     "Корректная точность",
+			accuracy: 0.01,
+		},
+		{
+			name:     "Нулевая точность",
+			accuracy: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "Точность больше единицы",
+			accuracy: 1.5,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := models.NewSummaryValue(tt.accuracy)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSummaryValue_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   models.SummaryValue
+		wantErr bool
+	}{
+		{
+			name: "Корректный скетч",
+			value: models.SummaryValue{
+				Accuracy:  0.01,
+				Positive:  models.SummaryBins{Offset: 10, Counts: []int64{1, 0, 2}},
+				ZeroCount: 1,
+				Count:     4,
+				Sum:       3,
+				Min:       0,
+				Max:       1.5,
+			},
+		},
+		{
+			name: "Количество не совпадает с интервалами",
+			value: models.SummaryValue{
+				Accuracy: 0.01,
+				Positive: models.SummaryBins{Counts: []int64{1}},
+				Count:    2,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Отрицательное количество",
+			value: models.SummaryValue{
+				Accuracy: 0.01,
+				Negative: models.SummaryBins{Counts: []int64{-1, 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Минимум больше максимума",
+			value: models.SummaryValue{
+				Accuracy: 0.01,
+				Positive: models.SummaryBins{Counts: []int64{1}},
+				Count:    1,
+				Min:      2,
+				Max:      1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Слишком много интервалов",
+			value: models.SummaryValue{
+				Accuracy: 0.01,
+				Positive: models.SummaryBins{Counts: make([]int64, models.MaxSummaryBins+1)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSummaryValue_Quantile(t *testing.T) {
+	values := make([]float64, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		values = append(values, float64(i))
+	}
+
+	value := newTestSummary(t, values...)
+
+	tests := []struct {
+		name string
+		q    float64
+		want float64
+	}{
+		{name: "Минимум", q: 0, want: 1},
+		{name: "Медиана", q: 0.5, want: 500.5},
+		{name: "95-й перцентиль", q: 0.95, want: 950.05},
+		{name: "99-й перцентиль", q: 0.99, want: 990.01},
+		{name: "Максимум", q: 1, want: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := value.Quantile(tt.q)
+			require.InEpsilon(t, tt.want, got, models.DefaultSummaryAccuracy+0.001)
+		})
+	}
+
+	t.Run("Отрицательные и нулевые значения", func(t *testing.T) {
+		value := newTestSummary(t, -10, -5, 0, 5, 5)
+
+		require.InEpsilon(t, -10, value.Quantile(0.1), models.DefaultSummaryAccuracy)
+		require.Equal(t, float64(0), value.Quantile(0.5))
+		require.InEpsilon(t, 5, value.Quantile(0.9), models.DefaultSummaryAccuracy)
+	})
+
+	t.Run("Пустой скетч", func(t *testing.T) {
+		require.True(t, math.IsNaN(newTestSummary(t).Quantile(0.5)))
+	})
+
+	t.Run("Некорректный квантиль", func(t *testing.T) {
+		require.True(t, math.IsNaN(value.Quantile(1.5)))
+	})
+}
+
+func TestSummaryValue_Merge(t *testing.T) {
+	t.Run("Объединение скетчей", func(t *testing.T) {
+		first := newTestSummary(t, 1, 2, 3)
+		second := newTestSummary(t, 100, 200, -4)
+
+		merged, err := first.Merge(second)
+		require.NoError(t, err)
+		require.NoError(t, merged.Validate())
+
+		// Объединение эквивалентно добавлению всех значений в один скетч.
+		require.Equal(t, newTestSummary(t, 1, 2, 3, 100, 200, -4), merged)
+
+		// Исходные значения не изменяются.
+		require.Equal(t, int64(3), first.Count)
+		require.Equal(t, newTestSummary(t, 1, 2, 3), first)
+	})
+
+	t.Run("Пустое значение нейтрально", func(t *testing.T) {
+		value := newTestSummary(t, 1, 2)
+
+		merged, err := models.SummaryValue{}.Merge(value)
+		require.NoError(t, err)
+		require.Equal(t, value, merged)
+	})
+
+	t.Run("Скетч без значений сохраняет минимум и максимум", func(t *testing.T) {
+		value := newTestSummary(t, 5, 7)
+
+		merged, err := newTestSummary(t).Merge(value)
+		require.NoError(t, err)
+		require.Equal(t, float64(5), merged.Min)
+		require.Equal(t, float64(7), merged.Max)
+	})
+
+	t.Run("Разная точность", func(t *testing.T) {
+		other, err := models.NewSummaryValue(0.05)
+		require.NoError(t, err)
+		require.NoError(t, other.Add(1))
+
+		_, err = newTestSummary(t, 1).Merge(other)
+		require.ErrorIs(t, err, models.ErrSummaryAccuracyMismatch)
+	})
+
+	t.Run("Ограничение количества интервалов", func(t *testing.T) {
+		value := newTestSummary(t, 1e-8, 1e8, 1e300, 1e300)
+
+		require.LessOrEqual(t, len(value.Positive.Counts), models.MaxSummaryBins)
+		require.NoError(t, value.Validate())
+		require.Equal(t, int64(4), value.Count)
+
+		// Наибольшие значения сохраняют точность.
+		require.InEpsilon(t, 1e300, value.Quantile(0.9), models.DefaultSummaryAccuracy)
+	})
+}
@@ -0,0 +1,98 @@
+// Package proto содержит protobuf-описание gRPC-сервиса метрик
+// и вспомогательные функции для работы с ним.
+//
+// Код в файлах *.pb.go сгенерирован скриптом gen_proto.sh.
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// HashMetadataKey ключ метаданных gRPC-запроса,
+// в котором передаётся хеш SHA-256 сообщения.
+const HashMetadataKey = "hashsha256"
+
+// CalcHash вычисляет хеш SHA-256 от детерминированно
+// сериализованного сообщения.
+func CalcHash(msg proto.Message, key string) (string, error) {
+	msgBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return tools.CalcSHA256(msgBytes, key)
+}
+
+// FromMetricType преобразует тип метрики в protobuf-представление.
+func FromMetricType(metricType models.MetricType) Metric_MType {
+	switch metricType {
+	case models.Gauge:
+		return Metric_GAUGE
+	case models.Counter:
+		return Metric_COUNTER
+	default:
+		return Metric_UNKNOWN
+	}
+}
+
+// ToMetricType преобразует protobuf-представление типа метрики.
+func ToMetricType(metricType Metric_MType) (models.MetricType, error) {
+	switch metricType {
+	case Metric_GAUGE:
+		return models.Gauge, nil
+	case Metric_COUNTER:
+		return models.Counter, nil
+	default:
+		return "", fmt.Errorf("unknown metric type: %q", metricType)
+	}
+}
+
+// FromMetricInfo преобразует метрику в protobuf-представление.
+func FromMetricInfo(metric models.MetricInfo) *Metric {
+	return &Metric{
+		Id:     metric.ID(),
+		Type:   FromMetricType(metric.Type()),
+		Delta:  metric.CounterValue(),
+		Value:  metric.GaugeValue(),
+		Labels: metric.Labels(),
+	}
+}
+
+// ToMetricInfo преобразует protobuf-представление метрики.
+func ToMetricInfo(metric *Metric) (models.MetricInfo, error) {
+	metricType, err := ToMetricType(metric.GetType())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	err = models.ValidateMetricID(metric.GetId())
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	if metricType == models.Counter {
+		return models.NewCounterMetric(metric.GetId(), metric.GetDelta()).WithLabels(metric.GetLabels()), nil
+	}
+
+	return models.NewGaugeMetric(metric.GetId(), metric.GetValue()).WithLabels(metric.GetLabels()), nil
+}
+
+// ToMetricInfos преобразует protobuf-представления метрик.
+func ToMetricInfos(metrics []*Metric) ([]models.MetricInfo, error) {
+	result := make([]models.MetricInfo, len(metrics))
+	for i, metric := range metrics {
+		info, err := ToMetricInfo(metric)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = info
+	}
+
+	return result, nil
+}
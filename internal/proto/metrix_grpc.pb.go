@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: metrix.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Metrix_UpdateMetrics_FullMethodName = "/metrix.Metrix/UpdateMetrics"
+	Metrix_GetMetric_FullMethodName     = "/metrix.Metrix/GetMetric"
+	Metrix_PushMetrics_FullMethodName   = "/metrix.Metrix/PushMetrics"
+)
+
+// MetrixClient is the client API for Metrix service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Metrix сервис сбора метрик.
+type MetrixClient interface {
+	// UpdateMetrics батчевое обновление метрик.
+	UpdateMetrics(ctx context.Context, in *UpdateMetricsRequest, opts ...grpc.CallOption) (*UpdateMetricsResponse, error)
+	// GetMetric получение метрики по идентификатору и типу.
+	GetMetric(ctx context.Context, in *GetMetricRequest, opts ...grpc.CallOption) (*GetMetricResponse, error)
+	// PushMetrics потоковое обновление метрик.
+	PushMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PushMetricsRequest, PushMetricsResponse], error)
+}
+
+type metrixClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMetrixClient(cc grpc.ClientConnInterface) MetrixClient {
+	return &metrixClient{cc}
+}
+
+func (c *metrixClient) UpdateMetrics(ctx context.Context, in *UpdateMetricsRequest, opts ...grpc.CallOption) (*UpdateMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateMetricsResponse)
+	err := c.cc.Invoke(ctx, Metrix_UpdateMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metrixClient) GetMetric(ctx context.Context, in *GetMetricRequest, opts ...grpc.CallOption) (*GetMetricResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetricResponse)
+	err := c.cc.Invoke(ctx, Metrix_GetMetric_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metrixClient) PushMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PushMetricsRequest, PushMetricsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Metrix_ServiceDesc.Streams[0], Metrix_PushMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PushMetricsRequest, PushMetricsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Metrix_PushMetricsClient = grpc.ClientStreamingClient[PushMetricsRequest, PushMetricsResponse]
+
+// MetrixServer is the server API for Metrix service.
+// All implementations must embed UnimplementedMetrixServer
+// for forward compatibility.
+//
+// Metrix сервис сбора метрик.
+type MetrixServer interface {
+	// UpdateMetrics батчевое обновление метрик.
+	UpdateMetrics(context.Context, *UpdateMetricsRequest) (*UpdateMetricsResponse, error)
+	// GetMetric получение метрики по идентификатору и типу.
+	GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error)
+	// PushMetrics потоковое обновление метрик.
+	PushMetrics(grpc.ClientStreamingServer[PushMetricsRequest, PushMetricsResponse]) error
+	mustEmbedUnimplementedMetrixServer()
+}
+
+// UnimplementedMetrixServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMetrixServer struct{}
+
+func (UnimplementedMetrixServer) UpdateMetrics(context.Context, *UpdateMetricsRequest) (*UpdateMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMetrics not implemented")
+}
+func (UnimplementedMetrixServer) GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetric not implemented")
+}
+func (UnimplementedMetrixServer) PushMetrics(grpc.ClientStreamingServer[PushMetricsRequest, PushMetricsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PushMetrics not implemented")
+}
+func (UnimplementedMetrixServer) mustEmbedUnimplementedMetrixServer() {}
+func (UnimplementedMetrixServer) testEmbeddedByValue()                {}
+
+// UnsafeMetrixServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MetrixServer will
+// result in compilation errors.
+type UnsafeMetrixServer interface {
+	mustEmbedUnimplementedMetrixServer()
+}
+
+func RegisterMetrixServer(s grpc.ServiceRegistrar, srv MetrixServer) {
+	// If the following call pancis, it indicates UnimplementedMetrixServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Metrix_ServiceDesc, srv)
+}
+
+func _Metrix_UpdateMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetrixServer).UpdateMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Metrix_UpdateMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetrixServer).UpdateMetrics(ctx, req.(*UpdateMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Metrix_GetMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetrixServer).GetMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Metrix_GetMetric_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetrixServer).GetMetric(ctx, req.(*GetMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Metrix_PushMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MetrixServer).PushMetrics(&grpc.GenericServerStream[PushMetricsRequest, PushMetricsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Metrix_PushMetricsServer = grpc.ClientStreamingServer[PushMetricsRequest, PushMetricsResponse]
+
+// Metrix_ServiceDesc is the grpc.ServiceDesc for Metrix service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Metrix_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "metrix.Metrix",
+	HandlerType: (*MetrixServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateMetrics",
+			Handler:    _Metrix_UpdateMetrics_Handler,
+		},
+		{
+			MethodName: "GetMetric",
+			Handler:    _Metrix_GetMetric_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushMetrics",
+			Handler:       _Metrix_PushMetrics_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "metrix.proto",
+}
@@ -0,0 +1,97 @@
+// Package graphite содержит парсер метрик
+// в формате Graphite plaintext: "path[;tag=value...] value [timestamp]".
+//
+// Теги Graphite становятся метками метрики.
+// Все метрики сохраняются как метрики типа Gauge. Время, переданное
+// клиентом, проверяется, но не используется: сервер фиксирует
+// время получения значения самостоятельно.
+package graphite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// Parse разбирает строки, разделённые переводом строки.
+// Пустые строки пропускаются.
+func Parse(data []byte) ([]models.MetricInfo, []protocols.LineError) {
+	var (
+		metrics []models.MetricInfo
+		errs    []protocols.LineError
+	)
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		metric, err := ParseLine(raw)
+		if err != nil {
+			errs = append(errs, protocols.LineError{Line: i + 1, Err: err})
+			continue
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, errs
+}
+
+// ParseLine разбирает одну строку вида "path[;tag=value...] value [timestamp]".
+func ParseLine(raw string) (models.MetricInfo, error) {
+	fields := strings.Fields(raw)
+	if len(fields) < 2 || len(fields) > 3 {
+		return models.MetricInfo{}, fmt.Errorf("expected \"path value [timestamp]\", got %d fields", len(fields))
+	}
+
+	path, rawTags, _ := strings.Cut(fields[0], ";")
+
+	err := models.ValidateMetricID(path)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	labels, err := parseTags(rawTags)
+	if err != nil {
+		return models.MetricInfo{}, err
+	}
+
+	value, err := tools.StrToFloat(fields[1])
+	if err != nil {
+		return models.MetricInfo{}, fmt.Errorf("invalid metric value")
+	}
+
+	if len(fields) == 3 {
+		_, err = strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid timestamp")
+		}
+	}
+
+	return models.NewGaugeMetric(path, value).WithLabels(labels), nil
+}
+
+// parseTags разбирает теги вида "tag=value;tag=value".
+func parseTags(rawTags string) (models.Labels, error) {
+	if rawTags == "" {
+		return nil, nil
+	}
+
+	labels := make(models.Labels)
+	for _, tag := range strings.Split(rawTags, ";") {
+		name, value, found := strings.Cut(tag, "=")
+		if !found || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+
+		labels[name] = value
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,40 @@
+package graphite_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/graphite"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte("servers.web01.cpu 12.5 1735678800\n" +
+		"\n" +
+		"servers.web01.memory 2048\n" +
+		"servers.web01.disk\n" +
+		"servers.web01.load abc 1735678800\n" +
+		"servers.web01.requests;env=prod;dc=eu 7 1735678800\n" +
+		"servers.web01.errors;env 1\n" +
+		"servers.web01.uptime 100 yesterday\n")
+
+	metrics, errs := graphite.Parse(data)
+
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("servers.web01.cpu", 12.5),
+		models.NewGaugeMetric("servers.web01.memory", 2048),
+		models.NewGaugeMetric("servers.web01.requests", 7).WithLabels(models.Labels{"env": "prod", "dc": "eu"}),
+	}, metrics)
+
+	lines := make([]int, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Line
+	}
+	require.Equal(t, []int{4, 5, 7, 8}, lines)
+}
+
+func TestParseLine_EmptyPath(t *testing.T) {
+	_, err := graphite.ParseLine(";env=prod 1")
+	require.ErrorIs(t, err, models.ErrEmptyMetricID)
+}
@@ -0,0 +1,209 @@
+// Package influx содержит парсер метрик в формате InfluxDB line protocol:
+// "measurement[,tag=value...] field=value[,field=value...] [timestamp]".
+//
+// Каждое поле строки становится отдельной метрикой с идентификатором
+// measurement_field (или measurement для поля value). Числовые поля,
+// как целочисленные (1i, 1u), так и дробные, сохраняются как метрики типа
+// Gauge: line protocol передаёт текущее значение поля, а не приращение.
+// Строковые и логические поля не поддерживаются. Теги строки становятся метками всех её метрик.
+// Время, переданное клиентом, проверяется,
+// но не используется: сервер фиксирует время получения значения самостоятельно.
+package influx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// ErrUnsupportedFieldType ошибка неподдерживаемого типа поля.
+var ErrUnsupportedFieldType = errors.New("unsupported field type")
+
+// Parse разбирает строки, разделённые переводом строки.
+// Пустые строки и комментарии пропускаются.
+//
+// Если в строке есть поля неподдерживаемого типа, для них возвращается
+// ошибка, а остальные поля строки сохраняются.
+func Parse(data []byte) ([]models.MetricInfo, []protocols.LineError) {
+	var (
+		metrics []models.MetricInfo
+		errs    []protocols.LineError
+	)
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+
+		lineMetrics, err := ParseLine(raw)
+		if err != nil {
+			errs = append(errs, protocols.LineError{Line: i + 1, Err: err})
+		}
+
+		metrics = append(metrics, lineMetrics...)
+	}
+
+	return metrics, errs
+}
+
+// ParseLine разбирает одну строку. Может вернуть одновременно
+// метрики и ошибку, если часть полей не удалось разобрать.
+func ParseLine(raw string) ([]models.MetricInfo, error) {
+	sections := split(raw, ' ')
+	if len(sections) < 2 || len(sections) > 3 {
+		return nil, fmt.Errorf("expected \"measurement[,tags] fields [timestamp]\"")
+	}
+
+	if len(sections) == 3 {
+		_, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp")
+		}
+	}
+
+	series := split(sections[0], ',')
+	measurement := unescape(series[0])
+
+	err := models.ValidateMetricID(measurement)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(models.Labels, len(series)-1)
+	for _, tag := range series[1:] {
+		rawKey, rawValue, found := cutUnescaped(tag, '=')
+		if !found || rawKey == "" || rawValue == "" {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+
+		labels[unescape(rawKey)] = unescape(rawValue)
+	}
+
+	var (
+		errs    []error
+		metrics []models.MetricInfo
+	)
+
+	for _, field := range split(sections[1], ',') {
+		rawKey, rawValue, found := cutUnescaped(field, '=')
+		if !found || rawKey == "" || rawValue == "" {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+
+		id := measurement
+		if key := unescape(rawKey); key != "value" {
+			id += "_" + key
+		}
+
+		metric, fieldErr := parseField(id, rawValue)
+		if fieldErr != nil {
+			if errors.Is(fieldErr, ErrUnsupportedFieldType) {
+				errs = append(errs, fmt.Errorf("field %q: %w", rawKey, fieldErr))
+				continue
+			}
+
+			return nil, fmt.Errorf("field %q: %v", rawKey, fieldErr)
+		}
+
+		metrics = append(metrics, metric.WithLabels(labels))
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+// parseField разбирает значение поля и создаёт метрику
+// соответствующего типа.
+func parseField(id, rawValue string) (models.MetricInfo, error) {
+	switch {
+	case strings.HasPrefix(rawValue, `"`):
+		return models.MetricInfo{}, fmt.Errorf("%w: string", ErrUnsupportedFieldType)
+	case isBool(rawValue):
+		return models.MetricInfo{}, fmt.Errorf("%w: boolean", ErrUnsupportedFieldType)
+	case strings.HasSuffix(rawValue, "i"):
+		value, err := strconv.ParseInt(strings.TrimSuffix(rawValue, "i"), 10, 64)
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid integer value")
+		}
+
+		return models.NewGaugeMetric(id, float64(value)), nil
+	case strings.HasSuffix(rawValue, "u"):
+		value, err := strconv.ParseUint(strings.TrimSuffix(rawValue, "u"), 10, 64)
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid unsigned value")
+		}
+
+		return models.NewGaugeMetric(id, float64(value)), nil
+	default:
+		value, err := tools.StrToFloat(rawValue)
+		if err != nil {
+			return models.MetricInfo{}, fmt.Errorf("invalid float value")
+		}
+
+		return models.NewGaugeMetric(id, value), nil
+	}
+}
+
+func isBool(v string) bool {
+	switch v {
+	case "t", "T", "true", "True", "TRUE", "f", "F", "false", "False", "FALSE":
+		return true
+	default:
+		return false
+	}
+}
+
+// split разбивает строку по разделителю sep, пропуская
+// экранированные разделители и разделители внутри кавычек.
+// Экранирование в частях сохраняется.
+func split(s string, sep byte) []string {
+	var (
+		parts    []string
+		start    int
+		inQuotes bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			inQuotes = !inQuotes
+		case sep:
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// cutUnescaped делит строку по первому неэкранированному разделителю sep.
+func cutUnescaped(s string, sep byte) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+// unescape удаляет экранирование пробелов, запятых и знаков равенства.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	return strings.NewReplacer(`\ `, " ", `\,`, ",", `\=`, "=", `\\`, `\`).Replace(s)
+}
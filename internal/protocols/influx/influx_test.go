@@ -0,0 +1,108 @@
+package influx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/influx"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []models.MetricInfo
+		wantErr bool
+	}{
+		{
+			name: "Дробное поле value",
+			raw:  "cpu,host=web01 value=12.5 1735678800000000000",
+			want: []models.MetricInfo{models.NewGaugeMetric("cpu", 12.5).WithLabels(models.Labels{"host": "web01"})},
+		},
+		{
+			name: "Несколько полей разных типов",
+			raw:  "mem,host=web01 used=1024i,free=2048u,percent=33.3",
+			want: []models.MetricInfo{
+				models.NewGaugeMetric("mem_used", 1024).WithLabels(models.Labels{"host": "web01"}),
+				models.NewGaugeMetric("mem_free", 2048).WithLabels(models.Labels{"host": "web01"}),
+				models.NewGaugeMetric("mem_percent", 33.3).WithLabels(models.Labels{"host": "web01"}),
+			},
+		},
+		{
+			name: "Экранирование",
+			raw:  `disk\ io,path=/var\,log read\ bytes=5i`,
+			want: []models.MetricInfo{models.NewGaugeMetric("disk io_read bytes", 5).WithLabels(models.Labels{"path": "/var,log"})},
+		},
+		{
+			name:    "Невалидный тег",
+			raw:     "cpu,host value=1",
+			wantErr: true,
+		},
+		{
+			name:    "Строковое поле пропускается с ошибкой",
+			raw:     `service status="ok, running",latency=0.25`,
+			want:    []models.MetricInfo{models.NewGaugeMetric("service_latency", 0.25)},
+			wantErr: true,
+		},
+		{
+			name:    "Логическое поле пропускается с ошибкой",
+			raw:     "service healthy=true",
+			wantErr: true,
+		},
+		{
+			name:    "Отсутствуют поля",
+			raw:     "cpu,host=web01",
+			wantErr: true,
+		},
+		{
+			name:    "Пустое имя",
+			raw:     ",host=web01 value=1",
+			wantErr: true,
+		},
+		{
+			name:    "Отрицательное беззнаковое значение",
+			raw:     "mem free=-1u",
+			wantErr: true,
+		},
+		{
+			name:    "Невалидное значение",
+			raw:     "cpu value=abc",
+			wantErr: true,
+		},
+		{
+			name:    "Невалидное время",
+			raw:     "cpu value=1 now",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := influx.ParseLine(tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := []byte("# комментарий\n" +
+		"cpu value=1\n" +
+		"cpu value=oops\n" +
+		"\n" +
+		"mem used=10i\n")
+
+	metrics, errs := influx.Parse(data)
+
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("cpu", 1),
+		models.NewGaugeMetric("mem_used", 10),
+	}, metrics)
+	require.Len(t, errs, 1)
+	require.Equal(t, 3, errs[0].Line)
+}
@@ -0,0 +1,319 @@
+// Package otlp содержит модели запроса OpenTelemetry OTLP/HTTP
+// в JSON-кодировке и их преобразование в метрики.
+//
+// Поддерживаются метрики Gauge и Sum: монотонные суммы сохраняются
+// как метрики типа Counter, немонотонные - как метрики типа Gauge.
+// Атрибуты ресурса и значения сохраняются в виде меток метрики.
+// Гистограммы и сводки (summary) не поддерживаются и отклоняются.
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// DefaultStaleAfter время, по истечении которого забываются
+// последние значения накопительных сумм, не получавших обновлений.
+const DefaultStaleAfter = time.Hour
+
+// Storage хранилище, в которое записываются преобразованные метрики.
+type Storage interface {
+	GetCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error)
+	UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error
+}
+
+// ConverterOptions параметры преобразователя метрик.
+type ConverterOptions struct {
+	// Storage хранилище, в которое записываются метрики.
+	Storage Storage
+	// StaleAfter время, по истечении которого забывается последнее
+	// значение накопительной суммы, не получавшей обновлений.
+	StaleAfter time.Duration
+}
+
+// NewConverter создаёт новый преобразователь метрик.
+func NewConverter(opts ConverterOptions) *Converter {
+	return &Converter{
+		storage:    opts.Storage,
+		cumulative: make(map[string]cumulativePoint),
+		staleAfter: opts.StaleAfter,
+	}
+}
+
+// Converter преобразует OTLP-метрики в метрики metrix
+// и записывает их в хранилище.
+//
+// Для накопительных (cumulative) монотонных сумм хранятся последние
+// записанные значения, чтобы передавать в хранилище приращения.
+// Последние значения обновляются только после успешной записи,
+// поэтому повторная отправка отклонённого запроса не теряет приращения.
+// Уменьшение значения считается перезапуском источника.
+//
+// Первое значение ряда считается приращением от нуля, только если ряда
+// нет в хранилище. Иначе (после перезапуска сервера или забывания ряда)
+// значение служит точкой отсчёта, чтобы не учесть накопленную сумму дважды.
+type Converter struct {
+	storage    Storage
+	cumulative map[string]cumulativePoint
+	// lastEviction время последнего удаления забытых рядов.
+	lastEviction time.Time
+	// mx удерживается на время преобразования и записи запроса,
+	// чтобы приращения одного ряда вычислялись последовательно.
+	mx         sync.Mutex
+	staleAfter time.Duration
+}
+
+// cumulativePoint последнее записанное значение накопительной суммы.
+type cumulativePoint struct {
+	seenAt time.Time
+	value  int64
+}
+
+// ConvertResult результат преобразования запроса.
+type ConvertResult struct {
+	Metrics []models.MetricInfo
+	Errors  []string
+	// Rejected количество отклонённых значений.
+	Rejected int64
+}
+
+// Export преобразует запрос в метрики и записывает их в хранилище.
+// Значения, которые не удалось преобразовать, отклоняются,
+// не мешая сохранению остальных.
+func (c *Converter) Export(ctx context.Context, req ExportMetricsServiceRequest) (ConvertResult, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	now := time.Now()
+	c.evictStale(now)
+
+	// Значения накопительных сумм запроса, применяемые после записи.
+	pending := make(map[string]int64)
+
+	result, err := c.convert(ctx, req, pending)
+	if err != nil {
+		return ConvertResult{}, err
+	}
+
+	err = c.storage.UpdateMetrics(ctx, result.Metrics)
+	if err != nil {
+		return ConvertResult{}, err
+	}
+
+	for key, value := range pending {
+		c.cumulative[key] = cumulativePoint{seenAt: now, value: value}
+	}
+
+	return result, nil
+}
+
+// evictStale забывает ряды, не получавшие обновлений дольше staleAfter.
+// Проверка выполняется не чаще, чем раз в staleAfter.
+//
+// Вызывающая сторона должна удерживать блокировку mx.
+func (c *Converter) evictStale(now time.Time) {
+	if now.Sub(c.lastEviction) < c.staleAfter {
+		return
+	}
+
+	for key, point := range c.cumulative {
+		if now.Sub(point.seenAt) > c.staleAfter {
+			delete(c.cumulative, key)
+		}
+	}
+
+	c.lastEviction = now
+}
+
+// convert преобразует запрос в метрики. Новые значения
+// накопительных сумм сохраняются в pending.
+func (c *Converter) convert(ctx context.Context, req ExportMetricsServiceRequest, pending map[string]int64) (ConvertResult, error) {
+	var result ConvertResult
+
+	reject := func(metric Metric, count int, reason string) {
+		result.Rejected += int64(count)
+		result.Errors = append(result.Errors, fmt.Sprintf("metric %q: %s", metric.Name, reason))
+	}
+
+	for _, rm := range req.ResourceMetrics {
+		resourceLabels := attributesToLabels(rm.Resource.Attributes)
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				if err := models.ValidateMetricID(metric.Name); err != nil {
+					reject(metric, countDataPoints(metric), err.Error())
+					continue
+				}
+
+				switch {
+				case metric.Gauge != nil:
+					c.convertGauge(&result, metric.Name, resourceLabels, metric.Gauge.DataPoints)
+				case metric.Sum != nil && metric.Sum.IsMonotonic:
+					err := c.convertMonotonicSum(ctx, &result, metric.Name, resourceLabels, metric.Sum, pending)
+					if err != nil {
+						return ConvertResult{}, err
+					}
+				case metric.Sum != nil:
+					c.convertGauge(&result, metric.Name, resourceLabels, metric.Sum.DataPoints)
+				default:
+					reject(metric, countDataPoints(metric), "unsupported metric type")
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Converter) convertGauge(result *ConvertResult, name string, resourceLabels models.Labels, points []NumberDataPoint) {
+	for _, point := range points {
+		value, err := point.value()
+		if err != nil {
+			result.Rejected++
+			result.Errors = append(result.Errors, fmt.Sprintf("metric %q: %v", name, err))
+			continue
+		}
+
+		labels := mergeLabels(resourceLabels, attributesToLabels(point.Attributes))
+		result.Metrics = append(result.Metrics, models.NewGaugeMetric(name, value).WithLabels(labels))
+	}
+}
+
+func (c *Converter) convertMonotonicSum(ctx context.Context, result *ConvertResult, name string, resourceLabels models.Labels, sum *Sum, pending map[string]int64) error {
+	for _, point := range sum.DataPoints {
+		value, err := point.value()
+		if err != nil {
+			result.Rejected++
+			result.Errors = append(result.Errors, fmt.Sprintf("metric %q: %v", name, err))
+			continue
+		}
+
+		labels := mergeLabels(resourceLabels, attributesToLabels(point.Attributes))
+		delta := int64(math.Round(value))
+
+		if sum.AggregationTemporality == AggregationTemporalityCumulative {
+			delta, err = c.cumulativeDelta(ctx, name, labels, delta, pending)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Metrics = append(result.Metrics, models.NewCounterMetric(name, delta).WithLabels(labels))
+	}
+
+	return nil
+}
+
+// cumulativeDelta вычисляет приращение накопительного значения value
+// и сохраняет его в pending.
+//
+// Вызывающая сторона должна удерживать блокировку mx.
+func (c *Converter) cumulativeDelta(ctx context.Context, name string, labels models.Labels, value int64, pending map[string]int64) (int64, error) {
+	key := name + "{" + labels.Key() + "}"
+
+	prev, exists := pending[key]
+	if !exists {
+		var point cumulativePoint
+		point, exists = c.cumulative[key]
+		prev = point.value
+	}
+
+	if !exists {
+		_, err := c.storage.GetCounterMetric(ctx, name, labels)
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			// Новый ряд: значение накоплено с нуля.
+			prev = 0
+		case err != nil:
+			return 0, err
+		default:
+			// Накопленная сумма уже учтена в хранилище.
+			prev = value
+		}
+	}
+
+	pending[key] = value
+
+	if value < prev {
+		return value, nil
+	}
+
+	return value - prev, nil
+}
+
+// value возвращает значение точки.
+func (point NumberDataPoint) value() (float64, error) {
+	switch {
+	case point.AsDouble != nil:
+		return *point.AsDouble, nil
+	case point.AsInt != nil:
+		value, err := point.AsInt.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer value %q", point.AsInt.String())
+		}
+
+		return float64(value), nil
+	default:
+		return 0, fmt.Errorf("data point value is missing")
+	}
+}
+
+func countDataPoints(metric Metric) int {
+	switch {
+	case metric.Gauge != nil:
+		return len(metric.Gauge.DataPoints)
+	case metric.Sum != nil:
+		return len(metric.Sum.DataPoints)
+	case metric.Histogram != nil:
+		return len(metric.Histogram.DataPoints)
+	case metric.ExponentialHistogram != nil:
+		return len(metric.ExponentialHistogram.DataPoints)
+	case metric.Summary != nil:
+		return len(metric.Summary.DataPoints)
+	default:
+		return 0
+	}
+}
+
+// attributesToLabels преобразует атрибуты в метки.
+// Атрибуты с неподдерживаемыми значениями пропускаются.
+func attributesToLabels(attributes []KeyValue) models.Labels {
+	labels := make(models.Labels, len(attributes))
+
+	for _, attr := range attributes {
+		switch value := attr.Value; {
+		case value.StringValue != nil:
+			labels[attr.Key] = *value.StringValue
+		case value.BoolValue != nil:
+			labels[attr.Key] = fmt.Sprint(*value.BoolValue)
+		case value.IntValue != nil:
+			labels[attr.Key] = value.IntValue.String()
+		case value.DoubleValue != nil:
+			labels[attr.Key] = tools.FloatToStr(*value.DoubleValue)
+		}
+	}
+
+	return labels
+}
+
+// mergeLabels объединяет метки ресурса и значения.
+// Метки значения имеют приоритет.
+func mergeLabels(resourceLabels, pointLabels models.Labels) models.Labels {
+	labels := make(models.Labels, len(resourceLabels)+len(pointLabels))
+
+	for k, v := range resourceLabels {
+		labels[k] = v
+	}
+	for k, v := range pointLabels {
+		labels[k] = v
+	}
+
+	return labels
+}
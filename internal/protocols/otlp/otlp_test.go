@@ -0,0 +1,196 @@
+package otlp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/otlp"
+)
+
+const exportRequest = `{
+  "resourceMetrics": [{
+    "resource": {
+      "attributes": [
+        {"key": "service.name", "value": {"stringValue": "checkout"}},
+        {"key": "host.cpus", "value": {"intValue": "8"}}
+      ]
+    },
+    "scopeMetrics": [{
+      "metrics": [
+        {
+          "name": "memory.usage",
+          "gauge": {"dataPoints": [{"asDouble": 12.5, "attributes": [{"key": "state", "value": {"stringValue": "used"}}]}]}
+        },
+        {
+          "name": "http.requests",
+          "sum": {"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": [{"asInt": "%d"}]}
+        },
+        {
+          "name": "jobs.processed",
+          "sum": {"aggregationTemporality": "AGGREGATION_TEMPORALITY_DELTA", "isMonotonic": true, "dataPoints": [{"asInt": 3}]}
+        },
+        {
+          "name": "queue.size",
+          "sum": {"aggregationTemporality": 1, "isMonotonic": false, "dataPoints": [{"asInt": "-2"}]}
+        },
+        {
+          "name": "http.duration",
+          "histogram": {"dataPoints": [{}, {}]}
+        }
+      ]
+    }]
+  }]
+}`
+
+// memoryStorage хранилище counter в памяти.
+type memoryStorage struct {
+	counters map[string]int64
+	err      error
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{counters: make(map[string]int64)}
+}
+
+func (storage *memoryStorage) GetCounterMetric(_ context.Context, id string, labels models.Labels) (int64, error) {
+	value, exists := storage.counters[id+labels.Key()]
+	if !exists {
+		return 0, models.ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (storage *memoryStorage) UpdateMetrics(_ context.Context, metrics []models.MetricInfo) error {
+	if storage.err != nil {
+		return storage.err
+	}
+
+	for _, metric := range metrics {
+		if metric.Type() == models.Counter {
+			storage.counters[metric.ID()+metric.Labels().Key()] += metric.CounterValue()
+		}
+	}
+
+	return nil
+}
+
+func newRequest(t *testing.T, cumulativeRequests int) otlp.ExportMetricsServiceRequest {
+	var req otlp.ExportMetricsServiceRequest
+
+	raw := []byte(fmt.Sprintf(exportRequest, cumulativeRequests))
+	require.NoError(t, easyjson.Unmarshal(raw, &req))
+
+	return req
+}
+
+func TestConverter_Export(t *testing.T) {
+	ctx := context.Background()
+	resourceLabels := models.Labels{"service.name": "checkout", "host.cpus": "8"}
+
+	storage := newMemoryStorage()
+	converter := otlp.NewConverter(otlp.ConverterOptions{Storage: storage, StaleAfter: time.Hour})
+
+	export := func(cumulativeRequests int) (otlp.ConvertResult, error) {
+		return converter.Export(ctx, newRequest(t, cumulativeRequests))
+	}
+
+	requests := func() int64 {
+		value, err := storage.GetCounterMetric(ctx, "http.requests", resourceLabels)
+		require.NoError(t, err)
+		return value
+	}
+
+	result, err := export(10)
+	require.NoError(t, err)
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("memory.usage", 12.5).WithLabels(models.Labels{"service.name": "checkout", "host.cpus": "8", "state": "used"}),
+		models.NewCounterMetric("http.requests", 10).WithLabels(resourceLabels),
+		models.NewCounterMetric("jobs.processed", 3).WithLabels(resourceLabels),
+		models.NewGaugeMetric("queue.size", -2).WithLabels(resourceLabels),
+	}, result.Metrics)
+	require.Equal(t, int64(2), result.Rejected)
+	require.Len(t, result.Errors, 1)
+
+	// Для накопительной суммы передаётся приращение.
+	result, err = export(25)
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 15).WithLabels(resourceLabels), result.Metrics[1])
+
+	// Отклонённая запись не сдвигает точку отсчёта,
+	// поэтому повторная отправка сохраняет приращение.
+	storage.err = models.ErrQueueFull
+	_, err = export(30)
+	require.ErrorIs(t, err, models.ErrQueueFull)
+
+	storage.err = nil
+	result, err = export(30)
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 5).WithLabels(resourceLabels), result.Metrics[1])
+	require.Equal(t, int64(30), requests())
+
+	// Уменьшение значения считается перезапуском источника.
+	result, err = export(4)
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 4).WithLabels(resourceLabels), result.Metrics[1])
+	require.Equal(t, int64(34), requests())
+
+	// После перезапуска сервера значение ряда, уже существующего
+	// в хранилище, служит точкой отсчёта.
+	converter = otlp.NewConverter(otlp.ConverterOptions{Storage: storage, StaleAfter: time.Hour})
+
+	result, err = export(6)
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 0).WithLabels(resourceLabels), result.Metrics[1])
+
+	result, err = export(9)
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 3).WithLabels(resourceLabels), result.Metrics[1])
+	require.Equal(t, int64(37), requests())
+}
+
+func TestConverter_EvictStale(t *testing.T) {
+	ctx := context.Background()
+	resourceLabels := models.Labels{"service.name": "checkout", "host.cpus": "8"}
+
+	storage := newMemoryStorage()
+	converter := otlp.NewConverter(otlp.ConverterOptions{Storage: storage, StaleAfter: 10 * time.Millisecond})
+
+	_, err := converter.Export(ctx, newRequest(t, 10))
+	require.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Забытый ряд начинается заново от значения в хранилище.
+	result, err := converter.Export(ctx, newRequest(t, 25))
+	require.NoError(t, err)
+	require.Equal(t, models.NewCounterMetric("http.requests", 0).WithLabels(resourceLabels), result.Metrics[1])
+}
+
+func TestConverter_EmptyName(t *testing.T) {
+	var req otlp.ExportMetricsServiceRequest
+
+	raw := []byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"","gauge":{"dataPoints":[{"asDouble":1}]}}]}]}]}`)
+	require.NoError(t, easyjson.Unmarshal(raw, &req))
+
+	result, err := otlp.NewConverter(otlp.ConverterOptions{Storage: newMemoryStorage()}).Export(context.Background(), req)
+	require.NoError(t, err)
+	require.Empty(t, result.Metrics)
+	require.Equal(t, int64(1), result.Rejected)
+}
+
+func TestExportMetricsServiceResponse(t *testing.T) {
+	resp := otlp.ExportMetricsServiceResponse{
+		PartialSuccess: &otlp.ExportMetricsPartialSuccess{RejectedDataPoints: 2, ErrorMessage: "unsupported"},
+	}
+
+	raw, err := easyjson.Marshal(resp)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"partialSuccess":{"errorMessage":"unsupported","rejectedDataPoints":"2"}}`, string(raw))
+}
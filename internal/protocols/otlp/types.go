@@ -0,0 +1,150 @@
+package otlp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+//easyjson:json
+type ExportMetricsServiceRequest struct {
+	ResourceMetrics []ResourceMetrics `json:"resourceMetrics"`
+}
+
+// ResourceMetrics метрики одного ресурса (сервиса, хоста и т.п.).
+type ResourceMetrics struct {
+	Resource     Resource       `json:"resource"`
+	ScopeMetrics []ScopeMetrics `json:"scopeMetrics"`
+}
+
+// Resource описание ресурса.
+type Resource struct {
+	Attributes []KeyValue `json:"attributes"`
+}
+
+// ScopeMetrics метрики одной библиотеки инструментирования.
+type ScopeMetrics struct {
+	Metrics []Metric `json:"metrics"`
+}
+
+// Metric метрика. Заполнено ровно одно из полей с данными.
+type Metric struct {
+	Gauge                *Gauge           `json:"gauge"`
+	Sum                  *Sum             `json:"sum"`
+	Histogram            *UnsupportedData `json:"histogram"`
+	ExponentialHistogram *UnsupportedData `json:"exponentialHistogram"`
+	Summary              *UnsupportedData `json:"summary"`
+	Name                 string           `json:"name"`
+}
+
+// Gauge данные gauge-метрики.
+type Gauge struct {
+	DataPoints []NumberDataPoint `json:"dataPoints"`
+}
+
+// Sum данные суммируемой метрики.
+type Sum struct {
+	DataPoints             []NumberDataPoint      `json:"dataPoints"`
+	AggregationTemporality AggregationTemporality `json:"aggregationTemporality"`
+	IsMonotonic            bool                   `json:"isMonotonic"`
+}
+
+// UnsupportedData данные метрики неподдерживаемого типа.
+// Используется только для подсчёта отклонённых значений.
+type UnsupportedData struct {
+	DataPoints []easyjson.RawMessage `json:"dataPoints"`
+}
+
+// NumberDataPoint числовое значение метрики.
+type NumberDataPoint struct {
+	AsDouble   *float64     `json:"asDouble"`
+	AsInt      *json.Number `json:"asInt"`
+	Attributes []KeyValue   `json:"attributes"`
+}
+
+// KeyValue атрибут.
+type KeyValue struct {
+	Key   string   `json:"key"`
+	Value AnyValue `json:"value"`
+}
+
+// AnyValue значение атрибута. Массивы и вложенные
+// наборы атрибутов не поддерживаются.
+type AnyValue struct {
+	StringValue *string      `json:"stringValue"`
+	BoolValue   *bool        `json:"boolValue"`
+	IntValue    *json.Number `json:"intValue"`
+	DoubleValue *float64     `json:"doubleValue"`
+}
+
+//easyjson:json
+type ExportMetricsServiceResponse struct {
+	PartialSuccess *ExportMetricsPartialSuccess `json:"partialSuccess,omitempty"`
+}
+
+// ExportMetricsPartialSuccess описание частично принятого запроса.
+type ExportMetricsPartialSuccess struct {
+	ErrorMessage       string `json:"errorMessage"`
+	RejectedDataPoints int64  `json:"rejectedDataPoints,string"`
+}
+
+// AggregationTemporality способ агрегации значений суммируемой метрики.
+type AggregationTemporality int
+
+const (
+	// AggregationTemporalityUnspecified способ агрегации не указан.
+	AggregationTemporalityUnspecified AggregationTemporality = iota
+	// AggregationTemporalityDelta передаётся приращение с момента предыдущей выгрузки.
+	AggregationTemporalityDelta
+	// AggregationTemporalityCumulative передаётся накопленное значение.
+	AggregationTemporalityCumulative
+)
+
+var aggregationTemporalityNames = map[string]AggregationTemporality{
+	"AGGREGATION_TEMPORALITY_UNSPECIFIED": AggregationTemporalityUnspecified,
+	"AGGREGATION_TEMPORALITY_DELTA":       AggregationTemporalityDelta,
+	"AGGREGATION_TEMPORALITY_CUMULATIVE":  AggregationTemporalityCumulative,
+}
+
+// UnmarshalEasyJSON парсит способ агрегации, переданный
+// как числом, так и именем значения перечисления.
+func (t *AggregationTemporality) UnmarshalEasyJSON(in *jlexer.Lexer) {
+	if in.IsNull() {
+		in.Skip()
+		return
+	}
+
+	raw := in.Raw()
+	if len(raw) > 0 && raw[0] == '"' {
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			in.AddError(err)
+			return
+		}
+
+		value, exists := aggregationTemporalityNames[name]
+		if !exists {
+			in.AddError(fmt.Errorf("unknown aggregation temporality: %q", name))
+			return
+		}
+
+		*t = value
+		return
+	}
+
+	var value int
+	if err := json.Unmarshal(raw, &value); err != nil {
+		in.AddError(err)
+		return
+	}
+
+	*t = AggregationTemporality(value)
+}
+
+// MarshalEasyJSON сериализует способ агрегации числом.
+func (t AggregationTemporality) MarshalEasyJSON(out *jwriter.Writer) {
+	out.Int(int(t))
+}
@@ -0,0 +1,1116 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package otlp
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp(in *jlexer.Lexer, out *ExportMetricsServiceResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "partialSuccess":
+			if in.IsNull() {
+				in.Skip()
+				out.PartialSuccess = nil
+			} else {
+				if out.PartialSuccess == nil {
+					out.PartialSuccess = new(ExportMetricsPartialSuccess)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp1(in, out.PartialSuccess)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp(out *jwriter.Writer, in ExportMetricsServiceResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.PartialSuccess != nil {
+		const prefix string = ",\"partialSuccess\":"
+		first = false
+		out.RawString(prefix[1:])
+		easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp1(out, *in.PartialSuccess)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExportMetricsServiceResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExportMetricsServiceResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExportMetricsServiceResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExportMetricsServiceResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp(l, v)
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp1(in *jlexer.Lexer, out *ExportMetricsPartialSuccess) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "errorMessage":
+			out.ErrorMessage = string(in.String())
+		case "rejectedDataPoints":
+			out.RejectedDataPoints = int64(in.Int64Str())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp1(out *jwriter.Writer, in ExportMetricsPartialSuccess) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"errorMessage\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ErrorMessage))
+	}
+	{
+		const prefix string = ",\"rejectedDataPoints\":"
+		out.RawString(prefix)
+		out.Int64Str(int64(in.RejectedDataPoints))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp2(in *jlexer.Lexer, out *ExportMetricsServiceRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "resourceMetrics":
+			if in.IsNull() {
+				in.Skip()
+				out.ResourceMetrics = nil
+			} else {
+				in.Delim('[')
+				if out.ResourceMetrics == nil {
+					if !in.IsDelim(']') {
+						out.ResourceMetrics = make([]ResourceMetrics, 0, 1)
+					} else {
+						out.ResourceMetrics = []ResourceMetrics{}
+					}
+				} else {
+					out.ResourceMetrics = (out.ResourceMetrics)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 ResourceMetrics
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp3(in, &v1)
+					out.ResourceMetrics = append(out.ResourceMetrics, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp2(out *jwriter.Writer, in ExportMetricsServiceRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"resourceMetrics\":"
+		out.RawString(prefix[1:])
+		if in.ResourceMetrics == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.ResourceMetrics {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp3(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExportMetricsServiceRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExportMetricsServiceRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExportMetricsServiceRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExportMetricsServiceRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp2(l, v)
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp3(in *jlexer.Lexer, out *ResourceMetrics) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "resource":
+			easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp4(in, &out.Resource)
+		case "scopeMetrics":
+			if in.IsNull() {
+				in.Skip()
+				out.ScopeMetrics = nil
+			} else {
+				in.Delim('[')
+				if out.ScopeMetrics == nil {
+					if !in.IsDelim(']') {
+						out.ScopeMetrics = make([]ScopeMetrics, 0, 2)
+					} else {
+						out.ScopeMetrics = []ScopeMetrics{}
+					}
+				} else {
+					out.ScopeMetrics = (out.ScopeMetrics)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 ScopeMetrics
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp5(in, &v4)
+					out.ScopeMetrics = append(out.ScopeMetrics, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp3(out *jwriter.Writer, in ResourceMetrics) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"resource\":"
+		out.RawString(prefix[1:])
+		easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp4(out, in.Resource)
+	}
+	{
+		const prefix string = ",\"scopeMetrics\":"
+		out.RawString(prefix)
+		if in.ScopeMetrics == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.ScopeMetrics {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp5(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp5(in *jlexer.Lexer, out *ScopeMetrics) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "metrics":
+			if in.IsNull() {
+				in.Skip()
+				out.Metrics = nil
+			} else {
+				in.Delim('[')
+				if out.Metrics == nil {
+					if !in.IsDelim(']') {
+						out.Metrics = make([]Metric, 0, 1)
+					} else {
+						out.Metrics = []Metric{}
+					}
+				} else {
+					out.Metrics = (out.Metrics)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 Metric
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp6(in, &v7)
+					out.Metrics = append(out.Metrics, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp5(out *jwriter.Writer, in ScopeMetrics) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"metrics\":"
+		out.RawString(prefix[1:])
+		if in.Metrics == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Metrics {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp6(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp6(in *jlexer.Lexer, out *Metric) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "gauge":
+			if in.IsNull() {
+				in.Skip()
+				out.Gauge = nil
+			} else {
+				if out.Gauge == nil {
+					out.Gauge = new(Gauge)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp7(in, out.Gauge)
+			}
+		case "sum":
+			if in.IsNull() {
+				in.Skip()
+				out.Sum = nil
+			} else {
+				if out.Sum == nil {
+					out.Sum = new(Sum)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp8(in, out.Sum)
+			}
+		case "histogram":
+			if in.IsNull() {
+				in.Skip()
+				out.Histogram = nil
+			} else {
+				if out.Histogram == nil {
+					out.Histogram = new(UnsupportedData)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp9(in, out.Histogram)
+			}
+		case "exponentialHistogram":
+			if in.IsNull() {
+				in.Skip()
+				out.ExponentialHistogram = nil
+			} else {
+				if out.ExponentialHistogram == nil {
+					out.ExponentialHistogram = new(UnsupportedData)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp9(in, out.ExponentialHistogram)
+			}
+		case "summary":
+			if in.IsNull() {
+				in.Skip()
+				out.Summary = nil
+			} else {
+				if out.Summary == nil {
+					out.Summary = new(UnsupportedData)
+				}
+				easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp9(in, out.Summary)
+			}
+		case "name":
+			out.Name = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp6(out *jwriter.Writer, in Metric) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"gauge\":"
+		out.RawString(prefix[1:])
+		if in.Gauge == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp7(out, *in.Gauge)
+		}
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		if in.Sum == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp8(out, *in.Sum)
+		}
+	}
+	{
+		const prefix string = ",\"histogram\":"
+		out.RawString(prefix)
+		if in.Histogram == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp9(out, *in.Histogram)
+		}
+	}
+	{
+		const prefix string = ",\"exponentialHistogram\":"
+		out.RawString(prefix)
+		if in.ExponentialHistogram == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp9(out, *in.ExponentialHistogram)
+		}
+	}
+	{
+		const prefix string = ",\"summary\":"
+		out.RawString(prefix)
+		if in.Summary == nil {
+			out.RawString("null")
+		} else {
+			easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp9(out, *in.Summary)
+		}
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp9(in *jlexer.Lexer, out *UnsupportedData) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "dataPoints":
+			if in.IsNull() {
+				in.Skip()
+				out.DataPoints = nil
+			} else {
+				in.Delim('[')
+				if out.DataPoints == nil {
+					if !in.IsDelim(']') {
+						out.DataPoints = make([]easyjson.RawMessage, 0, 2)
+					} else {
+						out.DataPoints = []easyjson.RawMessage{}
+					}
+				} else {
+					out.DataPoints = (out.DataPoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 easyjson.RawMessage
+					(v10).UnmarshalEasyJSON(in)
+					out.DataPoints = append(out.DataPoints, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp9(out *jwriter.Writer, in UnsupportedData) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"dataPoints\":"
+		out.RawString(prefix[1:])
+		if in.DataPoints == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.DataPoints {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp8(in *jlexer.Lexer, out *Sum) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "dataPoints":
+			if in.IsNull() {
+				in.Skip()
+				out.DataPoints = nil
+			} else {
+				in.Delim('[')
+				if out.DataPoints == nil {
+					if !in.IsDelim(']') {
+						out.DataPoints = make([]NumberDataPoint, 0, 1)
+					} else {
+						out.DataPoints = []NumberDataPoint{}
+					}
+				} else {
+					out.DataPoints = (out.DataPoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 NumberDataPoint
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp10(in, &v13)
+					out.DataPoints = append(out.DataPoints, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "aggregationTemporality":
+			(out.AggregationTemporality).UnmarshalEasyJSON(in)
+		case "isMonotonic":
+			out.IsMonotonic = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp8(out *jwriter.Writer, in Sum) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"dataPoints\":"
+		out.RawString(prefix[1:])
+		if in.DataPoints == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.DataPoints {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp10(out, v15)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"aggregationTemporality\":"
+		out.RawString(prefix)
+		(in.AggregationTemporality).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"isMonotonic\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsMonotonic))
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp10(in *jlexer.Lexer, out *NumberDataPoint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "asDouble":
+			if in.IsNull() {
+				in.Skip()
+				out.AsDouble = nil
+			} else {
+				if out.AsDouble == nil {
+					out.AsDouble = new(float64)
+				}
+				*out.AsDouble = float64(in.Float64())
+			}
+		case "asInt":
+			if in.IsNull() {
+				in.Skip()
+				out.AsInt = nil
+			} else {
+				if out.AsInt == nil {
+					out.AsInt = new(json.Number)
+				}
+				*out.AsInt = in.JsonNumber()
+			}
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+				out.Attributes = nil
+			} else {
+				in.Delim('[')
+				if out.Attributes == nil {
+					if !in.IsDelim(']') {
+						out.Attributes = make([]KeyValue, 0, 1)
+					} else {
+						out.Attributes = []KeyValue{}
+					}
+				} else {
+					out.Attributes = (out.Attributes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 KeyValue
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp11(in, &v16)
+					out.Attributes = append(out.Attributes, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp10(out *jwriter.Writer, in NumberDataPoint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"asDouble\":"
+		out.RawString(prefix[1:])
+		if in.AsDouble == nil {
+			out.RawString("null")
+		} else {
+			out.Float64(float64(*in.AsDouble))
+		}
+	}
+	{
+		const prefix string = ",\"asInt\":"
+		out.RawString(prefix)
+		if in.AsInt == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.AsInt))
+		}
+	}
+	{
+		const prefix string = ",\"attributes\":"
+		out.RawString(prefix)
+		if in.Attributes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Attributes {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp11(out, v18)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp11(in *jlexer.Lexer, out *KeyValue) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "key":
+			out.Key = string(in.String())
+		case "value":
+			easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp12(in, &out.Value)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp11(out *jwriter.Writer, in KeyValue) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp12(out, in.Value)
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp12(in *jlexer.Lexer, out *AnyValue) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "stringValue":
+			if in.IsNull() {
+				in.Skip()
+				out.StringValue = nil
+			} else {
+				if out.StringValue == nil {
+					out.StringValue = new(string)
+				}
+				*out.StringValue = string(in.String())
+			}
+		case "boolValue":
+			if in.IsNull() {
+				in.Skip()
+				out.BoolValue = nil
+			} else {
+				if out.BoolValue == nil {
+					out.BoolValue = new(bool)
+				}
+				*out.BoolValue = bool(in.Bool())
+			}
+		case "intValue":
+			if in.IsNull() {
+				in.Skip()
+				out.IntValue = nil
+			} else {
+				if out.IntValue == nil {
+					out.IntValue = new(json.Number)
+				}
+				*out.IntValue = in.JsonNumber()
+			}
+		case "doubleValue":
+			if in.IsNull() {
+				in.Skip()
+				out.DoubleValue = nil
+			} else {
+				if out.DoubleValue == nil {
+					out.DoubleValue = new(float64)
+				}
+				*out.DoubleValue = float64(in.Float64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp12(out *jwriter.Writer, in AnyValue) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"stringValue\":"
+		out.RawString(prefix[1:])
+		if in.StringValue == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.StringValue))
+		}
+	}
+	{
+		const prefix string = ",\"boolValue\":"
+		out.RawString(prefix)
+		if in.BoolValue == nil {
+			out.RawString("null")
+		} else {
+			out.Bool(bool(*in.BoolValue))
+		}
+	}
+	{
+		const prefix string = ",\"intValue\":"
+		out.RawString(prefix)
+		if in.IntValue == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.IntValue))
+		}
+	}
+	{
+		const prefix string = ",\"doubleValue\":"
+		out.RawString(prefix)
+		if in.DoubleValue == nil {
+			out.RawString("null")
+		} else {
+			out.Float64(float64(*in.DoubleValue))
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp7(in *jlexer.Lexer, out *Gauge) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "dataPoints":
+			if in.IsNull() {
+				in.Skip()
+				out.DataPoints = nil
+			} else {
+				in.Delim('[')
+				if out.DataPoints == nil {
+					if !in.IsDelim(']') {
+						out.DataPoints = make([]NumberDataPoint, 0, 1)
+					} else {
+						out.DataPoints = []NumberDataPoint{}
+					}
+				} else {
+					out.DataPoints = (out.DataPoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 NumberDataPoint
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp10(in, &v19)
+					out.DataPoints = append(out.DataPoints, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp7(out *jwriter.Writer, in Gauge) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"dataPoints\":"
+		out.RawString(prefix[1:])
+		if in.DataPoints == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.DataPoints {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp10(out, v21)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp4(in *jlexer.Lexer, out *Resource) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "attributes":
+			if in.IsNull() {
+				in.Skip()
+				out.Attributes = nil
+			} else {
+				in.Delim('[')
+				if out.Attributes == nil {
+					if !in.IsDelim(']') {
+						out.Attributes = make([]KeyValue, 0, 1)
+					} else {
+						out.Attributes = []KeyValue{}
+					}
+				} else {
+					out.Attributes = (out.Attributes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 KeyValue
+					easyjson6601e8cdDecodeGithubComXantiniumMetrixInternalProtocolsOtlp11(in, &v22)
+					out.Attributes = append(out.Attributes, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp4(out *jwriter.Writer, in Resource) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"attributes\":"
+		out.RawString(prefix[1:])
+		if in.Attributes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Attributes {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6601e8cdEncodeGithubComXantiniumMetrixInternalProtocolsOtlp11(out, v24)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,22 @@
+// Package protocols содержит общие для всех текстовых
+// протоколов приёма метрик структуры.
+package protocols
+
+import "fmt"
+
+// LineError ошибка разбора строки с номером Line (нумерация с единицы).
+// Ошибка в одной строке не мешает разбору остальных.
+type LineError struct {
+	Err  error
+	Line int
+}
+
+// Error возвращает текст ошибки.
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку.
+func (e LineError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,283 @@
+// Package remotewrite содержит декодер запросов Prometheus remote-write
+// (сжатый при помощи snappy protobuf-объект WriteRequest)
+// и их преобразование в метрики.
+//
+// Для разбора protobuf используется пакет protowire, что позволяет
+// не тянуть в проект сгенерированные модели Prometheus.
+package remotewrite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// MetricNameLabel имя метки, содержащей имя метрики.
+const MetricNameLabel = "__name__"
+
+// WriteRequest запрос на запись временных рядов.
+type WriteRequest struct {
+	Timeseries []TimeSeries
+}
+
+// TimeSeries временной ряд.
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// Label метка временного ряда.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample значение временного ряда.
+type Sample struct {
+	Value float64
+	// Timestamp время в миллисекундах.
+	Timestamp int64
+}
+
+// MetricLabels возвращает метки временного ряда,
+// за исключением метки с именем метрики.
+func (ts TimeSeries) MetricLabels() models.Labels {
+	labels := make(models.Labels, len(ts.Labels))
+	for _, label := range ts.Labels {
+		if label.Name != MetricNameLabel {
+			labels[label.Name] = label.Value
+		}
+	}
+
+	return labels
+}
+
+// Name возвращает имя метрики временного ряда.
+func (ts TimeSeries) Name() string {
+	for _, label := range ts.Labels {
+		if label.Name == MetricNameLabel {
+			return label.Value
+		}
+	}
+
+	return ""
+}
+
+// Номера полей protobuf-схемы prompb.
+const (
+	writeRequestTimeseries protowire.Number = 1
+
+	timeSeriesLabels  protowire.Number = 1
+	timeSeriesSamples protowire.Number = 2
+
+	labelName  protowire.Number = 1
+	labelValue protowire.Number = 2
+
+	sampleValue     protowire.Number = 1
+	sampleTimestamp protowire.Number = 2
+)
+
+// MaxDecodedSize максимальный размер распакованного тела запроса.
+// Ограничивает и размер сжатого тела, которое не может быть больше.
+const MaxDecodedSize = 32 << 20
+
+var (
+	// ErrInvalidMessage ошибка разбора protobuf-сообщения.
+	ErrInvalidMessage = errors.New("invalid protobuf message")
+	// ErrTooLarge ошибка превышения MaxDecodedSize.
+	ErrTooLarge = errors.New("request is too large")
+)
+
+// Decode распаковывает и декодирует тело запроса remote-write.
+// Размер распакованного тела проверяется до выделения памяти под него.
+func Decode(body []byte) (WriteRequest, error) {
+	size, err := snappy.DecodedLen(body)
+	if err != nil {
+		return WriteRequest{}, fmt.Errorf("failed to decompress request: %v", err)
+	}
+	if size > MaxDecodedSize {
+		return WriteRequest{}, fmt.Errorf("%w: decoded size %d exceeds %d bytes", ErrTooLarge, size, MaxDecodedSize)
+	}
+
+	raw, err := snappy.Decode(nil, body)
+	if err != nil {
+		return WriteRequest{}, fmt.Errorf("failed to decompress request: %v", err)
+	}
+
+	return Unmarshal(raw)
+}
+
+// Unmarshal декодирует protobuf-сообщение WriteRequest.
+// Неизвестные поля (метаданные, экземпляры, гистограммы) пропускаются.
+func Unmarshal(raw []byte) (WriteRequest, error) {
+	var req WriteRequest
+
+	err := walkMessage(raw, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != writeRequestTimeseries || typ != protowire.BytesType {
+			return nil
+		}
+
+		ts, err := unmarshalTimeSeries(value)
+		if err != nil {
+			return err
+		}
+
+		req.Timeseries = append(req.Timeseries, ts)
+		return nil
+	})
+
+	return req, err
+}
+
+func unmarshalTimeSeries(raw []byte) (TimeSeries, error) {
+	var ts TimeSeries
+
+	err := walkMessage(raw, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case timeSeriesLabels:
+			label, err := unmarshalLabel(value)
+			if err != nil {
+				return err
+			}
+			ts.Labels = append(ts.Labels, label)
+		case timeSeriesSamples:
+			sample, err := unmarshalSample(value)
+			if err != nil {
+				return err
+			}
+			ts.Samples = append(ts.Samples, sample)
+		}
+
+		return nil
+	})
+
+	return ts, err
+}
+
+func unmarshalLabel(raw []byte) (Label, error) {
+	var label Label
+
+	err := walkMessage(raw, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case labelName:
+			label.Name = string(value)
+		case labelValue:
+			label.Value = string(value)
+		}
+
+		return nil
+	})
+
+	return label, err
+}
+
+func unmarshalSample(raw []byte) (Sample, error) {
+	var sample Sample
+
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return Sample{}, ErrInvalidMessage
+		}
+		raw = raw[n:]
+
+		switch {
+		case num == sampleValue && typ == protowire.Fixed64Type:
+			v, m := protowire.ConsumeFixed64(raw)
+			if m < 0 {
+				return Sample{}, ErrInvalidMessage
+			}
+			sample.Value = math.Float64frombits(v)
+			n = m
+		case num == sampleTimestamp && typ == protowire.VarintType:
+			v, m := protowire.ConsumeVarint(raw)
+			if m < 0 {
+				return Sample{}, ErrInvalidMessage
+			}
+			sample.Timestamp = int64(v)
+			n = m
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, raw)
+			if n < 0 {
+				return Sample{}, ErrInvalidMessage
+			}
+		}
+
+		raw = raw[n:]
+	}
+
+	return sample, nil
+}
+
+// walkMessage обходит поля сообщения, вызывая fn для каждого из них.
+// Для полей, не являющихся length-delimited, value равно nil.
+func walkMessage(raw []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return ErrInvalidMessage
+		}
+		raw = raw[n:]
+
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(raw)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, raw)
+		}
+		if n < 0 {
+			return ErrInvalidMessage
+		}
+		raw = raw[n:]
+
+		err := fn(num, typ, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ToMetrics преобразует временные ряды в метрики.
+//
+// Все значения сохраняются как метрики типа Gauge: счётчики Prometheus
+// передают накопленное значение, а не приращение, поэтому их нельзя
+// суммировать как метрики типа Counter. Каждое значение ряда становится
+// отдельным обновлением, чтобы попасть в историю метрики.
+// Метки ряда, кроме имени метрики, становятся метками метрики.
+//
+// Ряды без имени метрики пропускаются и возвращаются в виде ошибки.
+func ToMetrics(req WriteRequest) ([]models.MetricInfo, error) {
+	var errs []error
+
+	metrics := make([]models.MetricInfo, 0, len(req.Timeseries))
+
+	for i, ts := range req.Timeseries {
+		name := ts.Name()
+		if name == "" {
+			errs = append(errs, fmt.Errorf("timeseries #%d: metric name is missing", i))
+			continue
+		}
+
+		labels := ts.MetricLabels()
+		for _, sample := range ts.Samples {
+			metrics = append(metrics, models.NewGaugeMetric(name, sample.Value).WithLabels(labels))
+		}
+	}
+
+	return metrics, errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package remotewrite_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/remotewrite"
+)
+
+func TestDecode(t *testing.T) {
+	want := remotewrite.WriteRequest{
+		Timeseries: []remotewrite.TimeSeries{
+			{
+				Labels: []remotewrite.Label{
+					{Name: remotewrite.MetricNameLabel, Value: "go_memstats_heap_alloc_bytes"},
+					{Name: "instance", Value: "localhost:9090"},
+				},
+				Samples: []remotewrite.Sample{
+					{Value: 1024.5, Timestamp: 1735678800000},
+					{Value: 2048, Timestamp: 1735678815000},
+				},
+			},
+			{
+				Labels: []remotewrite.Label{
+					{Name: remotewrite.MetricNameLabel, Value: "http_requests_total"},
+				},
+				Samples: []remotewrite.Sample{
+					{Value: 17, Timestamp: 1735678800000},
+				},
+			},
+		},
+	}
+
+	body := snappy.Encode(nil, marshalWriteRequest(want))
+
+	got, err := remotewrite.Decode(body)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	metrics, err := remotewrite.ToMetrics(got)
+	require.NoError(t, err)
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("go_memstats_heap_alloc_bytes", 1024.5).WithLabels(models.Labels{"instance": "localhost:9090"}),
+		models.NewGaugeMetric("go_memstats_heap_alloc_bytes", 2048).WithLabels(models.Labels{"instance": "localhost:9090"}),
+		models.NewGaugeMetric("http_requests_total", 17),
+	}, metrics)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, err := remotewrite.Decode([]byte("not snappy"))
+	require.Error(t, err)
+
+	// Длина вложенного сообщения превышает размер данных.
+	raw := protowire.AppendTag(nil, 1, protowire.BytesType)
+	raw = protowire.AppendVarint(raw, 100)
+
+	_, err = remotewrite.Decode(snappy.Encode(nil, raw))
+	require.ErrorIs(t, err, remotewrite.ErrInvalidMessage)
+}
+
+func TestDecode_TooLarge(t *testing.T) {
+	// Заголовок блока snappy заявляет распакованный размер около 4 ГиБ.
+	body := binary.AppendUvarint(nil, math.MaxUint32)
+	body = append(body, 0)
+
+	_, err := remotewrite.Decode(body)
+	require.ErrorIs(t, err, remotewrite.ErrTooLarge)
+}
+
+func TestToMetrics_MissingName(t *testing.T) {
+	req := remotewrite.WriteRequest{
+		Timeseries: []remotewrite.TimeSeries{
+			{
+				Labels:  []remotewrite.Label{{Name: "job", Value: "node"}},
+				Samples: []remotewrite.Sample{{Value: 1}},
+			},
+			{
+				Labels:  []remotewrite.Label{{Name: remotewrite.MetricNameLabel, Value: "up"}},
+				Samples: []remotewrite.Sample{{Value: 1}},
+			},
+		},
+	}
+
+	metrics, err := remotewrite.ToMetrics(req)
+	require.Error(t, err)
+	require.Equal(t, []models.MetricInfo{models.NewGaugeMetric("up", 1)}, metrics)
+}
+
+// marshalWriteRequest кодирует запрос в protobuf
+// так же, как это делает Prometheus.
+func marshalWriteRequest(req remotewrite.WriteRequest) []byte {
+	var raw []byte
+
+	for _, ts := range req.Timeseries {
+		var tsRaw []byte
+
+		for _, label := range ts.Labels {
+			var labelRaw []byte
+			labelRaw = protowire.AppendTag(labelRaw, 1, protowire.BytesType)
+			labelRaw = protowire.AppendString(labelRaw, label.Name)
+			labelRaw = protowire.AppendTag(labelRaw, 2, protowire.BytesType)
+			labelRaw = protowire.AppendString(labelRaw, label.Value)
+
+			tsRaw = protowire.AppendTag(tsRaw, 1, protowire.BytesType)
+			tsRaw = protowire.AppendBytes(tsRaw, labelRaw)
+		}
+
+		for _, sample := range ts.Samples {
+			var sampleRaw []byte
+			sampleRaw = protowire.AppendTag(sampleRaw, 1, protowire.Fixed64Type)
+			sampleRaw = protowire.AppendFixed64(sampleRaw, math.Float64bits(sample.Value))
+			sampleRaw = protowire.AppendTag(sampleRaw, 2, protowire.VarintType)
+			sampleRaw = protowire.AppendVarint(sampleRaw, uint64(sample.Timestamp))
+
+			tsRaw = protowire.AppendTag(tsRaw, 2, protowire.BytesType)
+			tsRaw = protowire.AppendBytes(tsRaw, sampleRaw)
+		}
+
+		raw = protowire.AppendTag(raw, 1, protowire.BytesType)
+		raw = protowire.AppendBytes(raw, tsRaw)
+	}
+
+	return raw
+}
@@ -0,0 +1,108 @@
+// Package statsd содержит парсер строк в формате StatsD.
+//
+// Поддерживаются счётчики (name:1|c), в том числе с частотой
+// семплирования (name:1|c|@0.1), и gauge-метрики (name:12.5|g),
+// в том числе относительные изменения (name:+3|g, name:-3|g).
+// Теги в формате DogStatsD (|#tag:value) игнорируются.
+package statsd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// Line разобранная строка StatsD.
+type Line struct {
+	Name string
+	Type models.MetricType
+	// Value значение метрики. Для счётчиков уже поделено
+	// на частоту семплирования.
+	Value float64
+	// IsDelta является ли значение gauge-метрики
+	// относительным изменением.
+	IsDelta bool
+}
+
+// CounterValue возвращает значение счётчика, округлённое до целого.
+func (line Line) CounterValue() int64 {
+	return int64(math.Round(line.Value))
+}
+
+// Parse разбирает пакет, содержащий строки, разделённые переводом строки.
+// Пустые строки пропускаются. Ошибки разбора возвращаются для каждой
+// строки отдельно, не мешая разбору остальных.
+func Parse(packet []byte) ([]Line, []error) {
+	var (
+		lines []Line
+		errs  []error
+	)
+
+	for _, raw := range strings.Split(string(packet), "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		line, err := ParseLine(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %v", raw, err))
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, errs
+}
+
+// ParseLine разбирает одну строку вида name:value|type[|@rate][|#tags].
+func ParseLine(raw string) (Line, error) {
+	name, rest, found := strings.Cut(raw, ":")
+	if !found {
+		return Line{}, fmt.Errorf("value is missing")
+	}
+	if name == "" {
+		return Line{}, fmt.Errorf("metric name cannot be empty")
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return Line{}, fmt.Errorf("metric type is missing")
+	}
+
+	rawValue := parts[0]
+	value, err := tools.StrToFloat(rawValue)
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid metric value")
+	}
+
+	sampleRate := 1.0
+	for _, part := range parts[2:] {
+		if strings.HasPrefix(part, "@") {
+			sampleRate, err = tools.StrToFloat(part[1:])
+			if err != nil || sampleRate <= 0 || sampleRate > 1 {
+				return Line{}, fmt.Errorf("invalid sample rate")
+			}
+		}
+	}
+
+	line := Line{Name: name}
+
+	switch parts[1] {
+	case "c":
+		line.Type = models.Counter
+		line.Value = value / sampleRate
+	case "g":
+		line.Type = models.Gauge
+		line.Value = value
+		line.IsDelta = strings.HasPrefix(rawValue, "+") || strings.HasPrefix(rawValue, "-")
+	default:
+		return Line{}, fmt.Errorf("unsupported metric type: %q", parts[1])
+	}
+
+	return line, nil
+}
@@ -0,0 +1,102 @@
+package statsd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/statsd"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    statsd.Line
+		wantErr bool
+	}{
+		{
+			name: "Счётчик",
+			raw:  "requests:1|c",
+			want: statsd.Line{Name: "requests", Type: models.Counter, Value: 1},
+		},
+		{
+			name: "Счётчик с частотой семплирования",
+			raw:  "requests:1|c|@0.1",
+			want: statsd.Line{Name: "requests", Type: models.Counter, Value: 10},
+		},
+		{
+			name: "Gauge-метрика",
+			raw:  "HeapAlloc:12.5|g",
+			want: statsd.Line{Name: "HeapAlloc", Type: models.Gauge, Value: 12.5},
+		},
+		{
+			name: "Увеличение gauge-метрики",
+			raw:  "connections:+3|g",
+			want: statsd.Line{Name: "connections", Type: models.Gauge, Value: 3, IsDelta: true},
+		},
+		{
+			name: "Уменьшение gauge-метрики",
+			raw:  "connections:-3|g",
+			want: statsd.Line{Name: "connections", Type: models.Gauge, Value: -3, IsDelta: true},
+		},
+		{
+			name: "Теги игнорируются",
+			raw:  "requests:2|c|#env:prod",
+			want: statsd.Line{Name: "requests", Type: models.Counter, Value: 2},
+		},
+		{
+			name:    "Отсутствует значение",
+			raw:     "requests",
+			wantErr: true,
+		},
+		{
+			name:    "Пустое имя",
+			raw:     ":1|c",
+			wantErr: true,
+		},
+		{
+			name:    "Отсутствует тип",
+			raw:     "requests:1",
+			wantErr: true,
+		},
+		{
+			name:    "Неподдерживаемый тип",
+			raw:     "latency:320|ms",
+			wantErr: true,
+		},
+		{
+			name:    "Невалидное значение",
+			raw:     "requests:abc|c",
+			wantErr: true,
+		},
+		{
+			name:    "Невалидная частота семплирования",
+			raw:     "requests:1|c|@2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := statsd.ParseLine(tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	lines, errs := statsd.Parse([]byte("requests:1|c\n\ninvalid\nHeapAlloc:5|g\n"))
+
+	require.Len(t, errs, 1)
+	require.Equal(t, []statsd.Line{
+		{Name: "requests", Type: models.Counter, Value: 1},
+		{Name: "HeapAlloc", Type: models.Gauge, Value: 5},
+	}, lines)
+}
@@ -0,0 +1,131 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// Expr узел синтаксического дерева выражения.
+type Expr interface {
+	// String возвращает каноническое представление выражения.
+	String() string
+}
+
+// NumberLiteral числовая константа.
+type NumberLiteral struct {
+	Value float64
+}
+
+// String возвращает представление константы.
+func (e *NumberLiteral) String() string {
+	return strconv.FormatFloat(e.Value, 'g', -1, 64)
+}
+
+// VectorSelector выборка метрик типа Gauge и Counter по шаблону
+// названия и условиям на метки. Если Range больше нуля, выборка
+// является интервальной и может использоваться только как аргумент функций.
+type VectorSelector struct {
+	// Matchers условия на метки. Идентификатор метрики
+	// доступен как метка models.MetricNameLabel.
+	Matchers []models.LabelMatcher
+	// Name шаблон идентификатора метрики (синтаксис path.Match).
+	// Пустой шаблон соответствует любой метрике.
+	Name string
+	// Range длина интервала, предшествующего моменту вычисления.
+	Range time.Duration
+}
+
+// String возвращает представление выборки.
+func (e *VectorSelector) String() string {
+	var b strings.Builder
+
+	b.WriteString(e.Name)
+	if len(e.Matchers) > 0 || e.Name == "" {
+		b.WriteString("{")
+		for i, matcher := range e.Matchers {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(matcher.Name)
+			b.WriteString(string(matcher.Type))
+			b.WriteString(strconv.Quote(matcher.Value))
+		}
+		b.WriteString("}")
+	}
+	if e.Range > 0 {
+		b.WriteString("[")
+		b.WriteString(e.Range.String())
+		b.WriteString("]")
+	}
+
+	return b.String()
+}
+
+// UnaryExpr смена знака выражения.
+type UnaryExpr struct {
+	Expr Expr
+}
+
+// String возвращает представление выражения.
+func (e *UnaryExpr) String() string {
+	return "-" + e.Expr.String()
+}
+
+// ParenExpr выражение в скобках.
+type ParenExpr struct {
+	Expr Expr
+}
+
+// String возвращает представление выражения.
+func (e *ParenExpr) String() string {
+	return "(" + e.Expr.String() + ")"
+}
+
+// BinaryExpr арифметическая операция.
+type BinaryExpr struct {
+	LHS Expr
+	RHS Expr
+	Op  string
+}
+
+// String возвращает представление выражения.
+func (e *BinaryExpr) String() string {
+	return e.LHS.String() + " " + e.Op + " " + e.RHS.String()
+}
+
+// Call вызов функции.
+type Call struct {
+	Func string
+	Args []Expr
+}
+
+// String возвращает представление вызова.
+func (e *Call) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = arg.String()
+	}
+
+	return e.Func + "(" + strings.Join(args, ", ") + ")"
+}
+
+// AggregateExpr агрегация значений выборки.
+type AggregateExpr struct {
+	Expr Expr
+	// Grouping метки, по значениям которых группируются ряды.
+	// Если не заданы, все ряды агрегируются в один.
+	Grouping []string
+	Op       string
+}
+
+// String возвращает представление агрегации.
+func (e *AggregateExpr) String() string {
+	if e.Grouping == nil {
+		return e.Op + "(" + e.Expr.String() + ")"
+	}
+
+	return e.Op + " by (" + strings.Join(e.Grouping, ", ") + ") (" + e.Expr.String() + ")"
+}
@@ -0,0 +1,463 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// ErrInvalidQuery ошибка вычисления, вызванная самим выражением,
+// а не недоступностью источника метрик.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Source источник значений и истории метрик.
+type Source interface {
+	GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error)
+	GetMetricHistory(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error)
+}
+
+// Value результат вычисления выражения: Scalar или Vector.
+type Value interface {
+	value()
+}
+
+// Scalar числовое значение.
+type Scalar float64
+
+func (Scalar) value() {}
+
+// Sample значение одного временного ряда.
+type Sample struct {
+	// Labels метки ряда.
+	Labels models.Labels
+	// MetricID идентификатор метрики. Пустой у рядов,
+	// полученных в результате вычислений.
+	MetricID string
+	Value    float64
+}
+
+// Vector набор значений временных рядов в момент вычисления.
+type Vector []Sample
+
+func (Vector) value() {}
+
+// Evaluate вычисляет выражение expr на текущий момент now:
+// выборки возвращают текущие значения метрик.
+func Evaluate(ctx context.Context, source Source, expr Expr, now time.Time) (Value, error) {
+	return evaluate(ctx, &evaluator{source: source, now: now}, expr)
+}
+
+// EvaluateAt вычисляет выражение expr на прошедший момент at:
+// выборки возвращают последние значения рядов из истории не позднее at.
+// Ряды, у которых в истории нет значений до момента at,
+// в результат не попадают.
+func EvaluateAt(ctx context.Context, source Source, expr Expr, at time.Time) (Value, error) {
+	return evaluate(ctx, &evaluator{source: source, now: at, historical: true}, expr)
+}
+
+func evaluate(ctx context.Context, ev *evaluator, expr Expr) (Value, error) {
+	value, err := ev.eval(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if vector, ok := value.(Vector); ok {
+		sortVector(vector)
+	}
+
+	return value, nil
+}
+
+// evaluator состояние вычисления выражения.
+type evaluator struct {
+	source Source
+	now    time.Time
+	// metrics текущие значения метрик, запрашиваются один раз за вычисление.
+	metrics []models.MetricInfo
+	// fetchedAt момент запроса текущих значений метрик.
+	fetchedAt time.Time
+	fetched   bool
+	// historical признак вычисления выборок по истории на момент now.
+	historical bool
+}
+
+func (ev *evaluator) eval(ctx context.Context, expr Expr) (Value, error) {
+	switch e := expr.(type) {
+	case *NumberLiteral:
+		return Scalar(e.Value), nil
+	case *ParenExpr:
+		return ev.eval(ctx, e.Expr)
+	case *UnaryExpr:
+		value, err := ev.eval(ctx, e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return applyBinary("*", Scalar(-1), value)
+	case *BinaryExpr:
+		lhs, err := ev.eval(ctx, e.LHS)
+		if err != nil {
+			return nil, err
+		}
+
+		rhs, err := ev.eval(ctx, e.RHS)
+		if err != nil {
+			return nil, err
+		}
+
+		return applyBinary(e.Op, lhs, rhs)
+	case *VectorSelector:
+		return ev.evalSelector(ctx, e)
+	case *AggregateExpr:
+		value, err := ev.eval(ctx, e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(e, value)
+	case *Call:
+		return ev.evalCall(ctx, e)
+	default:
+		return nil, fmt.Errorf("%w: unsupported expression %s", ErrInvalidQuery, expr)
+	}
+}
+
+// selectMetrics возвращает метрики типа Gauge и Counter,
+// соответствующие выборке.
+func (ev *evaluator) selectMetrics(ctx context.Context, selector *VectorSelector) ([]models.MetricInfo, error) {
+	if !ev.fetched {
+		metrics, err := ev.source.GetAllMetrics(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ev.metrics = metrics
+		ev.fetchedAt = time.Now()
+		ev.fetched = true
+	}
+
+	var selected []models.MetricInfo
+	for _, metric := range ev.metrics {
+		if metric.Type() != models.Gauge && metric.Type() != models.Counter {
+			continue
+		}
+
+		if selector.Name != "" {
+			matched, err := path.Match(selector.Name, metric.ID())
+			if err != nil || !matched {
+				continue
+			}
+		}
+
+		if !models.MatchLabels(selector.Matchers, metric.ID(), metric.Labels()) {
+			continue
+		}
+
+		selected = append(selected, metric)
+	}
+
+	return selected, nil
+}
+
+func (ev *evaluator) evalSelector(ctx context.Context, selector *VectorSelector) (Value, error) {
+	metrics, err := ev.selectMetrics(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := make(Vector, 0, len(metrics))
+	for _, metric := range metrics {
+		sample := Sample{Labels: metric.Labels(), MetricID: metric.ID()}
+
+		switch {
+		case ev.historical:
+			value, ok, err := ev.historicalValue(ctx, metric)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			sample.Value = value
+		case metric.Type() == models.Gauge:
+			sample.Value = metric.GaugeValue()
+		default:
+			sample.Value = float64(metric.CounterValue())
+		}
+
+		vector = append(vector, sample)
+	}
+
+	return vector, nil
+}
+
+// historicalValue возвращает значение метрики на момент now по её истории.
+// Возвращает false, если в истории нет значений до момента now.
+//
+// Значение gauge - последнее значение в истории. В истории счётчиков хранятся
+// приращения, поэтому значение counter вычисляется как текущее значение
+// за вычетом приращений после момента now. Значения удаляются из истории
+// начиная с самых старых, поэтому при наличии значения до момента now
+// все последующие приращения также хранятся в истории.
+func (ev *evaluator) historicalValue(ctx context.Context, metric models.MetricInfo) (float64, bool, error) {
+	samples, err := ev.history(ctx, metric, time.Time{}, ev.fetchedAt)
+	if err != nil {
+		return 0, false, err
+	}
+
+	after := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Timestamp.After(ev.now)
+	})
+	if after == 0 {
+		return 0, false, nil
+	}
+
+	if metric.Type() == models.Gauge {
+		return samples[after-1].GaugeValue, true, nil
+	}
+
+	value := metric.CounterValue()
+	for _, sample := range samples[after:] {
+		value -= sample.CounterValue
+	}
+
+	return float64(value), true, nil
+}
+
+// history возвращает значения метрики в промежутке [from, to].
+// Выражение, которому нужна история, некорректно, если история не хранится.
+func (ev *evaluator) history(ctx context.Context, metric models.MetricInfo, from, to time.Time) ([]models.MetricSample, error) {
+	samples, err := ev.source.GetMetricHistory(ctx, metric.Type(), metric.ID(), metric.Labels(), from, to)
+	if errors.Is(err, models.ErrHistoryDisabled) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+
+	return samples, err
+}
+
+// evalCall вычисляет функцию над интервальной выборкой.
+//
+// rate - средняя скорость роста метрик типа Counter в секунду на интервале;
+// delta - изменение значения на интервале: разность последнего и первого
+// значений для метрик типа Gauge и сумма приращений для метрик типа Counter.
+// Ряды, для которых на интервале недостаточно значений, в результат не попадают.
+func (ev *evaluator) evalCall(ctx context.Context, call *Call) (Value, error) {
+	if call.Func != "rate" && call.Func != "delta" {
+		return nil, fmt.Errorf("%w: unknown function %q", ErrInvalidQuery, call.Func)
+	}
+
+	selector := call.Args[0].(*VectorSelector)
+
+	metrics, err := ev.selectMetrics(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	from := ev.now.Add(-selector.Range)
+
+	var vector Vector
+	for _, metric := range metrics {
+		if call.Func == "rate" && metric.Type() != models.Counter {
+			continue
+		}
+
+		samples, err := ev.history(ctx, metric, from, ev.now)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := rangeDelta(metric.Type(), samples)
+		if !ok {
+			continue
+		}
+
+		if call.Func == "rate" {
+			value /= selector.Range.Seconds()
+		}
+
+		vector = append(vector, Sample{Labels: metric.Labels(), Value: value})
+	}
+
+	return vector, nil
+}
+
+// rangeDelta вычисляет изменение значения метрики по её истории на интервале.
+// Возвращает false, если значений для вычисления недостаточно.
+func rangeDelta(metricType models.MetricType, samples []models.MetricSample) (float64, bool) {
+	if metricType == models.Counter {
+		if len(samples) == 0 {
+			return 0, false
+		}
+
+		// В истории счётчиков хранятся приращения.
+		var increase int64
+		for _, sample := range samples {
+			increase += sample.CounterValue
+		}
+
+		return float64(increase), true
+	}
+
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	return samples[len(samples)-1].GaugeValue - samples[0].GaugeValue, true
+}
+
+// aggregate агрегирует значения выборки. Ряды группируются по значениям
+// меток группировки, которые и образуют метки результата.
+func aggregate(expr *AggregateExpr, value Value) (Value, error) {
+	vector, ok := value.(Vector)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s() expects vector argument", ErrInvalidQuery, expr.Op)
+	}
+
+	type group struct {
+		labels models.Labels
+		value  float64
+		count  int
+	}
+
+	var keys []string
+	groups := make(map[string]*group)
+
+	for _, sample := range vector {
+		var labels models.Labels
+		for _, name := range expr.Grouping {
+			if labelValue, ok := sample.Labels[name]; ok {
+				if labels == nil {
+					labels = make(models.Labels, len(expr.Grouping))
+				}
+				labels[name] = labelValue
+			}
+		}
+
+		key := labels.Key()
+
+		g, exists := groups[key]
+		if !exists {
+			g = &group{labels: labels, value: sample.Value}
+			groups[key] = g
+			keys = append(keys, key)
+		} else {
+			switch expr.Op {
+			case "sum", "avg":
+				g.value += sample.Value
+			case "min":
+				g.value = math.Min(g.value, sample.Value)
+			case "max":
+				g.value = math.Max(g.value, sample.Value)
+			}
+		}
+		g.count++
+	}
+
+	result := make(Vector, len(keys))
+	for i, key := range keys {
+		g := groups[key]
+		if expr.Op == "avg" {
+			g.value /= float64(g.count)
+		}
+
+		result[i] = Sample{Labels: g.labels, Value: g.value}
+	}
+
+	return result, nil
+}
+
+// applyBinary применяет арифметическую операцию к операндам.
+// Значения векторов сопоставляются по совпадающим наборам меток.
+func applyBinary(op string, lhs, rhs Value) (Value, error) {
+	switch l := lhs.(type) {
+	case Scalar:
+		switch r := rhs.(type) {
+		case Scalar:
+			return Scalar(applyOp(op, float64(l), float64(r))), nil
+		case Vector:
+			result := make(Vector, len(r))
+			for i, sample := range r {
+				result[i] = Sample{Labels: sample.Labels, Value: applyOp(op, float64(l), sample.Value)}
+			}
+			return result, nil
+		}
+	case Vector:
+		switch r := rhs.(type) {
+		case Scalar:
+			result := make(Vector, len(l))
+			for i, sample := range l {
+				result[i] = Sample{Labels: sample.Labels, Value: applyOp(op, sample.Value, float64(r))}
+			}
+			return result, nil
+		case Vector:
+			return applyVectors(op, l, r)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operands for %q", ErrInvalidQuery, op)
+}
+
+// applyVectors применяет операцию к парам значений с одинаковыми метками.
+// Значения без пары отбрасываются.
+func applyVectors(op string, lhs, rhs Vector) (Vector, error) {
+	rhsByLabels := make(map[string]Sample, len(rhs))
+	for _, sample := range rhs {
+		key := sample.Labels.Key()
+		if _, exists := rhsByLabels[key]; exists {
+			return nil, fmt.Errorf("%w: many-to-one matching: duplicate series for labels {%s} on the right side of %q", ErrInvalidQuery, key, op)
+		}
+		rhsByLabels[key] = sample
+	}
+
+	seen := make(map[string]struct{}, len(lhs))
+
+	var result Vector
+	for _, sample := range lhs {
+		key := sample.Labels.Key()
+		if _, exists := seen[key]; exists {
+			return nil, fmt.Errorf("%w: many-to-one matching: duplicate series for labels {%s} on the left side of %q", ErrInvalidQuery, key, op)
+		}
+		seen[key] = struct{}{}
+
+		other, ok := rhsByLabels[key]
+		if !ok {
+			continue
+		}
+
+		result = append(result, Sample{Labels: sample.Labels, Value: applyOp(op, sample.Value, other.Value)})
+	}
+
+	return result, nil
+}
+
+func applyOp(op string, lhs, rhs float64) float64 {
+	switch op {
+	case "+":
+		return lhs + rhs
+	case "-":
+		return lhs - rhs
+	case "*":
+		return lhs * rhs
+	case "/":
+		return lhs / rhs
+	default:
+		// Попасть сюда невозможно, операторы проверяются парсером.
+		panic(fmt.Sprintf("unknown operator %q", op))
+	}
+}
+
+// sortVector упорядочивает значения по идентификатору метрики и меткам.
+func sortVector(vector Vector) {
+	slices.SortFunc(vector, func(a, b Sample) int {
+		if c := strings.Compare(a.MetricID, b.MetricID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Labels.Key(), b.Labels.Key())
+	})
+}
@@ -0,0 +1,305 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/query"
+)
+
+// testSource источник метрик с заранее заданными значениями и историей.
+type testSource struct {
+	history map[string][]models.MetricSample
+	metrics []models.MetricInfo
+	// historyDisabled признак источника, не хранящего историю.
+	historyDisabled bool
+}
+
+func (source *testSource) GetAllMetrics(_ context.Context, _ models.Labels) ([]models.MetricInfo, error) {
+	return source.metrics, nil
+}
+
+func (source *testSource) GetMetricHistory(_ context.Context, _ models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	if source.historyDisabled {
+		return nil, models.ErrHistoryDisabled
+	}
+
+	var samples []models.MetricSample
+	for _, sample := range source.history[id+labels.Key()] {
+		if !sample.Timestamp.Before(from) && !sample.Timestamp.After(to) {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples, nil
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Unix(10000, 0)
+
+	source := &testSource{
+		metrics: []models.MetricInfo{
+			models.NewGaugeMetric("HeapInuse", 30),
+			models.NewGaugeMetric("HeapSys", 120),
+			models.NewGaugeMetric("CPUutilization1", 10).WithLabels(models.Labels{"host": "a"}),
+			models.NewGaugeMetric("CPUutilization2", 20).WithLabels(models.Labels{"host": "a"}),
+			models.NewGaugeMetric("CPUutilization1", 40).WithLabels(models.Labels{"host": "b"}),
+			models.NewCounterMetric("PollCount", 100),
+			models.NewCounterMetric("Requests", 7).WithLabels(models.Labels{"host": "a"}),
+		},
+		history: map[string][]models.MetricSample{
+			"HeapInuse": {
+				{Timestamp: now.Add(-10 * time.Minute), GaugeValue: 5},
+				{Timestamp: now.Add(-4 * time.Minute), GaugeValue: 10},
+				{Timestamp: now.Add(-2 * time.Minute), GaugeValue: 40},
+				{Timestamp: now.Add(-1 * time.Minute), GaugeValue: 30},
+			},
+			"PollCount": {
+				{Timestamp: now.Add(-2 * time.Minute), CounterValue: 50},
+				{Timestamp: now.Add(-50 * time.Second), CounterValue: 30},
+				{Timestamp: now.Add(-10 * time.Second), CounterValue: 30},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    query.Value
+		wantErr bool
+	}{
+		{
+			name:  "Скалярное выражение",
+			input: "(1 + 2) * -3",
+			want:  query.Scalar(-9),
+		},
+		{
+			name:  "Выборка по названию",
+			input: "HeapInuse",
+			want:  query.Vector{{MetricID: "HeapInuse", Value: 30}},
+		},
+		{
+			name:  "Отношение двух метрик",
+			input: "HeapInuse / HeapSys * 100",
+			want:  query.Vector{{Value: 25}},
+		},
+		{
+			name:  "Выборка по шаблону и меткам",
+			input: `CPUutilization*{host="a"}`,
+			want: query.Vector{
+				{Labels: models.Labels{"host": "a"}, MetricID: "CPUutilization1", Value: 10},
+				{Labels: models.Labels{"host": "a"}, MetricID: "CPUutilization2", Value: 20},
+			},
+		},
+		{
+			name:  "Сумма по шаблону",
+			input: "sum(CPUutilization*)",
+			want:  query.Vector{{Value: 70}},
+		},
+		{
+			name:  "Выборка по регулярному выражению над названием",
+			input: `{__name__=~"Heap(Inuse|Sys)"} - 10`,
+			want: query.Vector{
+				{Value: 20},
+				{Value: 110},
+			},
+		},
+		{
+			name:  "Скорость роста счётчика",
+			input: "rate(PollCount[1m])",
+			want:  query.Vector{{Value: 1}},
+		},
+		{
+			name:  "Счётчик без истории на интервале",
+			input: "rate(Requests[1m])",
+			want:  query.Vector(nil),
+		},
+		{
+			name:  "Пустая выборка",
+			input: "sum(Unknown)",
+			want:  query.Vector{},
+		},
+		{
+			name:  "Изменение значения gauge",
+			input: "delta(HeapInuse[5m])",
+			want:  query.Vector{{Value: 20}},
+		},
+		{
+			name:  "Изменение значения counter",
+			input: "delta(PollCount[1m])",
+			want:  query.Vector{{Value: 60}},
+		},
+		{
+			name:  "Недостаточно значений для delta",
+			input: "delta(HeapInuse[90s])",
+			want:  query.Vector(nil),
+		},
+		{
+			name:  "Сумма с группировкой",
+			input: "sum by (host) (CPUutilization*)",
+			want: query.Vector{
+				{Labels: models.Labels{"host": "a"}, Value: 30},
+				{Labels: models.Labels{"host": "b"}, Value: 40},
+			},
+		},
+		{
+			name:  "Среднее с группировкой",
+			input: "avg(CPUutilization*) by (host)",
+			want: query.Vector{
+				{Labels: models.Labels{"host": "a"}, Value: 15},
+				{Labels: models.Labels{"host": "b"}, Value: 40},
+			},
+		},
+		{
+			name:  "Минимум и максимум",
+			input: "max(CPUutilization*) - min(CPUutilization*)",
+			want:  query.Vector{{Value: 30}},
+		},
+		{
+			name:  "Группировка по отсутствующей метке",
+			input: "max by (env) ({host=~\".+\"})",
+			want:  query.Vector{{Value: 40}},
+		},
+		{
+			name:  "Сопоставление сгруппированных рядов",
+			input: "sum by (host) (CPUutilization*) / sum by (host) (Requests)",
+			want:  query.Vector{{Labels: models.Labels{"host": "a"}, Value: 30.0 / 7}},
+		},
+		{
+			name:    "Неоднозначное сопоставление меток",
+			input:   "CPUutilization* / HeapSys",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := query.Parse(tt.input)
+			require.NoError(t, err)
+
+			got, err := query.Evaluate(context.Background(), source, expr, now)
+			if tt.wantErr {
+				require.ErrorIs(t, err, query.ErrInvalidQuery)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluateAt(t *testing.T) {
+	now := time.Unix(10000, 0)
+	at := now.Add(-3 * time.Minute)
+
+	source := &testSource{
+		metrics: []models.MetricInfo{
+			models.NewGaugeMetric("HeapInuse", 30),
+			models.NewGaugeMetric("Created", 1),
+			models.NewCounterMetric("PollCount", 100),
+			models.NewCounterMetric("Requests", 7),
+		},
+		history: map[string][]models.MetricSample{
+			"HeapInuse": {
+				{Timestamp: now.Add(-10 * time.Minute), GaugeValue: 5},
+				{Timestamp: now.Add(-4 * time.Minute), GaugeValue: 10},
+				{Timestamp: now.Add(-2 * time.Minute), GaugeValue: 40},
+				{Timestamp: now.Add(-1 * time.Minute), GaugeValue: 30},
+			},
+			"Created": {
+				{Timestamp: now.Add(-1 * time.Minute), GaugeValue: 1},
+			},
+			"PollCount": {
+				{Timestamp: now.Add(-5 * time.Minute), CounterValue: 60},
+				{Timestamp: now.Add(-2 * time.Minute), CounterValue: 25},
+				{Timestamp: now.Add(-10 * time.Second), CounterValue: 15},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  query.Value
+	}{
+		{
+			name:  "Последнее значение gauge до момента вычисления",
+			input: "HeapInuse",
+			want:  query.Vector{{MetricID: "HeapInuse", Value: 10}},
+		},
+		{
+			name:  "Значение counter без последующих приращений",
+			input: "PollCount",
+			want:  query.Vector{{MetricID: "PollCount", Value: 60}},
+		},
+		{
+			name:  "Арифметика над значениями из истории",
+			input: "HeapInuse * 2",
+			want:  query.Vector{{Value: 20}},
+		},
+		{
+			name:  "Ряд без значений до момента вычисления",
+			input: "Created",
+			want:  query.Vector{},
+		},
+		{
+			name:  "Ряд без истории",
+			input: "Requests",
+			want:  query.Vector{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := query.Parse(tt.input)
+			require.NoError(t, err)
+
+			got, err := query.EvaluateAt(context.Background(), source, expr, at)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluate_HistoryDisabled(t *testing.T) {
+	now := time.Unix(10000, 0)
+
+	source := &testSource{
+		metrics: []models.MetricInfo{
+			models.NewGaugeMetric("HeapInuse", 30),
+			models.NewCounterMetric("PollCount", 100),
+		},
+		historyDisabled: true,
+	}
+
+	t.Run("Выборка без интервала вычисляется", func(t *testing.T) {
+		expr, err := query.Parse("HeapInuse")
+		require.NoError(t, err)
+
+		got, err := query.Evaluate(context.Background(), source, expr, now)
+		require.NoError(t, err)
+		require.Equal(t, query.Vector{{MetricID: "HeapInuse", Value: 30}}, got)
+	})
+
+	for _, input := range []string{"rate(PollCount[1m])", "delta(HeapInuse[5m])"} {
+		t.Run("Функция над интервалом "+input, func(t *testing.T) {
+			expr, err := query.Parse(input)
+			require.NoError(t, err)
+
+			_, err = query.Evaluate(context.Background(), source, expr, now)
+			require.ErrorIs(t, err, query.ErrInvalidQuery)
+			require.ErrorIs(t, err, models.ErrHistoryDisabled)
+		})
+	}
+
+	t.Run("Вычисление на прошедший момент", func(t *testing.T) {
+		expr, err := query.Parse("HeapInuse")
+		require.NoError(t, err)
+
+		_, err = query.EvaluateAt(context.Background(), source, expr, now.Add(-time.Minute))
+		require.ErrorIs(t, err, query.ErrInvalidQuery)
+	})
+}
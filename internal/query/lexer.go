@@ -0,0 +1,194 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind тип лексемы.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenDuration
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBrace
+	tokenRightBrace
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+	tokenAdd
+	tokenSub
+	tokenMul
+	tokenDiv
+	tokenEqual
+	tokenNotEqual
+	tokenRegexp
+	tokenNotRegexp
+)
+
+// token лексема выражения.
+type token struct {
+	value string
+	kind  tokenKind
+	pos   int
+}
+
+// String возвращает представление лексемы для сообщений об ошибках.
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// isOperand проверяет, может ли лексема завершать операнд.
+// Используется для различения умножения и шаблона в названии метрики.
+func (t token) isOperand() bool {
+	switch t.kind {
+	case tokenIdent, tokenNumber, tokenRightParen, tokenRightBrace, tokenRightBracket:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdentRune проверяет, может ли символ входить в название метрики.
+// Символы * и ? используются в шаблонах названий.
+func isIdentRune(r rune) bool {
+	return r == '_' || r == ':' || r == '.' || r == '*' || r == '?' ||
+		unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lex разбивает выражение на лексемы.
+//
+// Символ * внутри или в начале названия метрики считается частью шаблона,
+// поэтому умножение названий метрик необходимо отделять пробелами.
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+
+		var prev token
+		if len(tokens) > 0 {
+			prev = tokens[len(tokens)-1]
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case unicode.IsDigit(r) || (r == '.' && pos+1 < len(runes) && unicode.IsDigit(runes[pos+1])):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+				pos++
+			}
+			// Экспоненциальная запись.
+			if pos < len(runes) && (runes[pos] == 'e' || runes[pos] == 'E') {
+				pos++
+				if pos < len(runes) && (runes[pos] == '+' || runes[pos] == '-') {
+					pos++
+				}
+				for pos < len(runes) && unicode.IsDigit(runes[pos]) {
+					pos++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:pos]), pos: start})
+		case isIdentRune(r) && !(r == '*' && prev.isOperand()):
+			start := pos
+			for pos < len(runes) && isIdentRune(runes[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:pos]), pos: start})
+		case r == '"':
+			start := pos
+			var b strings.Builder
+			pos++
+			for {
+				if pos >= len(runes) {
+					return nil, fmt.Errorf("unterminated string at position %d", start)
+				}
+				if runes[pos] == '"' {
+					pos++
+					break
+				}
+				if runes[pos] == '\\' && pos+1 < len(runes) {
+					pos++
+				}
+				b.WriteRune(runes[pos])
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: b.String(), pos: start})
+		case r == '[':
+			start := pos
+			end := pos + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated range at position %d", start)
+			}
+			tokens = append(tokens,
+				token{kind: tokenLeftBracket, value: "[", pos: start},
+				token{kind: tokenDuration, value: strings.TrimSpace(string(runes[start+1 : end])), pos: start + 1},
+				token{kind: tokenRightBracket, value: "]", pos: end},
+			)
+			pos = end + 1
+		default:
+			kind, size := lexOperator(runes[pos:])
+			if size == 0 {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, pos)
+			}
+			tokens = append(tokens, token{kind: kind, value: string(runes[pos : pos+size]), pos: pos})
+			pos += size
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// lexOperator распознаёт оператор или разделитель в начале runes.
+// Возвращает тип лексемы и её длину (0, если оператор не распознан).
+func lexOperator(runes []rune) (tokenKind, int) {
+	if len(runes) >= 2 {
+		switch string(runes[:2]) {
+		case "!=":
+			return tokenNotEqual, 2
+		case "=~":
+			return tokenRegexp, 2
+		case "!~":
+			return tokenNotRegexp, 2
+		}
+	}
+
+	switch runes[0] {
+	case '(':
+		return tokenLeftParen, 1
+	case ')':
+		return tokenRightParen, 1
+	case '{':
+		return tokenLeftBrace, 1
+	case '}':
+		return tokenRightBrace, 1
+	case ',':
+		return tokenComma, 1
+	case '+':
+		return tokenAdd, 1
+	case '-':
+		return tokenSub, 1
+	case '*':
+		return tokenMul, 1
+	case '/':
+		return tokenDiv, 1
+	case '=':
+		return tokenEqual, 1
+	default:
+		return tokenEOF, 0
+	}
+}
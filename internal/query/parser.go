@@ -0,0 +1,465 @@
+// Package query содержит язык выражений над текущими значениями
+// и историей метрик: лексер, парсер и вычислитель.
+package query
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// functions поддерживаемые функции и признак того,
+// что их аргумент является интервальной выборкой.
+var functions = map[string]bool{
+	"rate":  true,
+	"delta": true,
+}
+
+// aggregations поддерживаемые операторы агрегации.
+var aggregations = map[string]struct{}{
+	"sum": {},
+	"avg": {},
+	"min": {},
+	"max": {},
+}
+
+// Parse разбирает выражение.
+//
+// Поддерживаются числовые константы, выборки метрик вида
+// CPUutilization*{host="a", env!~"dev|test"}, арифметические операции
+// +, -, *, / со скобками, агрегации sum, avg, min и max с необязательной
+// группировкой по меткам (sum by (host) (...) или sum(...) by (host))
+// и функции rate(counter[1m]) и delta(gauge[5m]).
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+
+	err = checkExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// parser парсер выражений методом рекурсивного спуска.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return token{}, fmt.Errorf("expected %s at position %d, got %s", what, t.pos, t)
+	}
+	return t, nil
+}
+
+func (p *parser) unexpected(t token) error {
+	return fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+// parseExpr разбирает сложение и вычитание.
+func (p *parser) parseExpr() (Expr, error) {
+	lhs, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenAdd && t.kind != tokenSub {
+			return lhs, nil
+		}
+		p.next()
+
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs = &BinaryExpr{LHS: lhs, RHS: rhs, Op: t.value}
+	}
+}
+
+// parseTerm разбирает умножение и деление.
+func (p *parser) parseTerm() (Expr, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenMul && t.kind != tokenDiv {
+			return lhs, nil
+		}
+		p.next()
+
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		lhs = &BinaryExpr{LHS: lhs, RHS: rhs, Op: t.value}
+	}
+}
+
+// parseUnary разбирает смену знака.
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind == tokenSub {
+		p.next()
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		if number, ok := expr.(*NumberLiteral); ok {
+			return &NumberLiteral{Value: -number.Value}, nil
+		}
+
+		return &UnaryExpr{Expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary разбирает константы, скобки, вызовы функций и выборки.
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.value, t.pos)
+		}
+
+		return &NumberLiteral{Value: value}, nil
+	case tokenLeftParen:
+		p.next()
+
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tokenRightParen, `")"`)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ParenExpr{Expr: expr}, nil
+	case tokenIdent:
+		next := p.tokens[p.pos+1]
+		if _, ok := aggregations[t.value]; ok && (next.kind == tokenLeftParen || isKeyword(next, "by")) {
+			return p.parseAggregation()
+		}
+		if next.kind == tokenLeftParen {
+			return p.parseCall()
+		}
+		return p.parseSelector()
+	case tokenLeftBrace:
+		return p.parseSelector()
+	default:
+		return nil, p.unexpected(t)
+	}
+}
+
+// isKeyword проверяет, является ли лексема ключевым словом keyword.
+func isKeyword(t token, keyword string) bool {
+	return t.kind == tokenIdent && t.value == keyword
+}
+
+// parseCall разбирает вызов функции.
+func (p *parser) parseCall() (Expr, error) {
+	name := p.next()
+
+	if _, ok := functions[name.value]; !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.value, name.pos)
+	}
+
+	args, err := p.parseArgs(name.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Call{Func: name.value, Args: args}, nil
+}
+
+// parseAggregation разбирает агрегацию. Группировка by (...)
+// может располагаться как до, так и после аргумента.
+func (p *parser) parseAggregation() (Expr, error) {
+	name := p.next()
+	expr := &AggregateExpr{Op: name.value}
+
+	if isKeyword(p.peek(), "by") {
+		grouping, err := p.parseGrouping()
+		if err != nil {
+			return nil, err
+		}
+		expr.Grouping = grouping
+	}
+
+	args, err := p.parseArgs(name.value)
+	if err != nil {
+		return nil, err
+	}
+	expr.Expr = args[0]
+
+	if isKeyword(p.peek(), "by") {
+		if expr.Grouping != nil {
+			return nil, fmt.Errorf("%s() has duplicate grouping at position %d", name.value, p.peek().pos)
+		}
+
+		grouping, err := p.parseGrouping()
+		if err != nil {
+			return nil, err
+		}
+		expr.Grouping = grouping
+	}
+
+	return expr, nil
+}
+
+// parseArgs разбирает единственный аргумент функции
+// или агрегации name в круглых скобках.
+func (p *parser) parseArgs(name string) ([]Expr, error) {
+	_, err := p.expect(tokenLeftParen, `"("`)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []Expr
+	if p.peek().kind != tokenRightParen {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	_, err = p.expect(tokenRightParen, `")"`)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s() expects exactly one argument, got %d", name, len(args))
+	}
+
+	return args, nil
+}
+
+// parseGrouping разбирает список меток группировки by (label, ...).
+func (p *parser) parseGrouping() ([]string, error) {
+	p.next() // by
+
+	_, err := p.expect(tokenLeftParen, `"("`)
+	if err != nil {
+		return nil, err
+	}
+
+	grouping := []string{}
+	for p.peek().kind != tokenRightParen {
+		label, err := p.expect(tokenIdent, "label name")
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.ContainsAny(label.value, "*?") {
+			return nil, fmt.Errorf("invalid label name %q at position %d", label.value, label.pos)
+		}
+
+		grouping = append(grouping, label.value)
+
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+
+	_, err = p.expect(tokenRightParen, `")"`)
+	if err != nil {
+		return nil, err
+	}
+
+	return grouping, nil
+}
+
+// parseSelector разбирает выборку метрик.
+func (p *parser) parseSelector() (Expr, error) {
+	selector := new(VectorSelector)
+
+	if t := p.peek(); t.kind == tokenIdent {
+		p.next()
+
+		_, err := path.Match(t.value, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric name pattern %q at position %d", t.value, t.pos)
+		}
+		selector.Name = t.value
+	}
+
+	if p.peek().kind == tokenLeftBrace {
+		matchers, err := p.parseMatchers()
+		if err != nil {
+			return nil, err
+		}
+		selector.Matchers = matchers
+	}
+
+	if selector.Name == "" && len(selector.Matchers) == 0 {
+		return nil, fmt.Errorf("selector must contain metric name or at least one matcher")
+	}
+
+	if p.peek().kind == tokenLeftBracket {
+		p.next()
+
+		t := p.next()
+		duration, err := time.ParseDuration(t.value)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid range %q at position %d", t.value, t.pos)
+		}
+		selector.Range = duration
+
+		p.next() // ]
+	}
+
+	return selector, nil
+}
+
+// parseMatchers разбирает условия на метки в фигурных скобках.
+func (p *parser) parseMatchers() ([]models.LabelMatcher, error) {
+	p.next() // {
+
+	var matchers []models.LabelMatcher
+	for p.peek().kind != tokenRightBrace {
+		name, err := p.expect(tokenIdent, "label name")
+		if err != nil {
+			return nil, err
+		}
+
+		op := p.next()
+
+		var matchType models.MatchType
+		switch op.kind {
+		case tokenEqual:
+			matchType = models.MatchEqual
+		case tokenNotEqual:
+			matchType = models.MatchNotEqual
+		case tokenRegexp:
+			matchType = models.MatchRegexp
+		case tokenNotRegexp:
+			matchType = models.MatchNotRegexp
+		default:
+			return nil, fmt.Errorf("expected match operator at position %d, got %s", op.pos, op)
+		}
+
+		value, err := p.expect(tokenString, "label value")
+		if err != nil {
+			return nil, err
+		}
+
+		matcher := models.LabelMatcher{Name: name.value, Value: value.value, Type: matchType}
+
+		err = matcher.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		matchers = append(matchers, matcher)
+
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+
+	_, err := p.expect(tokenRightBrace, `"}"`)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchers, nil
+}
+
+// checkExpr проверяет, что интервальные выборки используются
+// только как аргументы функций, которые их ожидают.
+func checkExpr(expr Expr) error {
+	switch e := expr.(type) {
+	case *VectorSelector:
+		if e.Range > 0 {
+			return fmt.Errorf("range selector %s can be used only as function argument", e)
+		}
+	case *UnaryExpr:
+		return checkExpr(e.Expr)
+	case *ParenExpr:
+		return checkExpr(e.Expr)
+	case *BinaryExpr:
+		err := checkExpr(e.LHS)
+		if err != nil {
+			return err
+		}
+		return checkExpr(e.RHS)
+	case *AggregateExpr:
+		return checkExpr(e.Expr)
+	case *Call:
+		for _, arg := range e.Args {
+			if functions[e.Func] {
+				selector, ok := arg.(*VectorSelector)
+				if !ok || selector.Range == 0 {
+					return fmt.Errorf("%s() expects range selector, got %s", e.Func, arg)
+				}
+				continue
+			}
+
+			err := checkExpr(arg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/query"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Выборка по названию",
+			input: "HeapInuse",
+			want:  "HeapInuse",
+		},
+		{
+			name:  "Выборка по шаблону и меткам",
+			input: `CPUutilization*{host="a",env!~"dev|test"}`,
+			want:  `CPUutilization*{host="a", env!~"dev|test"}`,
+		},
+		{
+			name:  "Выборка только по меткам",
+			input: `{__name__=~"Heap.*"}`,
+			want:  `{__name__=~"Heap.*"}`,
+		},
+		{
+			name:  "Приоритет операций",
+			input: "HeapInuse / HeapSys * 100 - 1",
+			want:  "HeapInuse / HeapSys * 100 - 1",
+		},
+		{
+			name:  "Скобки и смена знака",
+			input: "-(HeapInuse+HeapSys)*-2",
+			want:  "-(HeapInuse + HeapSys) * -2",
+		},
+		{
+			name:  "Умножение без пробелов после скобки",
+			input: "sum(CPUutilization*)*2",
+			want:  "sum(CPUutilization*) * 2",
+		},
+		{
+			name:  "Функция rate",
+			input: "rate(PollCount[1m])",
+			want:  "rate(PollCount[1m0s])",
+		},
+		{
+			name:  "Функция delta",
+			input: "delta(HeapInuse[5m])",
+			want:  "delta(HeapInuse[5m0s])",
+		},
+		{
+			name:  "Агрегация с группировкой перед аргументом",
+			input: "avg by (host,env) (CPUutilization*)",
+			want:  "avg by (host, env) (CPUutilization*)",
+		},
+		{
+			name:  "Агрегация с группировкой после аргумента",
+			input: "max(rate(PollCount[1m])) by (host) / 2",
+			want:  "max by (host) (rate(PollCount[1m0s])) / 2",
+		},
+		{
+			name:  "Агрегация с пустой группировкой",
+			input: "min by () (HeapInuse)",
+			want:  "min by () (HeapInuse)",
+		},
+		{
+			name:  "Метрика с названием агрегации",
+			input: "sum + 1",
+			want:  "sum + 1",
+		},
+		{
+			name:    "Повторная группировка",
+			input:   "sum by (host) (HeapInuse) by (env)",
+			wantErr: true,
+		},
+		{
+			name:    "Шаблон в метке группировки",
+			input:   "sum by (host*) (HeapInuse)",
+			wantErr: true,
+		},
+		{
+			name:    "Интервальная выборка вне функции",
+			input:   "PollCount[1m]",
+			wantErr: true,
+		},
+		{
+			name:    "rate без интервальной выборки",
+			input:   "rate(PollCount)",
+			wantErr: true,
+		},
+		{
+			name:    "Некорректный интервал",
+			input:   "rate(PollCount[soon])",
+			wantErr: true,
+		},
+		{
+			name:    "Неизвестная функция",
+			input:   "median(PollCount)",
+			wantErr: true,
+		},
+		{
+			name:    "Незакрытая скобка",
+			input:   "sum(PollCount",
+			wantErr: true,
+		},
+		{
+			name:    "Некорректное регулярное выражение",
+			input:   `PollCount{host=~"("}`,
+			wantErr: true,
+		},
+		{
+			name:    "Пустая выборка",
+			input:   "{}",
+			wantErr: true,
+		},
+		{
+			name:    "Лишние символы",
+			input:   "HeapInuse HeapSys",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := query.Parse(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/query"
+)
+
+type recordItem struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Name   string            `json:"name"`
+	Expr   string            `json:"expr"`
+}
+
+//easyjson:json
+type recordsFile struct {
+	Records []recordItem `json:"records"`
+}
+
+// LoadRules загружает правила записи из JSON-файла вида
+//
+//	{"records": [{"name": "cpu:utilization:sum", "expr": "sum(CPUutilization*)",
+//	  "labels": {"source": "recording"}}]}
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse recording rules file %q: %v", path, err)
+	}
+
+	return rules, nil
+}
+
+// ParseRules парсит и проверяет правила записи в формате JSON.
+// Названия правил должны быть уникальны.
+func ParseRules(data []byte) ([]Rule, error) {
+	var file recordsFile
+
+	err := easyjson.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(file.Records))
+	rules := make([]Rule, len(file.Records))
+
+	for i, item := range file.Records {
+		err = models.ValidateMetricID(item.Name)
+		if err != nil {
+			return nil, fmt.Errorf("record #%d: %v", i+1, err)
+		}
+
+		expr, err := query.Parse(item.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("record %q: invalid expression: %v", item.Name, err)
+		}
+
+		if _, exists := names[item.Name]; exists {
+			return nil, fmt.Errorf("duplicate record name %q", item.Name)
+		}
+		names[item.Name] = struct{}{}
+
+		rules[i] = Rule{
+			Labels: item.Labels,
+			Expr:   expr,
+			Name:   item.Name,
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,212 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package recording
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalRecording(in *jlexer.Lexer, out *recordsFile) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "records":
+			if in.IsNull() {
+				in.Skip()
+				out.Records = nil
+			} else {
+				in.Delim('[')
+				if out.Records == nil {
+					if !in.IsDelim(']') {
+						out.Records = make([]recordItem, 0, 1)
+					} else {
+						out.Records = []recordItem{}
+					}
+				} else {
+					out.Records = (out.Records)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 recordItem
+					easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalRecording1(in, &v1)
+					out.Records = append(out.Records, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalRecording(out *jwriter.Writer, in recordsFile) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"records\":"
+		out.RawString(prefix[1:])
+		if in.Records == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Records {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalRecording1(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v recordsFile) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalRecording(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v recordsFile) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalRecording(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *recordsFile) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalRecording(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *recordsFile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalRecording(l, v)
+}
+func easyjson8ceb9162DecodeGithubComXantiniumMetrixInternalRecording1(in *jlexer.Lexer, out *recordItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v4 string
+					v4 = string(in.String())
+					(out.Labels)[key] = v4
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "name":
+			out.Name = string(in.String())
+		case "expr":
+			out.Expr = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8ceb9162EncodeGithubComXantiniumMetrixInternalRecording1(out *jwriter.Writer, in recordItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v5First := true
+			for v5Name, v5Value := range in.Labels {
+				if v5First {
+					v5First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v5Name))
+				out.RawByte(':')
+				out.String(string(v5Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"name\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"expr\":"
+		out.RawString(prefix)
+		out.String(string(in.Expr))
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,75 @@
+package recording_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/recording"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		labels  map[string]models.Labels
+		wantErr bool
+	}{
+		{
+			name: "Корректные правила",
+			data: `{"records":[
+				{"name":"cpu:utilization:sum","expr":"sum(CPUutilization*)","labels":{"source":"recording"}},
+				{"name":"heap:usage:ratio","expr":"HeapInuse / HeapSys"}
+			]}`,
+			want: map[string]string{
+				"cpu:utilization:sum": "sum(CPUutilization*)",
+				"heap:usage:ratio":    "HeapInuse / HeapSys",
+			},
+			labels: map[string]models.Labels{
+				"cpu:utilization:sum": {"source": "recording"},
+			},
+		},
+		{
+			name:    "Пустое название",
+			data:    `{"records":[{"name":"","expr":"HeapInuse"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Некорректное выражение",
+			data:    `{"records":[{"name":"heap","expr":"HeapInuse /"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Повторяющиеся названия",
+			data: `{"records":[
+				{"name":"heap","expr":"HeapInuse"},
+				{"name":"heap","expr":"HeapSys"}
+			]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Некорректный JSON",
+			data:    `{"records":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := recording.ParseRules([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, rules, len(tt.want))
+
+			for _, rule := range rules {
+				require.Equal(t, tt.want[rule.Name], rule.Expr.String())
+				require.Equal(t, tt.labels[rule.Name], rule.Labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Package recording содержит правила записи: выражения языка запросов
+// периодически вычисляются по текущим значениям и истории метрик,
+// а результаты сохраняются как новые метрики типа Gauge.
+package recording
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/query"
+)
+
+// Rule правило записи.
+//
+// Результат вычисления Expr сохраняется как метрики типа Gauge
+// с идентификатором Name: скаляр - одной метрикой без меток,
+// вектор - отдельной метрикой для каждого набора меток.
+type Rule struct {
+	// Labels метки, добавляемые к результатам.
+	// Перекрывают одноимённые метки результатов.
+	Labels models.Labels
+	// Expr вычисляемое выражение.
+	Expr query.Expr
+	// Name идентификатор сохраняемой метрики. Рекомендуется
+	// использовать названия с ":" (например, cpu:utilization:sum),
+	// чтобы они не попадали в выборки исходных метрик.
+	Name string
+}
+
+// MetricsWriter сущность, сохраняющая результаты правил.
+type MetricsWriter interface {
+	UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error
+}
+
+// ManagerOptions параметры менеджера правил записи.
+type ManagerOptions struct {
+	// Source источник значений и истории метрик.
+	Source query.Source
+	// Writer сущность для сохранения результатов.
+	Writer MetricsWriter
+	// Rules правила записи.
+	Rules []Rule
+}
+
+// NewManager создаёт новый менеджер правил записи.
+func NewManager(opts ManagerOptions) *Manager {
+	return &Manager{
+		source: opts.Source,
+		writer: opts.Writer,
+		rules:  slices.Clone(opts.Rules),
+	}
+}
+
+// Manager структура, вычисляющая правила записи.
+type Manager struct {
+	source query.Source
+	writer MetricsWriter
+	rules  []Rule
+}
+
+// Rules возвращает правила записи.
+func (manager *Manager) Rules() []Rule {
+	return slices.Clone(manager.rules)
+}
+
+// Evaluate вычисляет все правила на момент now и сохраняет результаты
+// одним обновлением. Ошибка вычисления одного правила не мешает
+// сохранению результатов остальных.
+func (manager *Manager) Evaluate(ctx context.Context, now time.Time) error {
+	if len(manager.rules) == 0 {
+		return nil
+	}
+
+	var (
+		metrics []models.MetricInfo
+		errs    []error
+	)
+
+	for _, rule := range manager.rules {
+		value, err := query.Evaluate(ctx, manager.source, rule.Expr, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+			continue
+		}
+
+		metrics = append(metrics, rule.materialize(value)...)
+	}
+
+	if len(metrics) != 0 {
+		err := manager.writer.UpdateMetrics(ctx, metrics)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// materialize преобразует результат вычисления правила в метрики.
+// Значения, не являющиеся конечными числами (например,
+// результаты деления на ноль), не сохраняются.
+func (rule Rule) materialize(value query.Value) []models.MetricInfo {
+	switch v := value.(type) {
+	case query.Scalar:
+		if !isFinite(float64(v)) {
+			return nil
+		}
+		return []models.MetricInfo{rule.newMetric(nil, float64(v))}
+	case query.Vector:
+		metrics := make([]models.MetricInfo, 0, len(v))
+		seen := make(map[string]struct{}, len(v))
+
+		for _, sample := range v {
+			if !isFinite(sample.Value) {
+				continue
+			}
+
+			metric := rule.newMetric(sample.Labels, sample.Value)
+
+			// После добавления меток правила наборы меток
+			// могут совпасть, сохраняется первый из них.
+			key := metric.Labels().Key()
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			metrics = append(metrics, metric)
+		}
+
+		return metrics
+	default:
+		return nil
+	}
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func (rule Rule) newMetric(labels models.Labels, value float64) models.MetricInfo {
+	metric := models.NewGaugeMetric(rule.Name, value)
+
+	if len(labels) == 0 && len(rule.Labels) == 0 {
+		return metric
+	}
+
+	merged := make(models.Labels, len(labels)+len(rule.Labels))
+	maps.Copy(merged, labels)
+	maps.Copy(merged, rule.Labels)
+
+	return metric.WithLabels(merged)
+}
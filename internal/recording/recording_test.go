@@ -0,0 +1,144 @@
+package recording_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/query"
+	"github.com/xantinium/metrix/internal/recording"
+)
+
+// memSource источник метрик, в который сохраняются результаты правил.
+type memSource struct {
+	history map[string][]models.MetricSample
+	metrics []models.MetricInfo
+	written []models.MetricInfo
+	err     error
+}
+
+func (source *memSource) GetAllMetrics(_ context.Context, _ models.Labels) ([]models.MetricInfo, error) {
+	return source.metrics, nil
+}
+
+func (source *memSource) GetMetricHistory(_ context.Context, _ models.MetricType, id string, _ models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	var samples []models.MetricSample
+	for _, sample := range source.history[id] {
+		if !sample.Timestamp.Before(from) && !sample.Timestamp.After(to) {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples, nil
+}
+
+func (source *memSource) UpdateMetrics(_ context.Context, metrics []models.MetricInfo) error {
+	if source.err != nil {
+		return source.err
+	}
+
+	source.written = append(source.written, metrics...)
+	return nil
+}
+
+func mustParse(t *testing.T, input string) query.Expr {
+	t.Helper()
+
+	expr, err := query.Parse(input)
+	require.NoError(t, err)
+
+	return expr
+}
+
+func TestManager_Evaluate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+
+	source := &memSource{
+		metrics: []models.MetricInfo{
+			models.NewGaugeMetric("HeapInuse", 25),
+			models.NewGaugeMetric("HeapSys", 100),
+			models.NewGaugeMetric("CPUutilization1", 10),
+			models.NewGaugeMetric("CPUutilization2", 30),
+			models.NewGaugeMetric("Latency", 5).WithLabels(models.Labels{"host": "a"}),
+			models.NewGaugeMetric("Latency", 7).WithLabels(models.Labels{"host": "b"}),
+			models.NewCounterMetric("PollCount", 500),
+		},
+		history: map[string][]models.MetricSample{
+			"PollCount": {
+				{Timestamp: now.Add(-30 * time.Second), CounterValue: 60},
+				{Timestamp: now.Add(-10 * time.Second), CounterValue: 60},
+			},
+		},
+	}
+
+	manager := recording.NewManager(recording.ManagerOptions{
+		Source: source,
+		Writer: source,
+		Rules: []recording.Rule{
+			{Name: "poll:rate1m", Expr: mustParse(t, "rate(PollCount[1m])")},
+			{Name: "heap:usage:ratio", Expr: mustParse(t, "HeapInuse / HeapSys")},
+			{Name: "cpu:utilization:sum", Expr: mustParse(t, "sum(CPUutilization*)"), Labels: models.Labels{"source": "recording"}},
+			{Name: "latency:ms", Expr: mustParse(t, "Latency * 1000")},
+			{Name: "answer", Expr: mustParse(t, "6 * 7")},
+		},
+	})
+
+	err := manager.Evaluate(ctx, now)
+	require.NoError(t, err)
+
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("poll:rate1m", 2),
+		models.NewGaugeMetric("heap:usage:ratio", 0.25),
+		models.NewGaugeMetric("cpu:utilization:sum", 40).WithLabels(models.Labels{"source": "recording"}),
+		models.NewGaugeMetric("latency:ms", 5000).WithLabels(models.Labels{"host": "a"}),
+		models.NewGaugeMetric("latency:ms", 7000).WithLabels(models.Labels{"host": "b"}),
+		models.NewGaugeMetric("answer", 42),
+	}, source.written)
+}
+
+func TestManager_EvaluateErrors(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+
+	source := &memSource{
+		metrics: []models.MetricInfo{
+			models.NewGaugeMetric("HeapInuse", 25),
+			models.NewGaugeMetric("CPUutilization1", 10),
+			models.NewGaugeMetric("CPUutilization2", 30),
+			models.NewGaugeMetric("Latency", 5).WithLabels(models.Labels{"host": "a"}),
+			models.NewGaugeMetric("Latency", 7).WithLabels(models.Labels{"host": "a", "env": "dev"}),
+		},
+	}
+
+	manager := recording.NewManager(recording.ManagerOptions{
+		Source: source,
+		Writer: source,
+		Rules: []recording.Rule{
+			// Значения слева не различаются метками.
+			{Name: "broken", Expr: mustParse(t, "CPUutilization* / HeapInuse")},
+			{Name: "heap:doubled", Expr: mustParse(t, "HeapInuse * 2")},
+			{Name: "infinity", Expr: mustParse(t, "HeapInuse / 0")},
+			// После добавления меток правила наборы меток совпадают.
+			{Name: "latency:any", Expr: mustParse(t, "Latency"), Labels: models.Labels{"env": "prod"}},
+		},
+	})
+
+	err := manager.Evaluate(ctx, now)
+	require.ErrorContains(t, err, `rule "broken"`)
+
+	require.Equal(t, []models.MetricInfo{
+		models.NewGaugeMetric("heap:doubled", 50),
+		models.NewGaugeMetric("latency:any", 7).WithLabels(models.Labels{"host": "a", "env": "prod"}),
+	}, source.written)
+
+	writeErr := errors.New("storage is unavailable")
+	source.err = writeErr
+
+	err = manager.Evaluate(ctx, now)
+	require.ErrorIs(t, err, writeErr)
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// DeleteMetric удаляет метрику типа metricType с идентификатором id
+// и набором меток labels вместе с её историей.
+func (repo *MetricsRepository) DeleteMetric(ctx context.Context, metricType models.MetricType, id string, labels models.Labels) error {
+	err := repo.storage.DeleteMetric(ctx, metricType, id, labels)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s metric id=%s: %w", metricType, id, err)
+	}
+
+	repo.onMetricsUpdate(ctx)
+	return nil
+}
+
+// DeleteMetrics удаляет все метрики, удовлетворяющие условиям matchers.
+// Идентификатор метрики доступен в условиях как метка models.MetricNameLabel.
+// Если metricType не пуст, удаляются только метрики этого типа.
+//
+// Возвращает количество удалённых метрик.
+func (repo *MetricsRepository) DeleteMetrics(ctx context.Context, metricType models.MetricType, matchers []models.LabelMatcher) (int, error) {
+	allMetrics, err := repo.storage.GetAllMetrics(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get all metrics: %v", err)
+	}
+
+	var deleted int
+	for _, metric := range allMetrics {
+		if metricType != "" && metric.Type() != metricType {
+			continue
+		}
+		if !models.MatchLabels(matchers, metric.ID(), metric.Labels()) {
+			continue
+		}
+
+		err = repo.storage.DeleteMetric(ctx, metric.Type(), metric.ID(), metric.Labels())
+		// Метрика могла быть удалена одновременно с запросом.
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to delete %s metric id=%s: %w", metric.Type(), metric.ID(), err)
+			break
+		}
+
+		deleted++
+	}
+
+	if deleted > 0 {
+		repo.onMetricsUpdate(ctx)
+	}
+
+	return deleted, err
+}
+
+// DeleteStaleMetrics удаляет метрики, последнее обновление
+// которых было раньше updatedBefore, вместе с их историей.
+//
+// Возвращает количество удалённых метрик.
+func (repo *MetricsRepository) DeleteStaleMetrics(ctx context.Context, updatedBefore time.Time) (int, error) {
+	deleted, err := repo.storage.DeleteStaleMetrics(ctx, updatedBefore)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete stale metrics: %w", err)
+	}
+
+	if deleted > 0 {
+		repo.onMetricsUpdate(ctx)
+	}
+
+	return deleted, nil
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"time"
 
 	"github.com/xantinium/metrix/internal/models"
 )
@@ -9,13 +10,25 @@ import (
 // MetricsStorage интерфейс хранилища метрик.
 type MetricsStorage interface {
 	Destroy(ctx context.Context)
-	GetGaugeMetric(ctx context.Context, id string) (float64, error)
-	GetCounterMetric(ctx context.Context, id string) (int64, error)
-	GetAllMetrics(ctx context.Context) ([]models.MetricInfo, error)
-	UpdateGaugeMetric(ctx context.Context, id string, value float64) (float64, error)
-	UpdateCounterMetric(ctx context.Context, id string, value int64) (int64, error)
+	GetGaugeMetric(ctx context.Context, id string, labels models.Labels) (float64, error)
+	GetCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error)
+	GetHistogramMetric(ctx context.Context, id string, labels models.Labels) (models.HistogramValue, error)
+	GetSummaryMetric(ctx context.Context, id string, labels models.Labels) (models.SummaryValue, error)
+	GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error)
+	UpdateGaugeMetric(ctx context.Context, id string, labels models.Labels, value float64) (float64, error)
+	UpdateCounterMetric(ctx context.Context, id string, labels models.Labels, value int64) (int64, error)
+	UpdateHistogramMetric(ctx context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error)
+	UpdateSummaryMetric(ctx context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error)
 	UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error
+	DeleteMetric(ctx context.Context, metricType models.MetricType, id string, labels models.Labels) error
+	DeleteStaleMetrics(ctx context.Context, updatedBefore time.Time) (int, error)
+	GetMetricHistory(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error)
+	PruneHistory(ctx context.Context, now time.Time) (int, error)
 	SaveMetrics(ctx context.Context) error
+	GetSilences(ctx context.Context) ([]models.Silence, error)
+	CreateSilence(ctx context.Context, silence models.Silence) error
+	ExpireSilence(ctx context.Context, id string, now time.Time) (models.Silence, error)
+	DeleteExpiredSilences(ctx context.Context, endedBefore time.Time) (int, error)
 }
 
 // DatabaseChecker интерфейс для проверки соединения с БД.
@@ -4,6 +4,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/xantinium/metrix/internal/logger"
 	"github.com/xantinium/metrix/internal/models"
@@ -33,22 +34,36 @@ type MetricsRepository struct {
 	syncMetrics bool
 }
 
-// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id.
-func (repo *MetricsRepository) GetGaugeMetric(ctx context.Context, id string) (float64, error) {
-	return repo.storage.GetGaugeMetric(ctx, id)
+// GetGaugeMetric возвращает метрику типа Gauge по идентификатору id
+// и набору меток labels.
+func (repo *MetricsRepository) GetGaugeMetric(ctx context.Context, id string, labels models.Labels) (float64, error) {
+	return repo.storage.GetGaugeMetric(ctx, id, labels)
 }
 
-// GetCounterMetric возвращает метрику типа Counter по идентификатору id.
-func (repo *MetricsRepository) GetCounterMetric(ctx context.Context, id string) (int64, error) {
-	return repo.storage.GetCounterMetric(ctx, id)
+// GetCounterMetric возвращает метрику типа Counter по идентификатору id
+// и набору меток labels.
+func (repo *MetricsRepository) GetCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error) {
+	return repo.storage.GetCounterMetric(ctx, id, labels)
+}
+
+// GetHistogramMetric возвращает метрику типа Histogram по идентификатору id
+// и набору меток labels.
+func (repo *MetricsRepository) GetHistogramMetric(ctx context.Context, id string, labels models.Labels) (models.HistogramValue, error) {
+	return repo.storage.GetHistogramMetric(ctx, id, labels)
+}
+
+// GetSummaryMetric возвращает метрику типа Summary по идентификатору id
+// и набору меток labels.
+func (repo *MetricsRepository) GetSummaryMetric(ctx context.Context, id string, labels models.Labels) (models.SummaryValue, error) {
+	return repo.storage.GetSummaryMetric(ctx, id, labels)
 }
 
 // UpdateGaugeMetric обновляет текущее значение метрики типа Gauge
-// с идентификатором id, перезаписывая его значением value.
-func (repo *MetricsRepository) UpdateGaugeMetric(ctx context.Context, id string, value float64) (float64, error) {
-	updatedValue, err := repo.storage.UpdateGaugeMetric(ctx, id, value)
+// с идентификатором id и набором меток labels, перезаписывая его значением value.
+func (repo *MetricsRepository) UpdateGaugeMetric(ctx context.Context, id string, labels models.Labels, value float64) (float64, error) {
+	updatedValue, err := repo.storage.UpdateGaugeMetric(ctx, id, labels, value)
 	if err != nil {
-		return 0, fmt.Errorf("failed to update gauge metric id=%s value=%f: %v", id, value, err)
+		return 0, fmt.Errorf("failed to update gauge metric id=%s value=%f: %w", id, value, err)
 	}
 
 	repo.onMetricsUpdate(ctx)
@@ -56,11 +71,37 @@ func (repo *MetricsRepository) UpdateGaugeMetric(ctx context.Context, id string,
 }
 
 // UpdateCounterMetric обновляет текущее значение метрики типа Counter
-// с идентификатором id, добавляя к нему значение value.
-func (repo *MetricsRepository) UpdateCounterMetric(ctx context.Context, id string, value int64) (int64, error) {
-	updatedValue, err := repo.storage.UpdateCounterMetric(ctx, id, value)
+// с идентификатором id и набором меток labels, добавляя к нему значение value.
+func (repo *MetricsRepository) UpdateCounterMetric(ctx context.Context, id string, labels models.Labels, value int64) (int64, error) {
+	updatedValue, err := repo.storage.UpdateCounterMetric(ctx, id, labels, value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update counter metric id=%s value=%d: %w", id, value, err)
+	}
+
+	repo.onMetricsUpdate(ctx)
+	return updatedValue, nil
+}
+
+// UpdateHistogramMetric обновляет текущее значение метрики типа Histogram
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+// Границы интервалов обоих значений должны совпадать.
+func (repo *MetricsRepository) UpdateHistogramMetric(ctx context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error) {
+	updatedValue, err := repo.storage.UpdateHistogramMetric(ctx, id, labels, value)
+	if err != nil {
+		return models.HistogramValue{}, fmt.Errorf("failed to update histogram metric id=%s: %w", id, err)
+	}
+
+	repo.onMetricsUpdate(ctx)
+	return updatedValue, nil
+}
+
+// UpdateSummaryMetric обновляет текущее значение метрики типа Summary
+// с идентификатором id и набором меток labels, объединяя его со значением value.
+// Точность обоих скетчей должна совпадать.
+func (repo *MetricsRepository) UpdateSummaryMetric(ctx context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error) {
+	updatedValue, err := repo.storage.UpdateSummaryMetric(ctx, id, labels, value)
 	if err != nil {
-		return 0, fmt.Errorf("failed to update counter metric id=%s value=%d: %v", id, value, err)
+		return models.SummaryValue{}, fmt.Errorf("failed to update summary metric id=%s: %w", id, err)
 	}
 
 	repo.onMetricsUpdate(ctx)
@@ -73,9 +114,10 @@ func (repo *MetricsRepository) UpdateMetrics(ctx context.Context, metrics []mode
 	return repo.storage.UpdateMetrics(ctx, metrics)
 }
 
-// GetAllMetrics возвращает все существующие метрики.
-func (repo *MetricsRepository) GetAllMetrics(ctx context.Context) ([]models.MetricInfo, error) {
-	metrics, err := repo.storage.GetAllMetrics(ctx)
+// GetAllMetrics возвращает все существующие метрики,
+// содержащие метки из filter. Пустой фильтр возвращает все метрики.
+func (repo *MetricsRepository) GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error) {
+	metrics, err := repo.storage.GetAllMetrics(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all metrics: %v", err)
 	}
@@ -83,6 +125,30 @@ func (repo *MetricsRepository) GetAllMetrics(ctx context.Context) ([]models.Metr
 	return metrics, nil
 }
 
+// GetMetricHistory возвращает значения метрики с идентификатором id,
+// типом metricType и набором меток labels, полученные в промежутке [from, to].
+func (repo *MetricsRepository) GetMetricHistory(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	samples, err := repo.storage.GetMetricHistory(ctx, metricType, id, labels, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of %s metric id=%s: %w", metricType, id, err)
+	}
+
+	return samples, nil
+}
+
+// PruneHistory удаляет значения истории, вышедшие
+// за время хранения к моменту now.
+//
+// Возвращает количество удалённых значений.
+func (repo *MetricsRepository) PruneHistory(ctx context.Context, now time.Time) (int, error) {
+	pruned, err := repo.storage.PruneHistory(ctx, now)
+	if err != nil {
+		return pruned, fmt.Errorf("failed to prune metrics history: %w", err)
+	}
+
+	return pruned, nil
+}
+
 // CheckDatabase проверяет соединение с БД.
 func (repo *MetricsRepository) CheckDatabase(ctx context.Context) error {
 	return repo.dbChecker.Ping(ctx)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -14,7 +15,7 @@ import (
 func TestMetricsRepository_UpdateMetric(t *testing.T) {
 	ctx := context.Background()
 
-	storage, err := memstorage.NewMemStorage("metrix.db", false)
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,9 +59,9 @@ func TestMetricsRepository_UpdateMetric(t *testing.T) {
 	for _, oper := range updateOperations {
 		switch oper.metricType {
 		case models.Gauge:
-			_, err = repo.UpdateGaugeMetric(ctx, oper.metricID, oper.metricValue)
+			_, err = repo.UpdateGaugeMetric(ctx, oper.metricID, nil, oper.metricValue)
 		case models.Counter:
-			_, err = repo.UpdateCounterMetric(ctx, oper.metricID, int64(oper.metricValue))
+			_, err = repo.UpdateCounterMetric(ctx, oper.metricID, nil, int64(oper.metricValue))
 		default:
 			err = fmt.Errorf("unknown metric type %q", oper.metricType)
 		}
@@ -73,15 +74,122 @@ func TestMetricsRepository_UpdateMetric(t *testing.T) {
 		allocValue, randomValue float64
 	)
 
-	allocValue, err = repo.GetGaugeMetric(ctx, "Alloc")
+	allocValue, err = repo.GetGaugeMetric(ctx, "Alloc", nil)
 	require.NoError(t, err)
 	require.Equal(t, 2.1, allocValue)
 
-	randomValue, err = repo.GetGaugeMetric(ctx, "RandomValue")
+	randomValue, err = repo.GetGaugeMetric(ctx, "RandomValue", nil)
 	require.NoError(t, err)
 	require.Equal(t, 78.0, randomValue)
 
-	pollCount, err = repo.GetCounterMetric(ctx, "PollCount")
+	pollCount, err = repo.GetCounterMetric(ctx, "PollCount", nil)
 	require.NoError(t, err)
 	require.Equal(t, int64(300), pollCount)
 }
+
+func TestAggregateSamples(t *testing.T) {
+	from := time.Unix(1000, 0)
+	step := 10 * time.Second
+
+	t.Run("Агрегация метрики типа Gauge", func(t *testing.T) {
+		samples := []models.MetricSample{
+			{Timestamp: from.Add(1 * time.Second), GaugeValue: 4},
+			{Timestamp: from.Add(2 * time.Second), GaugeValue: 1},
+			{Timestamp: from.Add(9 * time.Second), GaugeValue: 7},
+			// Интервал [from+10s, from+20s) пропущен: в нём нет значений.
+			{Timestamp: from.Add(25 * time.Second), GaugeValue: 3},
+		}
+
+		got := AggregateSamples(models.Gauge, samples, from, step)
+		require.Equal(t, []models.MetricBucket{
+			{Start: from, Min: 1, Max: 7, Avg: 4, Last: 7, Count: 3},
+			{Start: from.Add(20 * time.Second), Min: 3, Max: 3, Avg: 3, Last: 3, Count: 1},
+		}, got)
+	})
+
+	t.Run("Агрегация метрики типа Counter", func(t *testing.T) {
+		samples := []models.MetricSample{
+			{Timestamp: from, CounterValue: 2},
+			{Timestamp: from.Add(5 * time.Second), CounterValue: 6},
+			{Timestamp: from.Add(10 * time.Second), CounterValue: 1},
+		}
+
+		got := AggregateSamples(models.Counter, samples, from, step)
+		require.Equal(t, []models.MetricBucket{
+			{Start: from, Min: 2, Max: 6, Avg: 4, Last: 6, Sum: 8, Count: 2},
+			{Start: from.Add(10 * time.Second), Min: 1, Max: 1, Avg: 1, Last: 1, Sum: 1, Count: 1},
+		}, got)
+	})
+
+	t.Run("Пустая история", func(t *testing.T) {
+		require.Empty(t, AggregateSamples(models.Gauge, nil, from, step))
+	})
+}
+
+func TestMetricsRepository_DeleteMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: t.TempDir() + "/metrix.db"})
+	require.NoError(t, err)
+
+	repo := NewMetricsRepository(MetricsRepositoryOptions{
+		Storage: storage,
+	})
+
+	hostA := models.Labels{"host": "a"}
+	hostB := models.Labels{"host": "b"}
+
+	require.NoError(t, repo.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization1", 10).WithLabels(hostA),
+		models.NewGaugeMetric("CPUutilization2", 20).WithLabels(hostA),
+		models.NewGaugeMetric("CPUutilization1", 30).WithLabels(hostB),
+		models.NewCounterMetric("CPUutilization1", 1).WithLabels(hostA),
+		models.NewGaugeMetric("Alloc", 1).WithLabels(hostA),
+	}))
+
+	deleted, err := repo.DeleteMetrics(ctx, models.Gauge, []models.LabelMatcher{
+		{Name: models.MetricNameLabel, Value: "CPUutilization.*", Type: models.MatchRegexp},
+		{Name: "host", Value: "a", Type: models.MatchEqual},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	all, err := repo.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization1", 30).WithLabels(hostB),
+		models.NewCounterMetric("CPUutilization1", 1).WithLabels(hostA),
+		models.NewGaugeMetric("Alloc", 1).WithLabels(hostA),
+	}, all)
+
+	deleted, err = repo.DeleteMetrics(ctx, "", []models.LabelMatcher{
+		{Name: "host", Value: "a", Type: models.MatchEqual},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	err = repo.DeleteMetric(ctx, models.Gauge, "Alloc", hostA)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func TestMetricsRepository_GetMetricRange(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: t.TempDir() + "/metrix.db"})
+	require.NoError(t, err)
+
+	repo := NewMetricsRepository(MetricsRepositoryOptions{
+		Storage: storage,
+	})
+
+	from := time.Unix(0, 0)
+
+	_, err = repo.GetMetricRange(ctx, models.Gauge, "Alloc", nil, from, from.Add(time.Minute), 0)
+	require.ErrorIs(t, err, models.ErrInvalidRange)
+
+	_, err = repo.GetMetricRange(ctx, models.Gauge, "Alloc", nil, from, from.Add(-time.Minute), time.Second)
+	require.ErrorIs(t, err, models.ErrInvalidRange)
+
+	_, err = repo.GetMetricRange(ctx, models.Gauge, "Alloc", nil, from, from.Add(MaxRangeBuckets*time.Second), time.Second)
+	require.ErrorIs(t, err, models.ErrInvalidRange)
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// MaxRangeBuckets максимальное количество интервалов,
+// которое может быть запрошено за один раз.
+const MaxRangeBuckets = 11000
+
+// GetMetricRange возвращает значения метрики с идентификатором id,
+// типом metricType и набором меток labels в промежутке [from, to],
+// агрегированные по интервалам длиной step. Интервалы без значений пропускаются.
+func (repo *MetricsRepository) GetMetricRange(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time, step time.Duration) ([]models.MetricBucket, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("%w: step must be positive", models.ErrInvalidRange)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: end of range cannot be before its start", models.ErrInvalidRange)
+	}
+	if to.Sub(from)/step >= MaxRangeBuckets {
+		return nil, fmt.Errorf("%w: too many buckets requested, increase step", models.ErrInvalidRange)
+	}
+
+	samples, err := repo.GetMetricHistory(ctx, metricType, id, labels, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateSamples(metricType, samples, from, step), nil
+}
+
+// AggregateSamples разбивает значения samples на интервалы длиной step,
+// отсчитываемые от момента from, и вычисляет для каждого из них
+// минимум, максимум, среднее и последнее значение.
+// Для метрик типа Counter дополнительно вычисляется сумма приращений.
+//
+// Ожидается, что samples отсортированы по времени.
+func AggregateSamples(metricType models.MetricType, samples []models.MetricSample, from time.Time, step time.Duration) []models.MetricBucket {
+	buckets := make([]models.MetricBucket, 0)
+
+	var (
+		sum     float64
+		current *models.MetricBucket
+	)
+
+	for _, sample := range samples {
+		index := sample.Timestamp.Sub(from) / step
+		start := from.Add(index * step)
+
+		value := sample.GaugeValue
+		if metricType == models.Counter {
+			value = float64(sample.CounterValue)
+		}
+
+		if current == nil || !current.Start.Equal(start) {
+			buckets = append(buckets, models.MetricBucket{
+				Start: start,
+				Min:   value,
+				Max:   value,
+			})
+			current = &buckets[len(buckets)-1]
+			sum = 0
+		}
+
+		current.Min = min(current.Min, value)
+		current.Max = max(current.Max, value)
+		current.Last = value
+		current.Count++
+		sum += value
+		current.Avg = sum / float64(current.Count)
+
+		if metricType == models.Counter {
+			current.Sum += sample.CounterValue
+		}
+	}
+
+	return buckets
+}
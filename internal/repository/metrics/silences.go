@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// GetSilences возвращает все заглушения, включая завершённые.
+func (repo *MetricsRepository) GetSilences(ctx context.Context) ([]models.Silence, error) {
+	silences, err := repo.storage.GetSilences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silences: %v", err)
+	}
+
+	return silences, nil
+}
+
+// CreateSilence проверяет и сохраняет новое заглушение,
+// присваивая ему уникальный идентификатор.
+func (repo *MetricsRepository) CreateSilence(ctx context.Context, silence models.Silence) (models.Silence, error) {
+	err := silence.Validate()
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	silence.ID, err = newSilenceID()
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	err = repo.storage.CreateSilence(ctx, silence)
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("failed to create silence: %v", err)
+	}
+
+	repo.onSilencesUpdate(ctx)
+	return silence, nil
+}
+
+// ExpireSilence завершает заглушение с идентификатором id в момент now.
+func (repo *MetricsRepository) ExpireSilence(ctx context.Context, id string, now time.Time) (models.Silence, error) {
+	silence, err := repo.storage.ExpireSilence(ctx, id, now)
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("failed to expire silence id=%s: %w", id, err)
+	}
+
+	repo.onSilencesUpdate(ctx)
+	return silence, nil
+}
+
+// DeleteExpiredSilences удаляет заглушения, завершившиеся раньше endedBefore.
+//
+// Возвращает количество удалённых заглушений.
+func (repo *MetricsRepository) DeleteExpiredSilences(ctx context.Context, endedBefore time.Time) (int, error) {
+	deleted, err := repo.storage.DeleteExpiredSilences(ctx, endedBefore)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete expired silences: %w", err)
+	}
+
+	if deleted > 0 {
+		repo.onSilencesUpdate(ctx)
+	}
+
+	return deleted, nil
+}
+
+// onSilencesUpdate сохраняет данные хранилища после изменения заглушений.
+// В отличие от метрик, заглушения сохраняются сразу, независимо от SyncMetrics.
+func (repo *MetricsRepository) onSilencesUpdate(ctx context.Context) {
+	err := repo.storage.SaveMetrics(ctx)
+	if err != nil {
+		logger.Errorf("failed to sync silences: %v", err)
+	}
+}
+
+// newSilenceID генерирует случайный идентификатор заглушения.
+func newSilenceID() (string, error) {
+	id := make([]byte, 16)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,491 @@
+// Package storagetest содержит общий набор тестов, которому
+// должна соответствовать любая реализация metrics.MetricsStorage.
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+)
+
+// Opener открывает хранилище. Повторный вызов после сохранения
+// и уничтожения хранилища должен открывать его с сохранёнными значениями.
+type Opener func() (metrics.MetricsStorage, error)
+
+// Factory подготавливает новое пустое хранилище с включённой историей
+// метрик и возвращает функцию для его открытия.
+type Factory func(t *testing.T) Opener
+
+// Run проверяет хранилища, создаваемые factory, на соответствие
+// контракту metrics.MetricsStorage. Каждый тест получает новое хранилище,
+// тесты выполняются последовательно.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, open Opener)
+	}{
+		{name: "перезапись gauge", test: testGaugeOverwrite},
+		{name: "накопление counter", test: testCounterAccumulation},
+		{name: "отсутствующие метрики", test: testNotFound},
+		{name: "фильтрация по меткам", test: testLabelsFilter},
+		{name: "атомарность пакетного обновления", test: testBatchAtomicity},
+		{name: "конкурентные обновления", test: testConcurrentUpdates},
+		{name: "сохранение и восстановление", test: testSaveRestore},
+		{name: "удаление метрик", test: testDeleteMetric},
+		{name: "удаление устаревших метрик", test: testDeleteStaleMetrics},
+		{name: "удаление завершившихся заглушений", test: testDeleteExpiredSilences},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, factory(t))
+		})
+	}
+}
+
+// mustOpen открывает хранилище. Возвращает функцию его уничтожения,
+// которая также вызывается по завершении теста, если не была вызвана ранее.
+func mustOpen(t *testing.T, open Opener) (metrics.MetricsStorage, func()) {
+	t.Helper()
+
+	storage, err := open()
+	require.NoError(t, err)
+
+	var once sync.Once
+	destroy := func() {
+		once.Do(func() {
+			storage.Destroy(context.Background())
+		})
+	}
+	t.Cleanup(destroy)
+
+	return storage, destroy
+}
+
+func testGaugeOverwrite(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	ctx := context.Background()
+
+	value, err := storage.UpdateGaugeMetric(ctx, "Alloc", nil, 1.5)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, value)
+
+	value, err = storage.UpdateGaugeMetric(ctx, "Alloc", nil, -2.25)
+	require.NoError(t, err)
+	require.Equal(t, -2.25, value)
+
+	value, err = storage.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, -2.25, value)
+
+	// В пакетном обновлении побеждает последнее значение.
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 3),
+		models.NewGaugeMetric("Alloc", 4),
+	})
+	require.NoError(t, err)
+
+	value, err = storage.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(4), value)
+}
+
+func testCounterAccumulation(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	ctx := context.Background()
+	hostA := models.Labels{"host": "a"}
+
+	value, err := storage.UpdateCounterMetric(ctx, "PollCount", nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	value, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	// Ряды с разными метками накапливаются независимо.
+	value, err = storage.UpdateCounterMetric(ctx, "PollCount", hostA, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), value)
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 1),
+		models.NewCounterMetric("PollCount", 4),
+	})
+	require.NoError(t, err)
+
+	value, err = storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), value)
+
+	value, err = storage.GetCounterMetric(ctx, "PollCount", hostA)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), value)
+}
+
+func testNotFound(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	ctx := context.Background()
+
+	_, err := storage.UpdateGaugeMetric(ctx, "Alloc", models.Labels{"host": "a"}, 1)
+	require.NoError(t, err)
+
+	_, err = storage.GetGaugeMetric(ctx, "Unknown", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	// Метрика определяется идентификатором, типом и метками.
+	_, err = storage.GetGaugeMetric(ctx, "Alloc", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	_, err = storage.GetCounterMetric(ctx, "Alloc", models.Labels{"host": "a"})
+	require.ErrorIs(t, err, models.ErrNotFound)
+	_, err = storage.GetHistogramMetric(ctx, "Alloc", models.Labels{"host": "a"})
+	require.ErrorIs(t, err, models.ErrNotFound)
+	_, err = storage.GetSummaryMetric(ctx, "Alloc", models.Labels{"host": "a"})
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	_, err = storage.ExpireSilence(ctx, "unknown", time.Now())
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	samples, err := storage.GetMetricHistory(ctx, models.Gauge, "Unknown", nil, time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+func testLabelsFilter(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	ctx := context.Background()
+	hostA := models.Labels{"host": "a", "service": "api"}
+	hostB := models.Labels{"host": "b", "service": "api"}
+
+	err := storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization", 10).WithLabels(hostA),
+		models.NewGaugeMetric("CPUutilization", 20).WithLabels(hostB),
+		models.NewGaugeMetric("CPUutilization", 30),
+		models.NewCounterMetric("PollCount", 1).WithLabels(hostA),
+	})
+	require.NoError(t, err)
+
+	all, err := storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	filtered, err := storage.GetAllMetrics(ctx, models.Labels{"host": "a"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization", 10).WithLabels(hostA),
+		models.NewCounterMetric("PollCount", 1).WithLabels(hostA),
+	}, filtered)
+
+	filtered, err = storage.GetAllMetrics(ctx, models.Labels{"host": "c"})
+	require.NoError(t, err)
+	require.Empty(t, filtered)
+}
+
+func testBatchAtomicity(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	ctx := context.Background()
+
+	histogram, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+	mismatched, err := models.NewHistogramValue([]float64{1}, []int64{1, 0}, 0.5)
+	require.NoError(t, err)
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 1),
+		models.NewCounterMetric("PollCount", 1),
+		models.NewHistogramMetric("Latency", histogram),
+	})
+	require.NoError(t, err)
+
+	// Ошибка в любой метрике пакета отменяет обновление остальных.
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 2),
+		models.NewCounterMetric("PollCount", 10),
+		models.NewGaugeMetric("Created", 1),
+		models.NewHistogramMetric("Latency", mismatched),
+	})
+	require.ErrorIs(t, err, models.ErrHistogramBoundsMismatch)
+
+	gauge, err := storage.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), gauge)
+
+	counter, err := storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), counter)
+
+	_, err = storage.GetGaugeMetric(ctx, "Created", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	value, err := storage.GetHistogramMetric(ctx, "Latency", nil)
+	require.NoError(t, err)
+	require.Equal(t, histogram.Counts, value.Counts)
+
+	require.NoError(t, storage.UpdateMetrics(ctx, nil))
+}
+
+func testConcurrentUpdates(t *testing.T, open Opener) {
+	storage, _ := mustOpen(t, open)
+	const (
+		workers = 5
+		updates = 20
+	)
+
+	var wg sync.WaitGroup
+
+	ctx := context.Background()
+
+	errs := make(chan error, 3*workers*updates)
+
+	for range workers {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			for range updates {
+				_, err := storage.UpdateGaugeMetric(ctx, "Alloc", nil, 5)
+				errs <- err
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for range updates {
+				_, err := storage.UpdateCounterMetric(ctx, "PollCount", nil, 2)
+				errs <- err
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for range updates {
+				errs <- storage.UpdateMetrics(ctx, []models.MetricInfo{
+					models.NewCounterMetric("PollCount", 1),
+					models.NewCounterMetric("BatchCount", 1),
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	gauge, err := storage.GetGaugeMetric(ctx, "Alloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(5), gauge)
+
+	counter, err := storage.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(workers*updates*(2+1)), counter)
+
+	counter, err = storage.GetCounterMetric(ctx, "BatchCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(workers*updates), counter)
+}
+
+func testSaveRestore(t *testing.T, open Opener) {
+	storage, destroy := mustOpen(t, open)
+	ctx := context.Background()
+	labels := models.Labels{"host": "a"}
+
+	histogram, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+
+	summary, err := models.NewSummaryValue(0.01)
+	require.NoError(t, err)
+	for _, v := range []float64{0.5, 1, 2, -3} {
+		require.NoError(t, summary.Add(v))
+	}
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 1.5).WithLabels(labels),
+		models.NewCounterMetric("PollCount", 7),
+		models.NewHistogramMetric("Latency", histogram),
+		models.NewSummaryMetric("Duration", summary).WithLabels(labels),
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	silence := models.Silence{
+		ID:        "silence",
+		RuleName:  "HighCPU",
+		Matchers:  []models.LabelMatcher{{Name: "host", Value: "a|b", Type: models.MatchRegexp}},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		Comment:   "maintenance",
+		CreatedBy: "ops",
+	}
+	require.NoError(t, storage.CreateSilence(ctx, silence))
+	require.Error(t, storage.CreateSilence(ctx, silence))
+
+	saved, err := storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, storage.SaveMetrics(ctx))
+	destroy()
+
+	restored, _ := mustOpen(t, open)
+
+	restoredMetrics, err := restored.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, saved, restoredMetrics)
+
+	silences, err := restored.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	require.Equal(t, silence, normalizeSilence(silences[0]))
+
+	// Восстановленные значения продолжают обновляться.
+	counter, err := restored.UpdateCounterMetric(ctx, "PollCount", nil, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), counter)
+}
+
+func testDeleteMetric(t *testing.T, open Opener) {
+	storage, destroy := mustOpen(t, open)
+	ctx := context.Background()
+	hostA := models.Labels{"host": "a"}
+
+	histogram, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{1, 2, 0}, 0.9)
+	require.NoError(t, err)
+
+	err = storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 1).WithLabels(hostA),
+		models.NewGaugeMetric("Alloc", 2),
+		models.NewCounterMetric("PollCount", 5),
+		models.NewHistogramMetric("Latency", histogram),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, storage.DeleteMetric(ctx, models.Gauge, "Alloc", hostA))
+
+	_, err = storage.GetGaugeMetric(ctx, "Alloc", hostA)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	samples, err := storage.GetMetricHistory(ctx, models.Gauge, "Alloc", hostA, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	// Метрика определяется идентификатором, типом и метками.
+	err = storage.DeleteMetric(ctx, models.Gauge, "Alloc", hostA)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	err = storage.DeleteMetric(ctx, models.Counter, "Alloc", nil)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	require.NoError(t, storage.DeleteMetric(ctx, models.Histogram, "Latency", nil))
+	require.NoError(t, storage.DeleteMetric(ctx, models.Counter, "PollCount", nil))
+
+	// Удалённый counter накапливается заново.
+	counter, err := storage.UpdateCounterMetric(ctx, "PollCount", nil, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), counter)
+
+	require.NoError(t, storage.SaveMetrics(ctx))
+	destroy()
+
+	restored, _ := mustOpen(t, open)
+
+	restoredMetrics, err := restored.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 2),
+		models.NewCounterMetric("PollCount", 3),
+	}, restoredMetrics)
+}
+
+func testDeleteStaleMetrics(t *testing.T, open Opener) {
+	storage, destroy := mustOpen(t, open)
+	ctx := context.Background()
+
+	err := storage.UpdateMetrics(ctx, []models.MetricInfo{
+		models.NewGaugeMetric("Alloc", 1),
+		models.NewCounterMetric("PollCount", 1),
+	})
+	require.NoError(t, err)
+	require.NoError(t, storage.SaveMetrics(ctx))
+
+	time.Sleep(50 * time.Millisecond)
+	updatedBefore := time.Now()
+	time.Sleep(50 * time.Millisecond)
+
+	_, err = storage.UpdateCounterMetric(ctx, "PollCount", nil, 1)
+	require.NoError(t, err)
+	_, err = storage.UpdateGaugeMetric(ctx, "Created", nil, 1)
+	require.NoError(t, err)
+
+	// Время обновления сохраняется: перезапуск не продлевает жизнь метрик.
+	require.NoError(t, storage.SaveMetrics(ctx))
+	destroy()
+
+	storage, _ = mustOpen(t, open)
+
+	deleted, err := storage.DeleteStaleMetrics(ctx, updatedBefore)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	all, err := storage.GetAllMetrics(ctx, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.MetricInfo{
+		models.NewCounterMetric("PollCount", 2),
+		models.NewGaugeMetric("Created", 1),
+	}, all)
+
+	deleted, err = storage.DeleteStaleMetrics(ctx, updatedBefore)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
+
+func testDeleteExpiredSilences(t *testing.T, open Opener) {
+	storage, destroy := mustOpen(t, open)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	matchers := []models.LabelMatcher{{Name: "host", Value: "a", Type: models.MatchEqual}}
+
+	for _, silence := range []models.Silence{
+		{ID: "old", Matchers: matchers, StartsAt: now.Add(-3 * time.Hour), EndsAt: now.Add(-2 * time.Hour)},
+		{ID: "recent", Matchers: matchers, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(-time.Minute)},
+		{ID: "active", Matchers: matchers, StartsAt: now, EndsAt: now.Add(time.Hour)},
+	} {
+		require.NoError(t, storage.CreateSilence(ctx, silence))
+	}
+
+	deleted, err := storage.DeleteExpiredSilences(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	require.NoError(t, storage.SaveMetrics(ctx))
+	destroy()
+
+	restored, _ := mustOpen(t, open)
+
+	silences, err := restored.GetSilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+	require.Equal(t, "recent", silences[0].ID)
+	require.Equal(t, "active", silences[1].ID)
+
+	deleted, err = restored.DeleteExpiredSilences(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
+
+// normalizeSilence приводит время заглушения к UTC, чтобы сравнение
+// не зависело от часового пояса, в котором хранилище возвращает время.
+func normalizeSilence(silence models.Silence) models.Silence {
+	silence.StartsAt = silence.StartsAt.UTC()
+	silence.EndsAt = silence.EndsAt.UTC()
+
+	return silence
+}
@@ -0,0 +1,99 @@
+package writebehind
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// UpdateGaugeMetric добавляет в буфер новое значение метрики типа Gauge.
+func (storage *WriteBehindStorage) UpdateGaugeMetric(_ context.Context, id string, labels models.Labels, value float64) (float64, error) {
+	err := storage.enqueue([]models.MetricInfo{models.NewGaugeMetric(id, value).WithLabels(labels)})
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// UpdateCounterMetric добавляет в буфер приращение метрики типа Counter.
+// Возвращает значение метрики с учётом всех накопленных приращений.
+func (storage *WriteBehindStorage) UpdateCounterMetric(ctx context.Context, id string, labels models.Labels, value int64) (int64, error) {
+	storage.flushMx.RLock()
+	defer storage.flushMx.RUnlock()
+
+	err := storage.enqueue([]models.MetricInfo{models.NewCounterMetric(id, value).WithLabels(labels)})
+	if err != nil {
+		return 0, err
+	}
+
+	return storage.getCounterMetric(ctx, id, labels)
+}
+
+// UpdateHistogramMetric обновляет значение метрики типа Histogram в хранилище.
+func (storage *WriteBehindStorage) UpdateHistogramMetric(ctx context.Context, id string, labels models.Labels, value models.HistogramValue) (models.HistogramValue, error) {
+	return storage.storage.UpdateHistogramMetric(ctx, id, labels, value)
+}
+
+// UpdateSummaryMetric обновляет значение метрики типа Summary в хранилище.
+func (storage *WriteBehindStorage) UpdateSummaryMetric(ctx context.Context, id string, labels models.Labels, value models.SummaryValue) (models.SummaryValue, error) {
+	return storage.storage.UpdateSummaryMetric(ctx, id, labels, value)
+}
+
+// UpdateMetrics добавляет в буфер обновления метрик metrics.
+//
+// Пакет, содержащий гистограммы или скетчи, записывается в хранилище сразу
+// вместе с буфером, чтобы ошибка объединения отменяла обновление всего пакета.
+func (storage *WriteBehindStorage) UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error {
+	for _, metric := range metrics {
+		if metric.Type() == models.Histogram || metric.Type() == models.Summary {
+			return storage.flushWith(ctx, metrics)
+		}
+	}
+
+	return storage.enqueue(metrics)
+}
+
+// DeleteMetric удаляет метрику из буфера и хранилища.
+// Метрика, существовавшая только в буфере, также считается удалённой.
+func (storage *WriteBehindStorage) DeleteMetric(ctx context.Context, metricType models.MetricType, id string, labels models.Labels) error {
+	storage.flushMx.Lock()
+	defer storage.flushMx.Unlock()
+
+	key := newSeriesKey(metricType, id, labels)
+
+	storage.mx.Lock()
+	_, buffered := storage.pending[key]
+	delete(storage.pending, key)
+	storage.mx.Unlock()
+
+	delete(storage.failures, key)
+
+	err := storage.storage.DeleteMetric(ctx, metricType, id, labels)
+	if buffered && errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// DeleteStaleMetrics записывает буфер в хранилище, чтобы время обновления
+// накопленных метрик было актуальным, и удаляет устаревшие метрики хранилища.
+func (storage *WriteBehindStorage) DeleteStaleMetrics(ctx context.Context, updatedBefore time.Time) (int, error) {
+	storage.flushMx.Lock()
+	defer storage.flushMx.Unlock()
+
+	err := storage.flushLocked(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return storage.storage.DeleteStaleMetrics(ctx, updatedBefore)
+}
+
+// PruneHistory удаляет устаревшие значения истории хранилища.
+func (storage *WriteBehindStorage) PruneHistory(ctx context.Context, now time.Time) (int, error) {
+	return storage.storage.PruneHistory(ctx, now)
+}
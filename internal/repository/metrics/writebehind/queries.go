@@ -0,0 +1,115 @@
+package writebehind
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+// GetGaugeMetric возвращает значение метрики типа Gauge из буфера,
+// а при его отсутствии - из хранилища.
+func (storage *WriteBehindStorage) GetGaugeMetric(ctx context.Context, id string, labels models.Labels) (float64, error) {
+	storage.flushMx.RLock()
+	defer storage.flushMx.RUnlock()
+
+	metric, buffered := storage.buffered(models.Gauge, id, labels)
+	if buffered {
+		return metric.GaugeValue(), nil
+	}
+
+	return storage.storage.GetGaugeMetric(ctx, id, labels)
+}
+
+// GetCounterMetric возвращает значение метрики типа Counter из хранилища
+// с учётом накопленных в буфере приращений.
+func (storage *WriteBehindStorage) GetCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error) {
+	storage.flushMx.RLock()
+	defer storage.flushMx.RUnlock()
+
+	return storage.getCounterMetric(ctx, id, labels)
+}
+
+// Вызывающая сторона должна удерживать блокировку flushMx на чтение.
+func (storage *WriteBehindStorage) getCounterMetric(ctx context.Context, id string, labels models.Labels) (int64, error) {
+	value, err := storage.storage.GetCounterMetric(ctx, id, labels)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return 0, err
+	}
+
+	metric, buffered := storage.buffered(models.Counter, id, labels)
+	if !buffered {
+		return value, err
+	}
+
+	return value + metric.CounterValue(), nil
+}
+
+// GetHistogramMetric возвращает значение метрики типа Histogram из хранилища.
+func (storage *WriteBehindStorage) GetHistogramMetric(ctx context.Context, id string, labels models.Labels) (models.HistogramValue, error) {
+	return storage.storage.GetHistogramMetric(ctx, id, labels)
+}
+
+// GetSummaryMetric возвращает значение метрики типа Summary из хранилища.
+func (storage *WriteBehindStorage) GetSummaryMetric(ctx context.Context, id string, labels models.Labels) (models.SummaryValue, error) {
+	return storage.storage.GetSummaryMetric(ctx, id, labels)
+}
+
+// GetAllMetrics возвращает все метрики хранилища, содержащие метки
+// из filter, с учётом значений буфера.
+func (storage *WriteBehindStorage) GetAllMetrics(ctx context.Context, filter models.Labels) ([]models.MetricInfo, error) {
+	storage.flushMx.RLock()
+	defer storage.flushMx.RUnlock()
+
+	stored, err := storage.storage.GetAllMetrics(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	var (
+		allMetrics = make([]models.MetricInfo, 0, len(stored)+len(storage.pending))
+		overlaid   = make(map[seriesKey]struct{})
+	)
+
+	for _, metric := range stored {
+		key := newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
+
+		buffered, exists := storage.pending[key]
+		if exists {
+			metric = coalesce(metric, buffered)
+			overlaid[key] = struct{}{}
+		}
+
+		allMetrics = append(allMetrics, metric)
+	}
+
+	for key, metric := range storage.pending {
+		if _, exists := overlaid[key]; exists || !metric.Labels().Matches(filter) {
+			continue
+		}
+
+		allMetrics = append(allMetrics, metric)
+	}
+
+	return allMetrics, nil
+}
+
+// GetMetricHistory возвращает историю значений метрики из хранилища.
+// Значения, ещё не записанные из буфера, в истории отсутствуют.
+func (storage *WriteBehindStorage) GetMetricHistory(ctx context.Context, metricType models.MetricType, id string, labels models.Labels, from, to time.Time) ([]models.MetricSample, error) {
+	return storage.storage.GetMetricHistory(ctx, metricType, id, labels, from, to)
+}
+
+// buffered возвращает накопленное в буфере обновление ряда.
+func (storage *WriteBehindStorage) buffered(metricType models.MetricType, id string, labels models.Labels) (models.MetricInfo, bool) {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	metric, exists := storage.pending[newSeriesKey(metricType, id, labels)]
+
+	return metric, exists
+}
@@ -0,0 +1,362 @@
+// Package writebehind содержит буферизующую обёртку над хранилищем метрик.
+// Обновления gauge и counter накапливаются в памяти и записываются
+// в хранилище пакетами по достижении порога размера или по таймеру.
+package writebehind
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+)
+
+// WriteBehindStorageOptions параметры буферизующего хранилища.
+type WriteBehindStorageOptions struct {
+	// Storage хранилище, в которое записываются накопленные обновления.
+	Storage metrics.MetricsStorage
+	// MaxSize максимальное количество рядов в буфере. Обновления
+	// новых рядов сверх него отклоняются с ошибкой models.ErrQueueFull.
+	MaxSize int
+	// FlushSize количество рядов в буфере, при котором
+	// запись в хранилище начинается, не дожидаясь таймера.
+	FlushSize int
+	// FlushInterval интервал между записями буфера в хранилище.
+	FlushInterval time.Duration
+	// MaxFlushAttempts количество неудачных записей ряда, после которого
+	// его обновления отбрасываются. По умолчанию DefaultMaxFlushAttempts.
+	MaxFlushAttempts int
+}
+
+// DefaultMaxFlushAttempts количество неудачных записей ряда
+// по умолчанию, после которого его обновления отбрасываются.
+const DefaultMaxFlushAttempts = 5
+
+// NewWriteBehindStorage создаёт новое буферизующее хранилище.
+// Для периодической записи буфера необходимо вызвать Run.
+func NewWriteBehindStorage(opts WriteBehindStorageOptions) *WriteBehindStorage {
+	done := make(chan struct{})
+	close(done)
+
+	maxFlushAttempts := opts.MaxFlushAttempts
+	if maxFlushAttempts <= 0 {
+		maxFlushAttempts = DefaultMaxFlushAttempts
+	}
+
+	return &WriteBehindStorage{
+		storage:          opts.Storage,
+		pending:          make(map[seriesKey]models.MetricInfo),
+		failures:         make(map[seriesKey]int),
+		flushChan:        make(chan struct{}, 1),
+		stopFunc:         func() {},
+		done:             done,
+		maxSize:          opts.MaxSize,
+		flushSize:        opts.FlushSize,
+		flushInterval:    opts.FlushInterval,
+		maxFlushAttempts: maxFlushAttempts,
+	}
+}
+
+// WriteBehindStorage структура, реализующая хранилище метрик
+// с отложенной записью обновлений gauge и counter.
+//
+// Повторные обновления ряда объединяются в буфере: gauge перезаписывается,
+// приращения counter суммируются. Чтения учитывают значения буфера.
+// Гистограммы и скетчи записываются в хранилище сразу.
+//
+// История значений пополняется при записи буфера, поэтому объединённые
+// обновления ряда попадают в неё одним значением.
+//
+// Если хранилище отклоняет пакет, а само остаётся доступным, ряды
+// записываются по одному, чтобы ошибка одного ряда не задерживала остальные.
+// Обновления ряда, который не удалось записать maxFlushAttempts раз подряд,
+// отбрасываются.
+type WriteBehindStorage struct {
+	storage   metrics.MetricsStorage
+	pending   map[seriesKey]models.MetricInfo
+	flushChan chan struct{}
+	stopFunc  context.CancelFunc
+	done      chan struct{}
+	// failures количество неудачных записей рядов подряд.
+	// Защищается блокировкой flushMx на запись.
+	failures map[seriesKey]int
+	// mx защищает буфер.
+	mx sync.Mutex
+	// flushMx удерживается на запись во время записи буфера в хранилище,
+	// чтобы чтения не учли одно и то же обновление дважды.
+	flushMx          sync.RWMutex
+	maxSize          int
+	flushSize        int
+	flushInterval    time.Duration
+	maxFlushAttempts int
+}
+
+// seriesKey ключ ряда в буфере.
+type seriesKey struct {
+	id         string
+	labels     string // каноническое представление меток, см. models.Labels.Key
+	metricType models.MetricType
+}
+
+func newSeriesKey(metricType models.MetricType, id string, labels models.Labels) seriesKey {
+	return seriesKey{
+		id:         id,
+		labels:     labels.Key(),
+		metricType: metricType,
+	}
+}
+
+// Run запускает периодическую запись буфера в хранилище.
+func (storage *WriteBehindStorage) Run() {
+	var ctx context.Context
+	ctx, storage.stopFunc = context.WithCancel(context.TODO())
+	storage.done = make(chan struct{})
+
+	t := time.NewTicker(storage.flushInterval)
+
+	go func() {
+		defer close(storage.done)
+
+		for {
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+				storage.flushAndLog(ctx)
+			case <-storage.flushChan:
+				storage.flushAndLog(ctx)
+			}
+		}
+	}()
+}
+
+// Stop прекращает периодическую запись
+// и записывает оставшиеся в буфере обновления.
+func (storage *WriteBehindStorage) Stop() {
+	storage.stopFunc()
+	<-storage.done
+
+	storage.flushAndLog(context.Background())
+}
+
+func (storage *WriteBehindStorage) flushAndLog(ctx context.Context) {
+	err := storage.Flush(ctx)
+	if err != nil {
+		logger.Errorf("failed to flush buffered metrics: %v", err)
+	}
+}
+
+// Flush записывает накопленные обновления в хранилище.
+// При ошибке обновления возвращаются в буфер.
+func (storage *WriteBehindStorage) Flush(ctx context.Context) error {
+	return storage.flushWith(ctx, nil)
+}
+
+// flushWith записывает накопленные обновления в хранилище одним пакетом
+// вместе с метриками extra. Возвращается ошибка записи extra, ошибки
+// записи накопленных обновлений только логируются.
+func (storage *WriteBehindStorage) flushWith(ctx context.Context, extra []models.MetricInfo) error {
+	storage.flushMx.Lock()
+	defer storage.flushMx.Unlock()
+
+	return storage.flushLocked(ctx, extra)
+}
+
+// Вызывающая сторона должна удерживать блокировку flushMx на запись.
+func (storage *WriteBehindStorage) flushLocked(ctx context.Context, extra []models.MetricInfo) error {
+	storage.mx.Lock()
+	flushing := storage.pending
+	storage.pending = make(map[seriesKey]models.MetricInfo)
+	storage.mx.Unlock()
+
+	if len(flushing) == 0 {
+		if len(extra) == 0 {
+			return nil
+		}
+		return storage.storage.UpdateMetrics(ctx, extra)
+	}
+
+	batch := make([]models.MetricInfo, 0, len(flushing)+len(extra))
+	for _, metric := range flushing {
+		batch = append(batch, metric)
+	}
+	batch = append(batch, extra...)
+
+	err := storage.storage.UpdateMetrics(ctx, batch)
+	if err == nil {
+		for key := range flushing {
+			delete(storage.failures, key)
+		}
+		return nil
+	}
+
+	// Недоступное хранилище не отличить от ошибки в отдельном ряду
+	// без проверки соединения: обновления возвращаются в буфер целиком.
+	if !storage.isAvailable(ctx) {
+		storage.requeue(flushing)
+		return err
+	}
+
+	var extraErr error
+	if len(extra) > 0 {
+		extraErr = storage.storage.UpdateMetrics(ctx, extra)
+	}
+
+	written := extraErr == nil && len(extra) > 0
+	failed := make(map[seriesKey]models.MetricInfo)
+	failedErrs := make([]error, 0)
+	for key, metric := range flushing {
+		writeErr := storage.storage.UpdateMetrics(ctx, []models.MetricInfo{metric})
+		if writeErr != nil {
+			failed[key] = metric
+			failedErrs = append(failedErrs, writeErr)
+			continue
+		}
+
+		written = true
+		delete(storage.failures, key)
+	}
+
+	// Если не записался ни один ряд, ошибка, скорее всего,
+	// не связана с конкретными рядами и попытки не учитываются.
+	if written || len(flushing) == 1 {
+		for key, metric := range failed {
+			storage.failures[key]++
+			if storage.failures[key] < storage.maxFlushAttempts {
+				continue
+			}
+
+			logger.Error(
+				"dropping buffered metric after repeated flush failures",
+				logger.Field{Name: "id", Value: metric.ID()},
+				logger.Field{Name: "type", Value: metric.Type()},
+				logger.Field{Name: "attempts", Value: storage.failures[key]},
+			)
+			delete(storage.failures, key)
+			delete(failed, key)
+		}
+	}
+	storage.requeue(failed)
+
+	flushErr := errors.Join(failedErrs...)
+	if extra != nil {
+		if flushErr != nil {
+			logger.Errorf("failed to flush buffered metrics: %v", flushErr)
+		}
+		return extraErr
+	}
+
+	return flushErr
+}
+
+// isAvailable проверяет соединение с хранилищем, если оно это поддерживает.
+func (storage *WriteBehindStorage) isAvailable(ctx context.Context) bool {
+	checker, ok := storage.storage.(metrics.DatabaseChecker)
+	if !ok {
+		return true
+	}
+
+	return checker.Ping(ctx) == nil
+}
+
+// requeue возвращает незаписанные обновления в буфер.
+func (storage *WriteBehindStorage) requeue(updates map[seriesKey]models.MetricInfo) {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	// Обновления, принятые во время записи, новее возвращаемых.
+	for key, metric := range updates {
+		newer, exists := storage.pending[key]
+		if exists {
+			metric = coalesce(metric, newer)
+		}
+		storage.pending[key] = metric
+	}
+}
+
+// coalesce объединяет обновление ряда older с более новым обновлением newer.
+func coalesce(older, newer models.MetricInfo) models.MetricInfo {
+	if newer.Type() == models.Counter {
+		return models.NewCounterMetric(newer.ID(), older.CounterValue()+newer.CounterValue()).WithLabels(newer.Labels())
+	}
+
+	return newer
+}
+
+// enqueue добавляет обновления gauge и counter в буфер. Если новые ряды
+// не помещаются в буфер, ни одно из обновлений не принимается.
+func (storage *WriteBehindStorage) enqueue(updates []models.MetricInfo) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	keys := make([]seriesKey, len(updates))
+	added := make(map[seriesKey]struct{})
+	for i, metric := range updates {
+		keys[i] = newSeriesKey(metric.Type(), metric.ID(), metric.Labels())
+
+		if _, exists := storage.pending[keys[i]]; !exists {
+			added[keys[i]] = struct{}{}
+		}
+	}
+
+	if len(storage.pending)+len(added) > storage.maxSize {
+		return models.ErrQueueFull
+	}
+
+	for i, metric := range updates {
+		older, exists := storage.pending[keys[i]]
+		if exists {
+			metric = coalesce(older, metric)
+		}
+		storage.pending[keys[i]] = metric
+	}
+
+	if len(storage.pending) >= storage.flushSize {
+		select {
+		case storage.flushChan <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Destroy записывает оставшиеся в буфере обновления
+// и уничтожает хранилище.
+func (storage *WriteBehindStorage) Destroy(ctx context.Context) {
+	storage.flushAndLog(ctx)
+	storage.storage.Destroy(ctx)
+}
+
+// SaveMetrics записывает буфер в хранилище и сохраняет метрики хранилища.
+func (storage *WriteBehindStorage) SaveMetrics(ctx context.Context) error {
+	return errors.Join(storage.Flush(ctx), storage.storage.SaveMetrics(ctx))
+}
+
+// GetSilences возвращает все заглушения хранилища.
+func (storage *WriteBehindStorage) GetSilences(ctx context.Context) ([]models.Silence, error) {
+	return storage.storage.GetSilences(ctx)
+}
+
+// CreateSilence создаёт заглушение в хранилище.
+func (storage *WriteBehindStorage) CreateSilence(ctx context.Context, silence models.Silence) error {
+	return storage.storage.CreateSilence(ctx, silence)
+}
+
+// ExpireSilence завершает заглушение в хранилище.
+func (storage *WriteBehindStorage) ExpireSilence(ctx context.Context, id string, now time.Time) (models.Silence, error) {
+	return storage.storage.ExpireSilence(ctx, id, now)
+}
+
+// DeleteExpiredSilences удаляет завершившиеся заглушения из хранилища.
+func (storage *WriteBehindStorage) DeleteExpiredSilences(ctx context.Context, endedBefore time.Time) (int, error) {
+	return storage.storage.DeleteExpiredSilences(ctx, endedBefore)
+}
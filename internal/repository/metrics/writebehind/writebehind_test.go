@@ -0,0 +1,255 @@
+package writebehind_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/repository/metrics/storagetest"
+	"github.com/xantinium/metrix/internal/repository/metrics/writebehind"
+)
+
+func TestWriteBehindStorage_Conformance(t *testing.T) {
+	logger.Init(true)
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Opener {
+		path := t.TempDir() + "/metrix.db"
+
+		return func() (metrics.MetricsStorage, error) {
+			storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+				Path:             path,
+				Restore:          true,
+				HistoryRetention: time.Hour,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return writebehind.NewWriteBehindStorage(writebehind.WriteBehindStorageOptions{
+				Storage:       storage,
+				MaxSize:       1000,
+				FlushSize:     1000,
+				FlushInterval: time.Hour,
+			}), nil
+		}
+	})
+}
+
+func TestWriteBehindStorage(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	inner := newRecordingStorage(t)
+	storage := writebehind.NewWriteBehindStorage(writebehind.WriteBehindStorageOptions{
+		Storage:       inner,
+		MaxSize:       2,
+		FlushSize:     2,
+		FlushInterval: time.Hour,
+	})
+
+	_, err := inner.UpdateCounterMetric(ctx, "PollCount", nil, 10)
+	require.NoError(t, err)
+
+	t.Run("объединение обновлений", func(t *testing.T) {
+		require.NoError(t, storage.UpdateMetrics(ctx, []models.MetricInfo{
+			models.NewGaugeMetric("Alloc", 1),
+			models.NewCounterMetric("PollCount", 1),
+			models.NewGaugeMetric("Alloc", 2),
+		}))
+
+		counter, err := storage.UpdateCounterMetric(ctx, "PollCount", nil, 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(13), counter)
+
+		require.Zero(t, inner.batches())
+	})
+
+	t.Run("чтение накопленных значений", func(t *testing.T) {
+		gauge, err := storage.GetGaugeMetric(ctx, "Alloc", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(2), gauge)
+
+		allMetrics, err := storage.GetAllMetrics(ctx, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []models.MetricInfo{
+			models.NewGaugeMetric("Alloc", 2),
+			models.NewCounterMetric("PollCount", 13),
+		}, allMetrics)
+	})
+
+	t.Run("переполнение буфера", func(t *testing.T) {
+		_, err := storage.UpdateGaugeMetric(ctx, "Frees", nil, 1)
+		require.ErrorIs(t, err, models.ErrQueueFull)
+
+		// Обновления уже накопленных рядов принимаются.
+		_, err = storage.UpdateGaugeMetric(ctx, "Alloc", nil, 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("ошибка записи", func(t *testing.T) {
+		inner.fail(true)
+		require.Error(t, storage.Flush(ctx))
+
+		_, err := storage.UpdateCounterMetric(ctx, "PollCount", nil, 4)
+		require.NoError(t, err)
+
+		inner.fail(false)
+		require.NoError(t, storage.Flush(ctx))
+
+		gauge, err := inner.GetGaugeMetric(ctx, "Alloc", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(3), gauge)
+
+		counter, err := inner.GetCounterMetric(ctx, "PollCount", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(17), counter)
+	})
+
+	t.Run("запись по порогу размера", func(t *testing.T) {
+		storage.Run()
+		defer storage.Stop()
+
+		batches := inner.batches()
+
+		require.NoError(t, storage.UpdateMetrics(ctx, []models.MetricInfo{
+			models.NewGaugeMetric("Alloc", 4),
+			models.NewGaugeMetric("Frees", 1),
+		}))
+
+		require.Eventually(t, func() bool {
+			return inner.batches() > batches
+		}, time.Second, 10*time.Millisecond)
+
+		gauge, err := inner.GetGaugeMetric(ctx, "Frees", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(1), gauge)
+	})
+}
+
+func TestWriteBehindStorage_FailingSeries(t *testing.T) {
+	logger.Init(true)
+
+	ctx := context.Background()
+	inner := newRecordingStorage(t)
+	storage := writebehind.NewWriteBehindStorage(writebehind.WriteBehindStorageOptions{
+		Storage:          inner,
+		MaxSize:          10,
+		FlushSize:        10,
+		FlushInterval:    time.Hour,
+		MaxFlushAttempts: 2,
+	})
+
+	histogram, err := models.NewHistogramValue([]float64{0.1}, []int64{1, 0}, 0.05)
+	require.NoError(t, err)
+
+	inner.reject("Broken")
+
+	t.Run("ошибка хранилища не учитывается", func(t *testing.T) {
+		require.NoError(t, storage.UpdateMetrics(ctx, []models.MetricInfo{
+			models.NewGaugeMetric("Alloc", 1),
+			models.NewGaugeMetric("Broken", 1),
+		}))
+
+		inner.fail(true)
+		require.Error(t, storage.Flush(ctx))
+		require.Error(t, storage.Flush(ctx))
+		inner.fail(false)
+
+		gauge, err := storage.GetGaugeMetric(ctx, "Broken", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(1), gauge)
+	})
+
+	t.Run("запись остальных рядов", func(t *testing.T) {
+		require.Error(t, storage.Flush(ctx))
+
+		gauge, err := inner.GetGaugeMetric(ctx, "Alloc", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(1), gauge)
+	})
+
+	t.Run("запись гистограммы", func(t *testing.T) {
+		require.NoError(t, storage.UpdateMetrics(ctx, []models.MetricInfo{
+			models.NewHistogramMetric("Latency", histogram),
+		}))
+
+		stored, err := inner.GetHistogramMetric(ctx, "Latency", nil)
+		require.NoError(t, err)
+		require.Equal(t, histogram, stored)
+	})
+
+	t.Run("отбрасывание ряда", func(t *testing.T) {
+		_, err := storage.GetGaugeMetric(ctx, "Broken", nil)
+		require.ErrorIs(t, err, models.ErrNotFound)
+
+		require.NoError(t, storage.Flush(ctx))
+	})
+}
+
+// recordingStorage хранилище, подсчитывающее пакетные обновления
+// и позволяющее имитировать ошибку записи.
+type recordingStorage struct {
+	*memstorage.MemStorage
+	rejected  map[string]struct{}
+	mx        sync.Mutex
+	updates   int
+	isFailing bool
+}
+
+func newRecordingStorage(t *testing.T) *recordingStorage {
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{
+		Path: t.TempDir() + "/metrix.db",
+	})
+	require.NoError(t, err)
+
+	return &recordingStorage{MemStorage: storage, rejected: make(map[string]struct{})}
+}
+
+func (storage *recordingStorage) UpdateMetrics(ctx context.Context, metrics []models.MetricInfo) error {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	if storage.isFailing {
+		return errors.New("storage is unavailable")
+	}
+
+	for _, metric := range metrics {
+		if _, rejected := storage.rejected[metric.ID()]; rejected {
+			return fmt.Errorf("metric id=%s is rejected", metric.ID())
+		}
+	}
+
+	storage.updates++
+
+	return storage.MemStorage.UpdateMetrics(ctx, metrics)
+}
+
+func (storage *recordingStorage) batches() int {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	return storage.updates
+}
+
+func (storage *recordingStorage) fail(isFailing bool) {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	storage.isFailing = isFailing
+}
+
+func (storage *recordingStorage) reject(id string) {
+	storage.mx.Lock()
+	defer storage.mx.Unlock()
+
+	storage.rejected[id] = struct{}{}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/graphite"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+)
+
+// graphiteMaxBatchSize максимальное количество метрик,
+// сохраняемых за один раз.
+const graphiteMaxBatchSize = 1000
+
+// NewGraphiteListener создаёт новый TCP-слушатель для приёма метрик
+// в формате Graphite plaintext.
+func NewGraphiteListener(addr string, metricsRepo *metrics.MetricsRepository) *GraphiteListener {
+	return &GraphiteListener{
+		addr:        addr,
+		metricsRepo: metricsRepo,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// GraphiteListener структура, описывающая TCP-слушатель
+// для приёма метрик в формате Graphite plaintext.
+type GraphiteListener struct {
+	listener    net.Listener
+	metricsRepo *metrics.MetricsRepository
+	conns       map[net.Conn]struct{}
+	addr        string
+	wg          sync.WaitGroup
+	mx          sync.Mutex
+}
+
+// Run начинает прослушивание адреса.
+func (listener *GraphiteListener) Run() error {
+	var err error
+
+	listener.listener, err = net.Listen("tcp", listener.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen graphite address: %v", err)
+	}
+
+	listener.wg.Add(1)
+	go func() {
+		defer listener.wg.Done()
+
+		for {
+			conn, acceptErr := listener.listener.Accept()
+			if acceptErr != nil {
+				if errors.Is(acceptErr, net.ErrClosed) {
+					listener.log("stopping...")
+					return
+				}
+
+				listener.log(fmt.Sprintf("failed to accept connection: %v", acceptErr))
+				continue
+			}
+
+			listener.mx.Lock()
+			listener.conns[conn] = struct{}{}
+			listener.mx.Unlock()
+
+			listener.wg.Add(1)
+			go func() {
+				defer listener.wg.Done()
+				listener.handleConn(conn)
+			}()
+		}
+	}()
+
+	return nil
+}
+
+// Addr возвращает адрес, на котором запущен слушатель.
+func (listener *GraphiteListener) Addr() net.Addr {
+	return listener.listener.Addr()
+}
+
+// Stop прекращает прослушивание адреса и закрывает
+// открытые соединения.
+func (listener *GraphiteListener) Stop() error {
+	if listener.listener == nil {
+		return nil
+	}
+
+	err := listener.listener.Close()
+
+	listener.mx.Lock()
+	for conn := range listener.conns {
+		conn.Close()
+	}
+	listener.mx.Unlock()
+
+	listener.wg.Wait()
+
+	return err
+}
+
+// handleConn читает строки из соединения и сохраняет метрики.
+// Метрики сохраняются батчами: когда прочитаны все данные,
+// пришедшие к этому моменту, или когда батч заполнен.
+func (listener *GraphiteListener) handleConn(conn net.Conn) {
+	defer func() {
+		listener.mx.Lock()
+		delete(listener.conns, conn)
+		listener.mx.Unlock()
+
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	batch := make([]models.MetricInfo, 0)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			metrics, lineErrs := graphite.Parse([]byte(line))
+			for _, lineErr := range lineErrs {
+				listener.log(fmt.Sprintf("failed to parse line %q: %v", line, lineErr.Err))
+			}
+
+			batch = append(batch, metrics...)
+		}
+
+		if err != nil || reader.Buffered() == 0 || len(batch) >= graphiteMaxBatchSize {
+			listener.flush(batch)
+			batch = batch[:0]
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				listener.log(fmt.Sprintf("failed to read connection: %v", err))
+			}
+
+			return
+		}
+	}
+}
+
+func (listener *GraphiteListener) flush(batch []models.MetricInfo) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := listener.metricsRepo.UpdateMetrics(context.Background(), batch)
+	if err != nil {
+		listener.log(fmt.Sprintf("failed to update metrics: %v", err))
+	}
+}
+
+// log логирует события слушателя.
+func (listener *GraphiteListener) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: "graphite-listener",
+		},
+	)
+}
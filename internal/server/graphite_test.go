@@ -0,0 +1,52 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/server"
+)
+
+func TestGraphiteListener(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
+	require.NoError(t, err)
+
+	repo := metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{Storage: storage})
+
+	listener := server.NewGraphiteListener("127.0.0.1:0", repo)
+	require.NoError(t, listener.Run())
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("servers.web01.cpu 12.5 1735678800\ninvalid\nservers.web01.memory 2048\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		memory, getErr := repo.GetGaugeMetric(ctx, "servers.web01.memory", nil)
+		return getErr == nil && memory == 2048
+	}, time.Second, 10*time.Millisecond)
+
+	cpu, err := repo.GetGaugeMetric(ctx, "servers.web01.cpu", nil)
+	require.NoError(t, err)
+	require.Equal(t, 12.5, cpu)
+
+	// Открытые соединения закрываются при остановке слушателя.
+	require.NoError(t, listener.Stop())
+
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	conn.Close()
+}
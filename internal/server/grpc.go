@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	pb "github.com/xantinium/metrix/internal/proto"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+)
+
+// NewGRPCServer создаёт новый gRPC-сервер метрик.
+// Если передан приватный ключ, входящие сообщения
+// проверяются при помощи хеширования через SHA-256.
+func NewGRPCServer(addr string, metricsRepo *metrics.MetricsRepository, privateKey string) *GRPCServer {
+	s := &GRPCServer{
+		addr:        addr,
+		metricsRepo: metricsRepo,
+		privateKey:  privateKey,
+	}
+
+	var opts []grpc.ServerOption
+	if privateKey != "" {
+		opts = append(opts, grpc.UnaryInterceptor(s.hashCheckInterceptor))
+	}
+
+	s.server = grpc.NewServer(opts...)
+	pb.RegisterMetrixServer(s.server, s)
+
+	return s
+}
+
+// GRPCServer структура, описывающая gRPC-сервер метрик.
+type GRPCServer struct {
+	pb.UnimplementedMetrixServer
+
+	server      *grpc.Server
+	listener    net.Listener
+	metricsRepo *metrics.MetricsRepository
+	addr        string
+	privateKey  string
+	wg          sync.WaitGroup
+}
+
+// Run начинает прослушивание адреса.
+func (s *GRPCServer) Run() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen grpc address: %v", err)
+	}
+
+	s.Serve(listener)
+
+	return nil
+}
+
+// Serve начинает обработку запросов, поступающих
+// на переданный слушатель.
+func (s *GRPCServer) Serve(listener net.Listener) {
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := s.server.Serve(listener)
+		if err != nil {
+			s.log(fmt.Sprintf("failed to serve: %v", err))
+			return
+		}
+
+		s.log("stopping...")
+	}()
+}
+
+// Addr возвращает адрес, на котором запущен сервер.
+func (s *GRPCServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop останавливает сервер, дожидаясь
+// завершения обработки текущих запросов.
+func (s *GRPCServer) Stop() error {
+	if s.listener == nil {
+		return nil
+	}
+
+	s.server.GracefulStop()
+	s.wg.Wait()
+
+	return nil
+}
+
+// UpdateMetrics батчевое обновление метрик.
+func (s *GRPCServer) UpdateMetrics(ctx context.Context, req *pb.UpdateMetricsRequest) (*pb.UpdateMetricsResponse, error) {
+	_, err := s.updateMetrics(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateMetricsResponse{}, nil
+}
+
+// GetMetric получение метрики по идентификатору и типу.
+func (s *GRPCServer) GetMetric(ctx context.Context, req *pb.GetMetricRequest) (*pb.GetMetricResponse, error) {
+	metricType, err := pb.ToMetricType(req.GetType())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = models.ValidateMetricID(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var metric models.MetricInfo
+
+	switch metricType {
+	case models.Gauge:
+		var value float64
+		value, err = s.metricsRepo.GetGaugeMetric(ctx, req.GetId(), req.GetLabels())
+		metric = models.NewGaugeMetric(req.GetId(), value).WithLabels(req.GetLabels())
+	case models.Counter:
+		var value int64
+		value, err = s.metricsRepo.GetCounterMetric(ctx, req.GetId(), req.GetLabels())
+		metric = models.NewCounterMetric(req.GetId(), value).WithLabels(req.GetLabels())
+	}
+
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GetMetricResponse{Metric: pb.FromMetricInfo(metric)}, nil
+}
+
+// PushMetrics потоковое обновление метрик.
+// Каждое сообщение потока сохраняется отдельным батчем.
+func (s *GRPCServer) PushMetrics(stream grpc.ClientStreamingServer[pb.PushMetricsRequest, pb.PushMetricsResponse]) error {
+	var accepted int64
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return stream.SendAndClose(&pb.PushMetricsResponse{Accepted: accepted})
+			}
+
+			return err
+		}
+
+		err = s.checkHash(req.GetBatch(), req.GetHash())
+		if err != nil {
+			return err
+		}
+
+		var n int
+		n, err = s.updateMetrics(stream.Context(), req.GetBatch())
+		if err != nil {
+			return err
+		}
+
+		accepted += int64(n)
+	}
+}
+
+// updateMetrics сохраняет метрики из запроса и
+// возвращает их количество.
+func (s *GRPCServer) updateMetrics(ctx context.Context, req *pb.UpdateMetricsRequest) (int, error) {
+	batch, err := pb.ToMetricInfos(req.GetMetrics())
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.metricsRepo.UpdateMetrics(ctx, batch)
+	if err != nil {
+		if errors.Is(err, models.ErrQueueFull) {
+			return 0, status.Error(codes.Unavailable, err.Error())
+		}
+
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+
+	return len(batch), nil
+}
+
+// hashCheckInterceptor интерсептор для проверки унарных запросов
+// при помощи хеширования через SHA-256. Хеш передаётся в метаданных.
+func (s *GRPCServer) hashCheckInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var hash string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(pb.HashMetadataKey); len(values) > 0 {
+			hash = values[0]
+		}
+	}
+
+	msg, ok := req.(protobuf.Message)
+	if !ok {
+		return nil, status.Error(codes.Internal, "unexpected request type")
+	}
+
+	err := s.checkHash(msg, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// checkHash сверяет хеш сообщения с переданным.
+//
+// Как и в HTTP-мидлвари, отсутствие хеша допускается.
+func (s *GRPCServer) checkHash(msg protobuf.Message, hash string) error {
+	if s.privateKey == "" || hash == "" {
+		return nil
+	}
+
+	targetHash, err := pb.CalcHash(msg, s.privateKey)
+	if err != nil {
+		s.log(fmt.Sprintf("failed to check request by hash: %v", err))
+		return status.Error(codes.InvalidArgument, "failed to check request by hash")
+	}
+
+	if hash != targetHash {
+		s.log("hash of request doesn't match to it's content")
+		return status.Error(codes.InvalidArgument, "hash of request doesn't match to it's content")
+	}
+
+	return nil
+}
+
+// log логирует события сервера.
+func (s *GRPCServer) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: "grpc-server",
+		},
+	)
+}
@@ -0,0 +1,192 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	pb "github.com/xantinium/metrix/internal/proto"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/server"
+)
+
+const testPrivateKey = "secret"
+
+func newTestGRPCClient(t *testing.T, privateKey string) (pb.MetrixClient, *metrics.MetricsRepository) {
+	t.Helper()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
+	require.NoError(t, err)
+
+	repo := metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{Storage: storage})
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.NewGRPCServer("", repo, privateKey)
+	grpcServer.Serve(listener)
+	t.Cleanup(func() {
+		grpcServer.Stop()
+	})
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return pb.NewMetrixClient(conn), repo
+}
+
+func withHash(t *testing.T, ctx context.Context, req *pb.UpdateMetricsRequest, key string) context.Context {
+	t.Helper()
+
+	hash, err := pb.CalcHash(req, key)
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, pb.HashMetadataKey, hash)
+}
+
+func TestGRPCServer_UpdateAndGetMetrics(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	ctx := context.Background()
+	client, repo := newTestGRPCClient(t, "")
+
+	_, err := client.UpdateMetrics(ctx, &pb.UpdateMetricsRequest{
+		Metrics: []*pb.Metric{
+			{Id: "Alloc", Type: pb.Metric_GAUGE, Value: 12.5},
+			{Id: "PollCount", Type: pb.Metric_COUNTER, Delta: 2},
+			{Id: "PollCount", Type: pb.Metric_COUNTER, Delta: 3},
+		},
+	})
+	require.NoError(t, err)
+
+	pollCount, err := repo.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), pollCount)
+
+	resp, err := client.GetMetric(ctx, &pb.GetMetricRequest{Id: "Alloc", Type: pb.Metric_GAUGE})
+	require.NoError(t, err)
+	require.Equal(t, "Alloc", resp.GetMetric().GetId())
+	require.Equal(t, pb.Metric_GAUGE, resp.GetMetric().GetType())
+	require.Equal(t, 12.5, resp.GetMetric().GetValue())
+
+	_, err = client.GetMetric(ctx, &pb.GetMetricRequest{Id: "Unknown", Type: pb.Metric_GAUGE})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.UpdateMetrics(ctx, &pb.UpdateMetricsRequest{
+		Metrics: []*pb.Metric{{Id: "Alloc", Type: pb.Metric_UNKNOWN}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_PushMetrics(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	ctx := context.Background()
+	client, repo := newTestGRPCClient(t, testPrivateKey)
+
+	stream, err := client.PushMetrics(ctx)
+	require.NoError(t, err)
+
+	for range 3 {
+		batch := &pb.UpdateMetricsRequest{
+			Metrics: []*pb.Metric{
+				{Id: "PollCount", Type: pb.Metric_COUNTER, Delta: 1},
+				{Id: "RandomValue", Type: pb.Metric_GAUGE, Value: 0.5},
+			},
+		}
+
+		hash, hashErr := pb.CalcHash(batch, testPrivateKey)
+		require.NoError(t, hashErr)
+
+		require.NoError(t, stream.Send(&pb.PushMetricsRequest{Batch: batch, Hash: hash}))
+	}
+
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	require.Equal(t, int64(6), resp.GetAccepted())
+
+	pollCount, err := repo.GetCounterMetric(ctx, "PollCount", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), pollCount)
+
+	stream, err = client.PushMetrics(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.PushMetricsRequest{
+		Batch: &pb.UpdateMetricsRequest{Metrics: []*pb.Metric{{Id: "PollCount", Type: pb.Metric_COUNTER, Delta: 1}}},
+		Hash:  "invalid",
+	}))
+
+	_, err = stream.CloseAndRecv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_HashCheck(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	req := &pb.UpdateMetricsRequest{
+		Metrics: []*pb.Metric{{Id: "Alloc", Type: pb.Metric_GAUGE, Value: 1}},
+	}
+
+	tests := []struct {
+		name string
+		ctx  func(t *testing.T) context.Context
+		want codes.Code
+	}{
+		{
+			name: "Корректный хеш",
+			ctx: func(t *testing.T) context.Context {
+				return withHash(t, context.Background(), req, testPrivateKey)
+			},
+			want: codes.OK,
+		},
+		{
+			name: "Отсутствие хеша",
+			ctx: func(_ *testing.T) context.Context {
+				return context.Background()
+			},
+			want: codes.OK,
+		},
+		{
+			name: "Некорректный хеш",
+			ctx: func(_ *testing.T) context.Context {
+				return metadata.AppendToOutgoingContext(context.Background(), pb.HashMetadataKey, "invalid")
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "Хеш другого сообщения",
+			ctx: func(t *testing.T) context.Context {
+				return withHash(t, context.Background(), &pb.UpdateMetricsRequest{}, testPrivateKey)
+			},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestGRPCClient(t, testPrivateKey)
+
+			_, err := client.UpdateMetrics(tt.ctx(t), req)
+			require.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"html"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/xantinium/metrix/internal/alerting"
 	"github.com/xantinium/metrix/internal/models"
 	"github.com/xantinium/metrix/internal/server/interfaces"
 	"github.com/xantinium/metrix/internal/tools"
@@ -14,24 +19,31 @@ import (
 // GetAllMetricHandler реализация хендлера для получения всех метрик в виде HTML.
 // @Tags Metrics_Legacy
 // @Summary Запрос на получение всех метрик
-// @Description Запрос на получение всех метрик
+// @Description Запрос на получение всех метрик.
+// @Description Параметры запроса используются как фильтр по меткам.
 // @ID getAllMetrics
 // @Produce text/plain
 // @Success 200 {string} string
 // @Failure 500 {string} string "Внутренняя ошибка"
 // @Router / [get]
 func GetAllMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, error) {
-	metrics, err := s.GetMetricsRepo().GetAllMetrics(ctx)
+	metrics, err := s.GetMetricsRepo().GetAllMetrics(ctx, parseLabelsFilter(ctx))
 	if err != nil {
 		return http.StatusInternalServerError, "", err
 	}
 
 	b := strings.Builder{}
 
+	writeActiveAlerts(&b, s.GetAlertsManager().Alerts())
+
 	for _, metric := range metrics {
 		b.WriteString("<p>")
 		b.WriteString("<strong>")
 		b.WriteString(metric.ID())
+		if labels := metric.Labels(); len(labels) > 0 {
+			b.WriteString(" ")
+			b.WriteString(html.EscapeString(formatLabels(labels)))
+		}
 		b.WriteString(": </strong>")
 		b.WriteString("<span>")
 		switch metric.Type() {
@@ -39,6 +51,22 @@ func GetAllMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, er
 			b.WriteString(tools.FloatToStr(metric.GaugeValue()))
 		case models.Counter:
 			b.WriteString(tools.IntToStr(metric.CounterValue()))
+		case models.Histogram:
+			value := metric.HistogramValue()
+			b.WriteString("count=")
+			b.WriteString(tools.IntToStr(value.Count))
+			b.WriteString(", sum=")
+			b.WriteString(tools.FloatToStr(value.Sum))
+		case models.Summary:
+			value := metric.SummaryValue()
+			b.WriteString("count=")
+			b.WriteString(tools.IntToStr(value.Count))
+			for _, q := range defaultSummaryQuantiles {
+				b.WriteString(", q")
+				b.WriteString(tools.FloatToStr(q))
+				b.WriteString("=")
+				b.WriteString(tools.FloatToStr(value.Quantile(q)))
+			}
 		}
 		b.WriteString(" (")
 		b.WriteString(string(metric.Type()))
@@ -47,3 +75,74 @@ func GetAllMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, er
 
 	return http.StatusOK, b.String(), nil
 }
+
+// writeActiveAlerts выводит оповещения в состояниях pending и firing.
+func writeActiveAlerts(b *strings.Builder, alerts []alerting.Alert) {
+	active := slices.DeleteFunc(alerts, func(alert alerting.Alert) bool {
+		return alert.State == alerting.StateResolved
+	})
+	if len(active) == 0 {
+		return
+	}
+
+	b.WriteString("<h3>Alerts</h3>")
+	for _, alert := range active {
+		b.WriteString("<p>")
+		b.WriteString("<strong>[")
+		b.WriteString(string(alert.State))
+		b.WriteString("] ")
+		b.WriteString(html.EscapeString(alert.RuleName))
+		b.WriteString("</strong>")
+		b.WriteString("<span>: ")
+		b.WriteString(html.EscapeString(alert.MetricID))
+		if len(alert.Labels) > 0 {
+			b.WriteString(" ")
+			b.WriteString(html.EscapeString(formatLabels(alert.Labels)))
+		}
+		b.WriteString(" = ")
+		b.WriteString(tools.FloatToStr(alert.Value))
+		b.WriteString(" (since ")
+		b.WriteString(alert.ActiveAt.UTC().Format(time.RFC3339))
+		b.WriteString(")")
+		if len(alert.SilencedBy) > 0 {
+			b.WriteString(" silenced")
+		}
+		b.WriteString("</span></p>")
+	}
+	b.WriteString("<h3>Metrics</h3>")
+}
+
+// parseLabelsFilter формирует фильтр по меткам из параметров запроса.
+// Для каждого параметра используется первое значение.
+func parseLabelsFilter(ctx *gin.Context) models.Labels {
+	query := ctx.Request.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+
+	filter := make(models.Labels, len(query))
+	for name, values := range query {
+		filter[name] = values[0]
+	}
+
+	return filter
+}
+
+// formatLabels форматирует метки в вид {name=value, ...}.
+func formatLabels(labels models.Labels) string {
+	b := strings.Builder{}
+	b.WriteByte('{')
+
+	for i, name := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
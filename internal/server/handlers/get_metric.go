@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -22,6 +24,7 @@ import (
 // @Produce text/plain
 // @Param metric_type path string true "Тип метрики"
 // @Param metric_id path string true "Идентификатор метрики"
+// @Param q query number false "Квантиль (0 <= q <= 1) для метрик типа summary"
 // @Success 200 {string} string
 // @Failure 404 {string} string "Метрика не найдена"
 // @Failure 500 {string} string "Внутренняя ошибка"
@@ -39,6 +42,10 @@ func GetMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, error
 		return getGaugeMetricHandler(ctx, metricsRepo, req.metricID)
 	case models.Counter:
 		return getCounterMetricHandler(ctx, metricsRepo, req.metricID)
+	case models.Histogram:
+		return getHistogramMetricHandler(ctx, metricsRepo, req.metricID)
+	case models.Summary:
+		return getSummaryMetricHandler(ctx, metricsRepo, req.metricID, req.quantile)
 	default:
 		// Попасть сюда невозможно, из-за валидации запроса.
 		return http.StatusInternalServerError, "", fmt.Errorf("unknown metric type")
@@ -46,7 +53,7 @@ func GetMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, error
 }
 
 func getGaugeMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string) (int, string, error) {
-	value, err := repo.GetGaugeMetric(ctx, id)
+	value, err := repo.GetGaugeMetric(ctx, id, nil)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			return http.StatusNotFound, "", err
@@ -59,7 +66,7 @@ func getGaugeMetricHandler(ctx context.Context, repo *metrics.MetricsRepository,
 }
 
 func getCounterMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string) (int, string, error) {
-	value, err := repo.GetCounterMetric(ctx, id)
+	value, err := repo.GetCounterMetric(ctx, id, nil)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			return http.StatusNotFound, "", err
@@ -71,8 +78,98 @@ func getCounterMetricHandler(ctx context.Context, repo *metrics.MetricsRepositor
 	return http.StatusOK, tools.IntToStr(value), nil
 }
 
+func getHistogramMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string) (int, string, error) {
+	value, err := repo.GetHistogramMetric(ctx, id, nil)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return http.StatusNotFound, "", err
+		}
+
+		return http.StatusInternalServerError, "", err
+	}
+
+	return http.StatusOK, formatHistogram(value), nil
+}
+
+// formatHistogram форматирует значение гистограммы в виде строк
+// "le=<граница> <количество>" с накопительным количеством значений,
+// за которыми следуют общее количество и сумма значений.
+func formatHistogram(value models.HistogramValue) string {
+	b := strings.Builder{}
+
+	var cumulative int64
+	for i, count := range value.Counts {
+		cumulative += count
+
+		b.WriteString("le=")
+		if i < len(value.Bounds) {
+			b.WriteString(tools.FloatToStr(value.Bounds[i]))
+		} else {
+			b.WriteString("+Inf")
+		}
+		b.WriteString(" ")
+		b.WriteString(tools.IntToStr(cumulative))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("count ")
+	b.WriteString(tools.IntToStr(value.Count))
+	b.WriteString("\nsum ")
+	b.WriteString(tools.FloatToStr(value.Sum))
+
+	return b.String()
+}
+
+// defaultSummaryQuantiles квантили, выводимые для метрик типа Summary,
+// если конкретный квантиль не запрошен.
+var defaultSummaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+func getSummaryMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string, quantile *float64) (int, string, error) {
+	value, err := repo.GetSummaryMetric(ctx, id, nil)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return http.StatusNotFound, "", err
+		}
+
+		return http.StatusInternalServerError, "", err
+	}
+
+	if quantile != nil {
+		return http.StatusOK, tools.FloatToStr(value.Quantile(*quantile)), nil
+	}
+
+	return http.StatusOK, formatSummary(value), nil
+}
+
+// formatSummary форматирует значение скетча в виде строк
+// "q=<квантиль> <значение>" для квантилей по умолчанию,
+// за которыми следуют количество, сумма, минимум и максимум значений.
+func formatSummary(value models.SummaryValue) string {
+	b := strings.Builder{}
+
+	for _, q := range defaultSummaryQuantiles {
+		b.WriteString("q=")
+		b.WriteString(tools.FloatToStr(q))
+		b.WriteString(" ")
+		b.WriteString(tools.FloatToStr(value.Quantile(q)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("count ")
+	b.WriteString(tools.IntToStr(value.Count))
+	b.WriteString("\nsum ")
+	b.WriteString(tools.FloatToStr(value.Sum))
+	b.WriteString("\nmin ")
+	b.WriteString(tools.FloatToStr(value.Min))
+	b.WriteString("\nmax ")
+	b.WriteString(tools.FloatToStr(value.Max))
+
+	return b.String()
+}
+
 // getMetricRequest структура запроса обновления метрик.
 type getMetricRequest struct {
+	quantile   *float64
 	metricType models.MetricType
 	metricID   string
 }
@@ -100,8 +197,38 @@ func parseGetMetricRequest(r *gin.Context) (getMetricRequest, error) {
 		return getMetricRequest{}, fmt.Errorf("metric id is missing")
 	}
 
+	quantile, err := parseQuantile(r)
+	if err != nil {
+		return getMetricRequest{}, err
+	}
+
+	if quantile != nil && metricType != models.Summary {
+		return getMetricRequest{}, fmt.Errorf("quantile can be requested only for summary metrics")
+	}
+
 	return getMetricRequest{
+		quantile:   quantile,
 		metricType: metricType,
 		metricID:   metricID,
 	}, nil
 }
+
+// parseQuantile парсит запрошенный квантиль из параметра q.
+// Если параметр не передан, возвращается nil.
+func parseQuantile(r *gin.Context) (*float64, error) {
+	maybeQuantile, exists := r.GetQuery("q")
+	if !exists {
+		return nil, nil
+	}
+
+	quantile, err := tools.StrToFloat(maybeQuantile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quantile: %v", err)
+	}
+
+	if math.IsNaN(quantile) || quantile < 0 || quantile > 1 {
+		return nil, fmt.Errorf("quantile must be in range [0, 1]")
+	}
+
+	return &quantile, nil
+}
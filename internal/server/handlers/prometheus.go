@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+	"github.com/xantinium/metrix/internal/tools"
+)
+
+// prometheusContentType тип содержимого текстового формата Prometheus.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusMetricsHandler реализация хендлера для получения всех метрик
+// в текстовом формате Prometheus.
+// @Tags Metrics_Legacy
+// @Summary Запрос на получение всех метрик в формате Prometheus
+// @Description Запрос на получение всех метрик в текстовом формате Prometheus.
+// @Description Параметры запроса используются как фильтр по меткам.
+// @ID getPrometheusMetrics
+// @Produce text/plain
+// @Success 200 {string} string
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /metrics [get]
+func PrometheusMetricsHandler(ctx *gin.Context, s interfaces.Server) (int, string, error) {
+	metrics, err := s.GetMetricsRepo().GetAllMetrics(ctx, parseLabelsFilter(ctx))
+	if err != nil {
+		return http.StatusInternalServerError, "", err
+	}
+
+	ctx.Header(tools.ContentType, prometheusContentType)
+
+	return http.StatusOK, renderPrometheusMetrics(metrics), nil
+}
+
+// renderPrometheusMetrics сериализует метрики в текстовый формат Prometheus.
+// Метрики сортируются по имени и меткам, чтобы вывод был стабильным.
+//
+// Если после приведения к допустимому виду имена нескольких метрик совпали,
+// выводятся только метрики с наименьшим исходным идентификатором и,
+// при совпадении идентификаторов, с наименьшим типом.
+func renderPrometheusMetrics(metrics []models.MetricInfo) string {
+	type sample struct {
+		id         string
+		name       string
+		labels     string
+		value      string
+		metricType models.MetricType
+		metric     models.MetricInfo
+	}
+
+	samples := make([]sample, 0, len(metrics))
+
+	for _, metric := range metrics {
+		item := sample{
+			id:         metric.ID(),
+			name:       toPrometheusName(metric.ID()),
+			labels:     renderPrometheusLabels(metric.Labels()),
+			metricType: metric.Type(),
+			metric:     metric,
+		}
+
+		switch metric.Type() {
+		case models.Gauge:
+			item.value = tools.FloatToStr(metric.GaugeValue())
+		case models.Counter:
+			// Суффикс _total принят в Prometheus для счётчиков
+			// и исключает совпадение имён с метриками типа Gauge.
+			if !strings.HasSuffix(item.name, "_total") {
+				item.name += "_total"
+			}
+			item.value = tools.IntToStr(metric.CounterValue())
+		case models.Histogram, models.Summary:
+			// Значения выводятся отдельными рядами, см. renderPrometheusHistogram
+			// и renderPrometheusSummary.
+		default:
+			continue
+		}
+
+		samples = append(samples, item)
+	}
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.id, b.id); c != 0 {
+			return c
+		}
+		if c := strings.Compare(string(a.metricType), string(b.metricType)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.labels, b.labels)
+	})
+
+	b := strings.Builder{}
+
+	var (
+		familyID   string
+		familyType models.MetricType
+	)
+
+	for i, item := range samples {
+		if i == 0 || samples[i-1].name != item.name {
+			familyID = item.id
+			familyType = item.metricType
+
+			b.WriteString("# TYPE ")
+			b.WriteString(item.name)
+			b.WriteString(" ")
+			b.WriteString(string(item.metricType))
+			b.WriteString("\n")
+		}
+
+		if item.id != familyID || item.metricType != familyType ||
+			(i > 0 && samples[i-1].name == item.name && samples[i-1].labels == item.labels) {
+			continue
+		}
+
+		switch item.metricType {
+		case models.Histogram:
+			renderPrometheusHistogram(&b, item.name, item.metric)
+			continue
+		case models.Summary:
+			renderPrometheusSummary(&b, item.name, item.metric)
+			continue
+		}
+
+		b.WriteString(item.name)
+		b.WriteString(item.labels)
+		b.WriteString(" ")
+		b.WriteString(item.value)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderPrometheusHistogram сериализует гистограмму в ряды
+// name_bucket (с накопительным количеством значений), name_sum и name_count.
+func renderPrometheusHistogram(b *strings.Builder, name string, metric models.MetricInfo) {
+	value := metric.HistogramValue()
+	labels := renderPrometheusLabels(metric.Labels())
+
+	var cumulative int64
+	for i, count := range value.Counts {
+		cumulative += count
+
+		le := "+Inf"
+		if i < len(value.Bounds) {
+			le = tools.FloatToStr(value.Bounds[i])
+		}
+
+		bucketLabels := maps.Clone(metric.Labels())
+		if bucketLabels == nil {
+			bucketLabels = make(models.Labels, 1)
+		}
+		bucketLabels["le"] = le
+
+		b.WriteString(name)
+		b.WriteString("_bucket")
+		b.WriteString(renderPrometheusLabels(bucketLabels))
+		b.WriteString(" ")
+		b.WriteString(tools.IntToStr(cumulative))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(name)
+	b.WriteString("_sum")
+	b.WriteString(labels)
+	b.WriteString(" ")
+	b.WriteString(tools.FloatToStr(value.Sum))
+	b.WriteString("\n")
+
+	b.WriteString(name)
+	b.WriteString("_count")
+	b.WriteString(labels)
+	b.WriteString(" ")
+	b.WriteString(tools.IntToStr(value.Count))
+	b.WriteString("\n")
+}
+
+// renderPrometheusSummary сериализует скетч в ряды name{quantile="..."}
+// для квантилей по умолчанию, name_sum и name_count.
+func renderPrometheusSummary(b *strings.Builder, name string, metric models.MetricInfo) {
+	value := metric.SummaryValue()
+	labels := renderPrometheusLabels(metric.Labels())
+
+	for _, q := range defaultSummaryQuantiles {
+		quantileLabels := maps.Clone(metric.Labels())
+		if quantileLabels == nil {
+			quantileLabels = make(models.Labels, 1)
+		}
+		quantileLabels["quantile"] = tools.FloatToStr(q)
+
+		b.WriteString(name)
+		b.WriteString(renderPrometheusLabels(quantileLabels))
+		b.WriteString(" ")
+		b.WriteString(tools.FloatToStr(value.Quantile(q)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(name)
+	b.WriteString("_sum")
+	b.WriteString(labels)
+	b.WriteString(" ")
+	b.WriteString(tools.FloatToStr(value.Sum))
+	b.WriteString("\n")
+
+	b.WriteString(name)
+	b.WriteString("_count")
+	b.WriteString(labels)
+	b.WriteString(" ")
+	b.WriteString(tools.IntToStr(value.Count))
+	b.WriteString("\n")
+}
+
+// renderPrometheusLabels сериализует метки в вид {name="value",...}.
+// Имена меток сортируются и приводятся к допустимому виду,
+// значения экранируются. Если несколько меток приводятся к одному имени,
+// сохраняется метка с наименьшим исходным именем.
+func renderPrometheusLabels(labels models.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	values := make(map[string]string, len(labels))
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		promName := strings.ReplaceAll(toPrometheusName(name), ":", "_")
+		if _, exists := values[promName]; exists {
+			continue
+		}
+
+		names = append(names, promName)
+		values[promName] = labels[name]
+	}
+	slices.Sort(names)
+
+	b := strings.Builder{}
+	b.WriteByte('{')
+
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(prometheusLabelValueReplacer.Replace(values[name]))
+		b.WriteByte('"')
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// prometheusLabelValueReplacer экранирует значения меток.
+var prometheusLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// toPrometheusName приводит идентификатор метрики к имени,
+// допустимому в Prometheus: [a-zA-Z_:][a-zA-Z0-9_:]*.
+// Недопустимые символы заменяются на подчёркивание.
+func toPrometheusName(id string) string {
+	b := strings.Builder{}
+	b.Grow(len(id) + 1)
+
+	for i, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	if b.Len() == 0 {
+		return "_"
+	}
+
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestToPrometheusName(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "HeapAlloc", want: "HeapAlloc"},
+		{id: "CPUutilization0", want: "CPUutilization0"},
+		{id: "http.requests-count", want: "http_requests_count"},
+		{id: "0day", want: "_0day"},
+		{id: "ns:metric", want: "ns:metric"},
+		{id: "метрика", want: "_______"},
+		{id: "", want: "_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			require.Equal(t, tt.want, toPrometheusName(tt.id))
+		})
+	}
+}
+
+func TestRenderPrometheusMetrics(t *testing.T) {
+	metrics := []models.MetricInfo{
+		models.NewGaugeMetric("HeapAlloc", 12.5),
+		models.NewCounterMetric("PollCount", 7),
+		models.NewGaugeMetric("PollCount", 1),
+		models.NewGaugeMetric("http.latency", 0.25),
+		// После приведения к допустимому виду совпадает с http.latency.
+		models.NewGaugeMetric("http_latency", 0.5),
+	}
+
+	want := "# TYPE HeapAlloc gauge\n" +
+		"HeapAlloc 12.5\n" +
+		"# TYPE PollCount gauge\n" +
+		"PollCount 1\n" +
+		"# TYPE PollCount_total counter\n" +
+		"PollCount_total 7\n" +
+		"# TYPE http_latency gauge\n" +
+		"http_latency 0.25\n"
+
+	require.Equal(t, want, renderPrometheusMetrics(metrics))
+}
+
+func TestRenderPrometheusMetrics_Labels(t *testing.T) {
+	metrics := []models.MetricInfo{
+		models.NewGaugeMetric("CPUutilization", 20).WithLabels(models.Labels{"host": "b"}),
+		models.NewGaugeMetric("CPUutilization", 10).WithLabels(models.Labels{"host": "a", "service": "api"}),
+		models.NewCounterMetric("requests", 5).WithLabels(models.Labels{"path": `/"quoted"\`}),
+		models.NewGaugeMetric("CPUutilization", 30),
+	}
+
+	want := "# TYPE CPUutilization gauge\n" +
+		"CPUutilization 30\n" +
+		"CPUutilization{host=\"a\",service=\"api\"} 10\n" +
+		"CPUutilization{host=\"b\"} 20\n" +
+		"# TYPE requests_total counter\n" +
+		"requests_total{path=\"/\\\"quoted\\\"\\\\\"} 5\n"
+
+	require.Equal(t, want, renderPrometheusMetrics(metrics))
+}
+
+func TestRenderPrometheusLabels_Collision(t *testing.T) {
+	labels := models.Labels{"a.b": "dot", "a_b": "underscore", "a-b": "dash"}
+
+	// Из меток, приводимых к одному имени, всегда выбирается
+	// метка с наименьшим исходным именем.
+	for range 20 {
+		require.Equal(t, `{a_b="dash"}`, renderPrometheusLabels(labels))
+	}
+}
+
+func TestRenderPrometheusMetrics_Histogram(t *testing.T) {
+	value, err := models.NewHistogramValue([]float64{0.1, 0.5}, []int64{2, 1, 1}, 1.25)
+	require.NoError(t, err)
+
+	metrics := []models.MetricInfo{
+		models.NewHistogramMetric("latency", value).WithLabels(models.Labels{"path": "/"}),
+		// Совпадает по имени с гистограммой, но имеет другой тип.
+		models.NewGaugeMetric("latency", 0.3),
+	}
+
+	want := "# TYPE latency gauge\n" +
+		"latency 0.3\n"
+
+	require.Equal(t, want, renderPrometheusMetrics(metrics))
+
+	want = "# TYPE latency histogram\n" +
+		"latency_bucket{le=\"0.1\",path=\"/\"} 2\n" +
+		"latency_bucket{le=\"0.5\",path=\"/\"} 3\n" +
+		"latency_bucket{le=\"+Inf\",path=\"/\"} 4\n" +
+		"latency_sum{path=\"/\"} 1.25\n" +
+		"latency_count{path=\"/\"} 4\n"
+
+	require.Equal(t, want, renderPrometheusMetrics(metrics[:1]))
+}
+
+func TestRenderPrometheusMetrics_Summary(t *testing.T) {
+	value, err := models.NewSummaryValue(models.DefaultSummaryAccuracy)
+	require.NoError(t, err)
+	for _, v := range []float64{1, 1, 1, 1} {
+		require.NoError(t, value.Add(v))
+	}
+
+	metrics := []models.MetricInfo{
+		models.NewSummaryMetric("latency", value).WithLabels(models.Labels{"path": "/"}),
+	}
+
+	want := "# TYPE latency summary\n" +
+		"latency{path=\"/\",quantile=\"0.5\"} 1\n" +
+		"latency{path=\"/\",quantile=\"0.9\"} 1\n" +
+		"latency{path=\"/\",quantile=\"0.99\"} 1\n" +
+		"latency_sum{path=\"/\"} 4\n" +
+		"latency_count{path=\"/\"} 4\n"
+
+	require.Equal(t, want, renderPrometheusMetrics(metrics))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/xantinium/metrix/internal/protocols/remotewrite"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+// RemoteWriteHandler реализация хендлера для приёма метрик
+// по протоколу Prometheus remote-write.
+// @Tags Ingest
+// @Summary Приём метрик по протоколу Prometheus remote-write
+// @Description Приём сжатого при помощи snappy protobuf-объекта WriteRequest.
+// @Description Все значения сохраняются как метрики типа gauge.
+// @ID prometheusRemoteWrite
+// @Accept application/x-protobuf
+// @Produce text/plain
+// @Success 204 {string} string
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 413 {string} string "Слишком большой запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
+// @Router /api/v1/write [post]
+func RemoteWriteHandler(ctx *gin.Context, s interfaces.Server) (int, string, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, remotewrite.MaxDecodedSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return http.StatusRequestEntityTooLarge, "", err
+		}
+		return http.StatusBadRequest, "", err
+	}
+
+	req, err := remotewrite.Decode(body)
+	if err != nil {
+		if errors.Is(err, remotewrite.ErrTooLarge) {
+			return http.StatusRequestEntityTooLarge, "", err
+		}
+		return http.StatusBadRequest, "", err
+	}
+
+	// Ряды без имени отбрасываются, остальные сохраняются.
+	// Prometheus не повторяет запросы, завершившиеся с кодом 4xx.
+	metrics, convErr := remotewrite.ToMetrics(req)
+
+	err = s.GetMetricsRepo().UpdateMetrics(ctx, metrics)
+	if err != nil {
+		return updateErrorStatus(ctx, err), "", err
+	}
+
+	if convErr != nil {
+		return http.StatusBadRequest, "", convErr
+	}
+
+	return http.StatusNoContent, "", nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -23,6 +24,7 @@ import (
 // @Success 200 {string} string
 // @Failure 400 {string} string "Неверный запрос"
 // @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
 // @Router /update/{metric_type}/{metric_id}/{metric_value} [post]
 func UpdateMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, error) {
 	req, err := parseUpdateMetricRequest(ctx)
@@ -34,18 +36,29 @@ func UpdateMetricHandler(ctx *gin.Context, s interfaces.Server) (int, string, er
 
 	switch req.metricType {
 	case models.Gauge:
-		_, err = metricsRepo.UpdateGaugeMetric(ctx, req.metricID, req.metricValue)
+		_, err = metricsRepo.UpdateGaugeMetric(ctx, req.metricID, nil, req.metricValue)
 	case models.Counter:
-		_, err = metricsRepo.UpdateCounterMetric(ctx, req.metricID, int64(req.metricValue))
+		_, err = metricsRepo.UpdateCounterMetric(ctx, req.metricID, nil, int64(req.metricValue))
 	}
 
 	if err != nil {
-		return http.StatusInternalServerError, "", err
+		return updateErrorStatus(ctx, err), "", err
 	}
 
 	return http.StatusOK, "", nil
 }
 
+// updateErrorStatus возвращает HTTP-статус для ошибки обновления метрик.
+// При переполнении очереди клиенту предлагается повторить запрос позже.
+func updateErrorStatus(ctx *gin.Context, err error) int {
+	if errors.Is(err, models.ErrQueueFull) {
+		ctx.Header("Retry-After", "1")
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
 // updateMetricRequest структура запроса обновления метрик.
 type updateMetricRequest struct {
 	metricType  models.MetricType
@@ -72,6 +85,11 @@ func parseUpdateMetricRequest(r *gin.Context) (updateMetricRequest, error) {
 		return updateMetricRequest{}, err
 	}
 
+	// Значения гистограммы и скетча не представимы в виде одного числа.
+	if metricType == models.Histogram || metricType == models.Summary {
+		return updateMetricRequest{}, fmt.Errorf("%s metrics can be updated only via JSON API", metricType)
+	}
+
 	metricID = r.Param("id")
 	if metricID == "" {
 		return updateMetricRequest{}, fmt.Errorf("metric id is missing")
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+)
+
+func TestUpdateErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		want       int
+		retryAfter string
+	}{
+		{
+			name:       "переполнение очереди",
+			err:        fmt.Errorf("failed to update gauge metric: %w", models.ErrQueueFull),
+			want:       http.StatusServiceUnavailable,
+			retryAfter: "1",
+		},
+		{
+			name: "ошибка хранилища",
+			err:  errors.New("connection refused"),
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+
+			require.Equal(t, tt.want, updateErrorStatus(ctx, tt.err))
+			require.Equal(t, tt.retryAfter, recorder.Header().Get("Retry-After"))
+		})
+	}
+}
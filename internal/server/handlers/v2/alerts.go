@@ -0,0 +1,72 @@
+package v2handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+//easyjson:json
+type AlertsResponse struct {
+	Alerts []Alert `json:"alerts"` // отслеживаемые оповещения
+}
+
+// Alert оповещение, созданное правилом для конкретной метрики.
+type Alert struct {
+	Labels     map[string]string `json:"labels,omitempty"`                                    // метки метрики
+	SilencedBy []string          `json:"silencedBy,omitempty"`                                // идентификаторы действующих заглушений
+	FiredAt    *time.Time        `json:"firedAt,omitempty" example:"2025-01-01T00:01:00Z"`    // момент перехода в состояние firing
+	ResolvedAt *time.Time        `json:"resolvedAt,omitempty" example:"2025-01-01T00:05:00Z"` // момент перехода в состояние resolved
+	Rule       string            `json:"rule" example:"HighCPU"`                              // название правила
+	ID         string            `json:"id" example:"CPUutilization1"`                        // идентификатор метрики
+	State      string            `json:"state" example:"firing"`                              // состояние: pending, firing или resolved
+	ActiveAt   time.Time         `json:"activeAt" example:"2025-01-01T00:00:00Z"`             // момент, начиная с которого выполняется условие
+	Value      float64           `json:"value" example:"93.5"`                                // последнее значение метрики
+}
+
+// GetAlertsHandler реализация хендлера для получения оповещений.
+// @Tags Alerts
+// @Summary Получение оповещений
+// @Description Получение оповещений в состояниях pending и firing, а также недавно разрешённых
+// @ID getAlerts
+// @Produce json
+// @Success 200 {object} AlertsResponse
+// @Router /alerts [get]
+func GetAlertsHandler(_ *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	alerts := s.GetAlertsManager().Alerts()
+
+	resp := AlertsResponse{Alerts: make([]Alert, len(alerts))}
+	for i, alert := range alerts {
+		resp.Alerts[i] = newAlert(alert)
+	}
+
+	return http.StatusOK, resp, nil
+}
+
+func newAlert(alert alerting.Alert) Alert {
+	item := Alert{
+		Labels:     alert.Labels,
+		SilencedBy: alert.SilencedBy,
+		Rule:       alert.RuleName,
+		ID:         alert.MetricID,
+		State:      string(alert.State),
+		ActiveAt:   alert.ActiveAt.UTC(),
+		Value:      alert.Value,
+	}
+
+	if !alert.FiredAt.IsZero() {
+		firedAt := alert.FiredAt.UTC()
+		item.FiredAt = &firedAt
+	}
+	if !alert.ResolvedAt.IsZero() {
+		resolvedAt := alert.ResolvedAt.UTC()
+		item.ResolvedAt = &resolvedAt
+	}
+
+	return item
+}
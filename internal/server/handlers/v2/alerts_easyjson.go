@@ -0,0 +1,322 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonBee2c381DecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *AlertsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "alerts":
+			if in.IsNull() {
+				in.Skip()
+				out.Alerts = nil
+			} else {
+				in.Delim('[')
+				if out.Alerts == nil {
+					if !in.IsDelim(']') {
+						out.Alerts = make([]Alert, 0, 0)
+					} else {
+						out.Alerts = []Alert{}
+					}
+				} else {
+					out.Alerts = (out.Alerts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Alert
+					easyjsonBee2c381DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in, &v1)
+					out.Alerts = append(out.Alerts, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBee2c381EncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in AlertsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"alerts\":"
+		out.RawString(prefix[1:])
+		if in.Alerts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Alerts {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonBee2c381EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AlertsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBee2c381EncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AlertsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBee2c381EncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AlertsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBee2c381DecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AlertsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBee2c381DecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjsonBee2c381DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *Alert) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v4 string
+					v4 = string(in.String())
+					(out.Labels)[key] = v4
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "silencedBy":
+			if in.IsNull() {
+				in.Skip()
+				out.SilencedBy = nil
+			} else {
+				in.Delim('[')
+				if out.SilencedBy == nil {
+					if !in.IsDelim(']') {
+						out.SilencedBy = make([]string, 0, 4)
+					} else {
+						out.SilencedBy = []string{}
+					}
+				} else {
+					out.SilencedBy = (out.SilencedBy)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 string
+					v5 = string(in.String())
+					out.SilencedBy = append(out.SilencedBy, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "firedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.FiredAt = nil
+			} else {
+				if out.FiredAt == nil {
+					out.FiredAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FiredAt).UnmarshalJSON(data))
+				}
+			}
+		case "resolvedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.ResolvedAt = nil
+			} else {
+				if out.ResolvedAt == nil {
+					out.ResolvedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ResolvedAt).UnmarshalJSON(data))
+				}
+			}
+		case "rule":
+			out.Rule = string(in.String())
+		case "id":
+			out.ID = string(in.String())
+		case "state":
+			out.State = string(in.String())
+		case "activeAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ActiveAt).UnmarshalJSON(data))
+			}
+		case "value":
+			out.Value = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBee2c381EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in Alert) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v6First := true
+			for v6Name, v6Value := range in.Labels {
+				if v6First {
+					v6First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v6Name))
+				out.RawByte(':')
+				out.String(string(v6Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.SilencedBy) != 0 {
+		const prefix string = ",\"silencedBy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v7, v8 := range in.SilencedBy {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v8))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.FiredAt != nil {
+		const prefix string = ",\"firedAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.FiredAt).MarshalJSON())
+	}
+	if in.ResolvedAt != nil {
+		const prefix string = ",\"resolvedAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ResolvedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"rule\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Rule))
+	}
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"activeAt\":"
+		out.RawString(prefix)
+		out.Raw((in.ActiveAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Value))
+	}
+	out.RawByte('}')
+}
@@ -2,46 +2,147 @@ package v2handlers
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/xantinium/metrix/internal/models"
 )
 
 //easyjson:json
 type Metrics struct {
-	Delta *int64   `json:"delta,omitempty" example:"5"`    // значение метрики в случае передачи counter
-	Value *float64 `json:"value,omitempty" example:"12.6"` // значение метрики в случае передачи gauge
-	ID    string   `json:"id" example:"Alloc"`             // идентификатор метрики
-	MType string   `json:"type" example:"gauge"`           // параметр, принимающий значение gauge или counter
+	Delta     *int64            `json:"delta,omitempty" example:"5"`    // значение метрики в случае передачи counter
+	Value     *float64          `json:"value,omitempty" example:"12.6"` // значение метрики в случае передачи gauge
+	Histogram *Histogram        `json:"histogram,omitempty"`            // значение метрики в случае передачи histogram
+	Summary   *Summary          `json:"summary,omitempty"`              // значение метрики в случае передачи summary
+	Labels    map[string]string `json:"labels,omitempty"`               // метки метрики
+	ID        string            `json:"id" example:"Alloc"`             // идентификатор метрики
+	MType     string            `json:"type" example:"gauge"`           // параметр, принимающий значение gauge, counter, histogram или summary
 }
 
-//easyjson:json
-type MetricsBatch []Metrics
+// Histogram значение метрики типа histogram.
+type Histogram struct {
+	Bounds []float64 `json:"bounds" example:"0.1,0.5,1"`  // верхние границы интервалов (включительно)
+	Counts []int64   `json:"counts" example:"3,5,1,0"`    // количество значений в интервалах, последний интервал до +Inf
+	Count  *int64    `json:"count,omitempty" example:"9"` // общее количество значений (необязательно)
+	Sum    float64   `json:"sum" example:"2.7"`           // сумма значений
+}
 
-// parseType парсит тип метрики.
-func parseType(maybeMetricType string) (models.MetricType, error) {
-	switch maybeMetricType {
-	case string(models.Gauge):
-		return models.Gauge, nil
-	case string(models.Counter):
-		return models.Counter, nil
-	default:
-		return "", fmt.Errorf("unknown metric type: %q", maybeMetricType)
+// newHistogram преобразует значение гистограммы в формат ответа.
+func newHistogram(value models.HistogramValue) *Histogram {
+	return &Histogram{
+		Bounds: value.Bounds,
+		Counts: value.Counts,
+		Count:  &value.Count,
+		Sum:    value.Sum,
 	}
 }
 
-func parseMetric(rawMetric Metrics) (models.MetricInfo, error) {
-	var (
-		err        error
-		metricID   string
-		metricType models.MetricType
-	)
-
-	metricID = rawMetric.ID
-	if metricID == "" {
-		return models.MetricInfo{}, fmt.Errorf("metric id cannot be empty")
+// Summary значение метрики типа summary (скетч DDSketch).
+//
+// Интервал с индексом k покрывает модули значений (gamma^(k-1), gamma^k],
+// где gamma = (1+accuracy)/(1-accuracy).
+type Summary struct {
+	Positive  *SummaryBins `json:"positive,omitempty"`           // интервалы положительных значений
+	Negative  *SummaryBins `json:"negative,omitempty"`           // интервалы модулей отрицательных значений
+	Count     *int64       `json:"count,omitempty" example:"42"` // общее количество значений (необязательно)
+	Quantiles []Quantile   `json:"quantiles,omitempty"`          // запрошенные квантили (только в ответе)
+	Accuracy  float64      `json:"accuracy" example:"0.01"`      // относительная точность скетча
+	ZeroCount int64        `json:"zeroCount" example:"0"`        // количество нулевых значений
+	Sum       float64      `json:"sum" example:"12.5"`           // сумма значений
+	Min       float64      `json:"min" example:"0.05"`           // минимальное значение
+	Max       float64      `json:"max" example:"1.2"`            // максимальное значение
+}
+
+// SummaryBins интервалы скетча для значений одного знака.
+type SummaryBins struct {
+	Offset int     `json:"offset" example:"-150"`  // индекс первого интервала
+	Counts []int64 `json:"counts" example:"1,0,3"` // количество значений в интервалах
+}
+
+// Quantile оценка квантиля метрики типа summary.
+type Quantile struct {
+	Value *float64 `json:"value" example:"1.1"` // значение квантиля, null для пустого скетча
+	Q     float64  `json:"q" example:"0.99"`    // квантиль
+}
+
+// newSummary преобразует значение скетча в формат ответа,
+// добавляя оценки квантилей quantiles.
+func newSummary(value models.SummaryValue, quantiles []float64) *Summary {
+	summary := &Summary{
+		Count:     &value.Count,
+		Accuracy:  value.Accuracy,
+		ZeroCount: value.ZeroCount,
+		Sum:       value.Sum,
+		Min:       value.Min,
+		Max:       value.Max,
+	}
+
+	if len(value.Positive.Counts) > 0 {
+		summary.Positive = &SummaryBins{Offset: value.Positive.Offset, Counts: value.Positive.Counts}
 	}
+	if len(value.Negative.Counts) > 0 {
+		summary.Negative = &SummaryBins{Offset: value.Negative.Offset, Counts: value.Negative.Counts}
+	}
+
+	for _, q := range quantiles {
+		quantile := Quantile{Q: q}
+		if v := value.Quantile(q); !math.IsNaN(v) {
+			quantile.Value = &v
+		}
+
+		summary.Quantiles = append(summary.Quantiles, quantile)
+	}
+
+	return summary
+}
 
-	metricType, err = parseType(rawMetric.MType)
+// toSummaryValue преобразует скетч из запроса и проверяет его корректность.
+// Общее количество значений вычисляется по интервалам.
+func (summary Summary) toSummaryValue() (models.SummaryValue, error) {
+	value := models.SummaryValue{
+		Accuracy:  summary.Accuracy,
+		ZeroCount: summary.ZeroCount,
+		Count:     summary.ZeroCount,
+		Sum:       summary.Sum,
+		Min:       summary.Min,
+		Max:       summary.Max,
+	}
+
+	for _, bins := range []struct {
+		src *SummaryBins
+		dst *models.SummaryBins
+	}{
+		{src: summary.Positive, dst: &value.Positive},
+		{src: summary.Negative, dst: &value.Negative},
+	} {
+		if bins.src == nil {
+			continue
+		}
+
+		*bins.dst = models.SummaryBins{Offset: bins.src.Offset, Counts: bins.src.Counts}
+		for _, count := range bins.src.Counts {
+			value.Count += count
+		}
+	}
+
+	err := value.Validate()
+	if err != nil {
+		return models.SummaryValue{}, err
+	}
+
+	if summary.Count != nil && *summary.Count != value.Count {
+		return models.SummaryValue{}, fmt.Errorf("summary count doesn't match to sum of bin counts")
+	}
+
+	return value, nil
+}
+
+//easyjson:json
+type MetricsBatch []Metrics
+
+func parseMetric(rawMetric Metrics) (models.MetricInfo, error) {
+	metricID := rawMetric.ID
+
+	metricType, err := models.ParseMetricIdentity(metricID, rawMetric.MType)
 	if err != nil {
 		return models.MetricInfo{}, err
 	}
@@ -52,13 +153,39 @@ func parseMetric(rawMetric Metrics) (models.MetricInfo, error) {
 			return models.MetricInfo{}, fmt.Errorf("value is missing")
 		}
 
-		return models.NewGaugeMetric(metricID, *rawMetric.Value), nil
+		return models.NewGaugeMetric(metricID, *rawMetric.Value).WithLabels(rawMetric.Labels), nil
 	case models.Counter:
 		if rawMetric.Delta == nil {
 			return models.MetricInfo{}, fmt.Errorf("value is missing")
 		}
 
-		return models.NewCounterMetric(metricID, *rawMetric.Delta), nil
+		return models.NewCounterMetric(metricID, *rawMetric.Delta).WithLabels(rawMetric.Labels), nil
+	case models.Histogram:
+		if rawMetric.Histogram == nil {
+			return models.MetricInfo{}, fmt.Errorf("value is missing")
+		}
+
+		value, err := models.NewHistogramValue(rawMetric.Histogram.Bounds, rawMetric.Histogram.Counts, rawMetric.Histogram.Sum)
+		if err != nil {
+			return models.MetricInfo{}, err
+		}
+
+		if rawMetric.Histogram.Count != nil && *rawMetric.Histogram.Count != value.Count {
+			return models.MetricInfo{}, fmt.Errorf("histogram count doesn't match to sum of bucket counts")
+		}
+
+		return models.NewHistogramMetric(metricID, value).WithLabels(rawMetric.Labels), nil
+	case models.Summary:
+		if rawMetric.Summary == nil {
+			return models.MetricInfo{}, fmt.Errorf("value is missing")
+		}
+
+		value, err := rawMetric.Summary.toSummaryValue()
+		if err != nil {
+			return models.MetricInfo{}, err
+		}
+
+		return models.NewSummaryMetric(metricID, value).WithLabels(rawMetric.Labels), nil
 	default:
 		return models.MetricInfo{}, fmt.Errorf("unknown metric type: %q", metricType)
 	}
@@ -27,7 +27,7 @@ func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *
 		in.Delim('[')
 		if *out == nil {
 			if !in.IsDelim(']') {
-				*out = make(MetricsBatch, 0, 1)
+				*out = make(MetricsBatch, 0, 0)
 			} else {
 				*out = MetricsBatch{}
 			}
@@ -103,10 +103,6 @@ func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = string(in.String())
-		case "type":
-			out.MType = string(in.String())
 		case "delta":
 			if in.IsNull() {
 				in.Skip()
@@ -127,6 +123,50 @@ func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in
 				}
 				*out.Value = float64(in.Float64())
 			}
+		case "histogram":
+			if in.IsNull() {
+				in.Skip()
+				out.Histogram = nil
+			} else {
+				if out.Histogram == nil {
+					out.Histogram = new(Histogram)
+				}
+				easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in, out.Histogram)
+			}
+		case "summary":
+			if in.IsNull() {
+				in.Skip()
+				out.Summary = nil
+			} else {
+				if out.Summary == nil {
+					out.Summary = new(Summary)
+				}
+				easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV23(in, out.Summary)
+			}
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v4 string
+					v4 = string(in.String())
+					(out.Labels)[key] = v4
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "type":
+			out.MType = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -141,9 +181,74 @@ func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out
 	out.RawByte('{')
 	first := true
 	_ = first
+	if in.Delta != nil {
+		const prefix string = ",\"delta\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int64(int64(*in.Delta))
+	}
+	if in.Value != nil {
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(*in.Value))
+	}
+	if in.Histogram != nil {
+		const prefix string = ",\"histogram\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out, *in.Histogram)
+	}
+	if in.Summary != nil {
+		const prefix string = ",\"summary\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV23(out, *in.Summary)
+	}
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v5First := true
+			for v5Name, v5Value := range in.Labels {
+				if v5First {
+					v5First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v5Name))
+				out.RawByte(':')
+				out.String(string(v5Value))
+			}
+			out.RawByte('}')
+		}
+	}
 	{
 		const prefix string = ",\"id\":"
-		out.RawString(prefix[1:])
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.ID))
 	}
 	{
@@ -151,16 +256,6 @@ func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out
 		out.RawString(prefix)
 		out.String(string(in.MType))
 	}
-	if in.Delta != nil {
-		const prefix string = ",\"delta\":"
-		out.RawString(prefix)
-		out.Int64(int64(*in.Delta))
-	}
-	if in.Value != nil {
-		const prefix string = ",\"value\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.Value))
-	}
 	out.RawByte('}')
 }
 
@@ -187,3 +282,453 @@ func (v *Metrics) UnmarshalJSON(data []byte) error {
 func (v *Metrics) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV21(l, v)
 }
+func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV23(in *jlexer.Lexer, out *Summary) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "positive":
+			if in.IsNull() {
+				in.Skip()
+				out.Positive = nil
+			} else {
+				if out.Positive == nil {
+					out.Positive = new(SummaryBins)
+				}
+				easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV24(in, out.Positive)
+			}
+		case "negative":
+			if in.IsNull() {
+				in.Skip()
+				out.Negative = nil
+			} else {
+				if out.Negative == nil {
+					out.Negative = new(SummaryBins)
+				}
+				easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV24(in, out.Negative)
+			}
+		case "count":
+			if in.IsNull() {
+				in.Skip()
+				out.Count = nil
+			} else {
+				if out.Count == nil {
+					out.Count = new(int64)
+				}
+				*out.Count = int64(in.Int64())
+			}
+		case "quantiles":
+			if in.IsNull() {
+				in.Skip()
+				out.Quantiles = nil
+			} else {
+				in.Delim('[')
+				if out.Quantiles == nil {
+					if !in.IsDelim(']') {
+						out.Quantiles = make([]Quantile, 0, 4)
+					} else {
+						out.Quantiles = []Quantile{}
+					}
+				} else {
+					out.Quantiles = (out.Quantiles)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 Quantile
+					easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV25(in, &v6)
+					out.Quantiles = append(out.Quantiles, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "accuracy":
+			out.Accuracy = float64(in.Float64())
+		case "zeroCount":
+			out.ZeroCount = int64(in.Int64())
+		case "sum":
+			out.Sum = float64(in.Float64())
+		case "min":
+			out.Min = float64(in.Float64())
+		case "max":
+			out.Max = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV23(out *jwriter.Writer, in Summary) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Positive != nil {
+		const prefix string = ",\"positive\":"
+		first = false
+		out.RawString(prefix[1:])
+		easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV24(out, *in.Positive)
+	}
+	if in.Negative != nil {
+		const prefix string = ",\"negative\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV24(out, *in.Negative)
+	}
+	if in.Count != nil {
+		const prefix string = ",\"count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(*in.Count))
+	}
+	if len(in.Quantiles) != 0 {
+		const prefix string = ",\"quantiles\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v7, v8 := range in.Quantiles {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV25(out, v8)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"accuracy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.Accuracy))
+	}
+	{
+		const prefix string = ",\"zeroCount\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ZeroCount))
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Sum))
+	}
+	{
+		const prefix string = ",\"min\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Min))
+	}
+	{
+		const prefix string = ",\"max\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Max))
+	}
+	out.RawByte('}')
+}
+func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV25(in *jlexer.Lexer, out *Quantile) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "value":
+			if in.IsNull() {
+				in.Skip()
+				out.Value = nil
+			} else {
+				if out.Value == nil {
+					out.Value = new(float64)
+				}
+				*out.Value = float64(in.Float64())
+			}
+		case "q":
+			out.Q = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV25(out *jwriter.Writer, in Quantile) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix[1:])
+		if in.Value == nil {
+			out.RawString("null")
+		} else {
+			out.Float64(float64(*in.Value))
+		}
+	}
+	{
+		const prefix string = ",\"q\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Q))
+	}
+	out.RawByte('}')
+}
+func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV24(in *jlexer.Lexer, out *SummaryBins) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "offset":
+			out.Offset = int(in.Int())
+		case "counts":
+			if in.IsNull() {
+				in.Skip()
+				out.Counts = nil
+			} else {
+				in.Delim('[')
+				if out.Counts == nil {
+					if !in.IsDelim(']') {
+						out.Counts = make([]int64, 0, 8)
+					} else {
+						out.Counts = []int64{}
+					}
+				} else {
+					out.Counts = (out.Counts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 int64
+					v9 = int64(in.Int64())
+					out.Counts = append(out.Counts, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV24(out *jwriter.Writer, in SummaryBins) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Offset))
+	}
+	{
+		const prefix string = ",\"counts\":"
+		out.RawString(prefix)
+		if in.Counts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v10, v11 := range in.Counts {
+				if v10 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v11))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjsonC803d3e7DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in *jlexer.Lexer, out *Histogram) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "bounds":
+			if in.IsNull() {
+				in.Skip()
+				out.Bounds = nil
+			} else {
+				in.Delim('[')
+				if out.Bounds == nil {
+					if !in.IsDelim(']') {
+						out.Bounds = make([]float64, 0, 8)
+					} else {
+						out.Bounds = []float64{}
+					}
+				} else {
+					out.Bounds = (out.Bounds)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 float64
+					v12 = float64(in.Float64())
+					out.Bounds = append(out.Bounds, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "counts":
+			if in.IsNull() {
+				in.Skip()
+				out.Counts = nil
+			} else {
+				in.Delim('[')
+				if out.Counts == nil {
+					if !in.IsDelim(']') {
+						out.Counts = make([]int64, 0, 8)
+					} else {
+						out.Counts = []int64{}
+					}
+				} else {
+					out.Counts = (out.Counts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 int64
+					v13 = int64(in.Int64())
+					out.Counts = append(out.Counts, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "count":
+			if in.IsNull() {
+				in.Skip()
+				out.Count = nil
+			} else {
+				if out.Count == nil {
+					out.Count = new(int64)
+				}
+				*out.Count = int64(in.Int64())
+			}
+		case "sum":
+			out.Sum = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC803d3e7EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out *jwriter.Writer, in Histogram) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bounds\":"
+		out.RawString(prefix[1:])
+		if in.Bounds == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Bounds {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				out.Float64(float64(v15))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"counts\":"
+		out.RawString(prefix)
+		if in.Counts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v16, v17 := range in.Counts {
+				if v16 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v17))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Count != nil {
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int64(int64(*in.Count))
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Sum))
+	}
+	out.RawByte('}')
+}
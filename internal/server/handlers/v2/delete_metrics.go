@@ -0,0 +1,166 @@
+package v2handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+//easyjson:json
+type DeleteMetricsRequest struct {
+	Matchers []Matcher `json:"matchers"`                       // условия на метки метрики
+	MType    string    `json:"type,omitempty" example:"gauge"` // тип удаляемых метрик (по умолчанию любой)
+}
+
+//easyjson:json
+type DeleteMetricsResponse struct {
+	Deleted int `json:"deleted" example:"3"` // количество удалённых метрик
+}
+
+// DeleteMetricHandler реализация хендлера для удаления метрики.
+// @Tags Metrics
+// @Summary Удаление метрики
+// @Description Удаление метрики по ID, типу и меткам вместе с историей значений
+// @ID deleteMetric
+// @Accept  json
+// @Produce json
+// @Param payload body Metrics true "Тело запроса (значения метрики не учитываются)"
+// @Success 200 {object} DeleteMetricsResponse
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 404 {string} string "Метрика не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /value [delete]
+func DeleteMetricHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	req, err := ParseDeleteMetricRequest(ctx)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	err = s.GetMetricsRepo().DeleteMetric(ctx, req.MetricType, req.MetricID, req.Labels)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return http.StatusNotFound, nil, err
+		}
+
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, DeleteMetricsResponse{Deleted: 1}, nil
+}
+
+// DeleteMetricsHandler реализация хендлера для удаления метрик по условиям.
+// @Tags Metrics
+// @Summary Удаление метрик по условиям
+// @Description Удаление всех метрик, метки которых удовлетворяют условиям, вместе с историей значений.
+// @Description Идентификатор метрики доступен в условиях как метка __name__
+// @ID deleteMetrics
+// @Accept  json
+// @Produce json
+// @Param payload body DeleteMetricsRequest true "Тело запроса"
+// @Success 200 {object} DeleteMetricsResponse
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /values [delete]
+func DeleteMetricsHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	metricType, matchers, err := ParseDeleteMetricsRequest(ctx)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	deleted, err := s.GetMetricsRepo().DeleteMetrics(ctx, metricType, matchers)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, DeleteMetricsResponse{Deleted: deleted}, nil
+}
+
+// ParseDeleteMetricRequest парсит запрос на удаление метрики.
+func ParseDeleteMetricRequest(ctx *gin.Context) (GetMetricsRequest, error) {
+	var (
+		err       error
+		bodyBytes []byte
+		rawReq    Metrics
+		req       GetMetricsRequest
+	)
+
+	bodyBytes, err = io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return GetMetricsRequest{}, err
+	}
+
+	err = easyjson.Unmarshal(bodyBytes, &rawReq)
+	if err != nil {
+		return GetMetricsRequest{}, err
+	}
+
+	req.MetricID = rawReq.ID
+	req.Labels = rawReq.Labels
+	req.MetricType, err = models.ParseMetricIdentity(rawReq.ID, rawReq.MType)
+	if err != nil {
+		return GetMetricsRequest{}, err
+	}
+
+	return req, nil
+}
+
+// ParseDeleteMetricsRequest парсит запрос на удаление метрик по условиям.
+// Возвращает тип удаляемых метрик (пустой, если тип не указан) и условия.
+func ParseDeleteMetricsRequest(ctx *gin.Context) (models.MetricType, []models.LabelMatcher, error) {
+	var (
+		err        error
+		bodyBytes  []byte
+		rawReq     DeleteMetricsRequest
+		metricType models.MetricType
+	)
+
+	bodyBytes, err = io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = easyjson.Unmarshal(bodyBytes, &rawReq)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if rawReq.MType != "" {
+		metricType, err = models.ParseStringAsMetricType(rawReq.MType)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	// Без условий запрос удалил бы все метрики.
+	if len(rawReq.Matchers) == 0 {
+		return "", nil, fmt.Errorf("at least one matcher is required")
+	}
+
+	matchers := make([]models.LabelMatcher, len(rawReq.Matchers))
+	for i, matcher := range rawReq.Matchers {
+		matchType := models.MatchType(matcher.Type)
+		if matchType == "" {
+			matchType = models.MatchEqual
+		}
+
+		matchers[i] = models.LabelMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  matchType,
+		}
+
+		err = matchers[i].Validate()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return metricType, matchers, nil
+}
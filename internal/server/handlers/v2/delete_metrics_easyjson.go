@@ -0,0 +1,247 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *DeleteMetricsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "deleted":
+			out.Deleted = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in DeleteMetricsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"deleted\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Deleted))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeleteMetricsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeleteMetricsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeleteMetricsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeleteMetricsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *DeleteMetricsRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "matchers":
+			if in.IsNull() {
+				in.Skip()
+				out.Matchers = nil
+			} else {
+				in.Delim('[')
+				if out.Matchers == nil {
+					if !in.IsDelim(']') {
+						out.Matchers = make([]Matcher, 0, 1)
+					} else {
+						out.Matchers = []Matcher{}
+					}
+				} else {
+					out.Matchers = (out.Matchers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Matcher
+					easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV22(in, &v1)
+					out.Matchers = append(out.Matchers, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "type":
+			out.MType = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in DeleteMetricsRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"matchers\":"
+		out.RawString(prefix[1:])
+		if in.Matchers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Matchers {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV22(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.MType != "" {
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.MType))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeleteMetricsRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeleteMetricsRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeleteMetricsRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeleteMetricsRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV21(l, v)
+}
+func easyjson6456595bDecodeGithubComXantiniumMetrixInternalServerHandlersV22(in *jlexer.Lexer, out *Matcher) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6456595bEncodeGithubComXantiniumMetrixInternalServerHandlersV22(out *jwriter.Writer, in Matcher) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,81 @@
+package v2handlers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	v2handlers "github.com/xantinium/metrix/internal/server/handlers/v2"
+)
+
+func TestParseDeleteMetricsRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		reqBody      string
+		wantType     models.MetricType
+		wantMatchers []models.LabelMatcher
+		wantErr      bool
+	}{
+		{
+			name:     "Удаление метрик по условиям",
+			reqBody:  `{"matchers":[{"name":"__name__","value":"CPUutilization.*","type":"=~"},{"name":"host","value":"a"}]}`,
+			wantType: "",
+			wantMatchers: []models.LabelMatcher{
+				{Name: "__name__", Value: "CPUutilization.*", Type: models.MatchRegexp},
+				{Name: "host", Value: "a", Type: models.MatchEqual},
+			},
+		},
+		{
+			name:     "Удаление метрик заданного типа",
+			reqBody:  `{"type":"counter","matchers":[{"name":"host","value":"a","type":"!="}]}`,
+			wantType: models.Counter,
+			wantMatchers: []models.LabelMatcher{
+				{Name: "host", Value: "a", Type: models.MatchNotEqual},
+			},
+		},
+		{
+			name:    "Нет условий",
+			reqBody: `{"type":"gauge"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Неизвестный тип метрики",
+			reqBody: `{"type":"unknown","matchers":[{"name":"host","value":"a"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Некорректное регулярное выражение",
+			reqBody: `{"matchers":[{"name":"host","value":"(","type":"=~"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Пустое название метки",
+			reqBody: `{"matchers":[{"name":"","value":"a"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &gin.Context{
+				Request: &http.Request{
+					Body: io.NopCloser(bytes.NewBuffer([]byte(tt.reqBody))),
+				},
+			}
+
+			gotType, gotMatchers, err := v2handlers.ParseDeleteMetricsRequest(ctx)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, gotType)
+			require.Equal(t, tt.wantMatchers, gotMatchers)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +14,7 @@ import (
 	"github.com/xantinium/metrix/internal/models"
 	"github.com/xantinium/metrix/internal/repository/metrics"
 	"github.com/xantinium/metrix/internal/server/interfaces"
+	"github.com/xantinium/metrix/internal/tools"
 )
 
 // GetMetricHandler реализация хендлера для получения метрик.
@@ -23,6 +25,7 @@ import (
 // @Accept  json
 // @Produce json
 // @Param payload body GetMetricsRequest true "Тело запроса"
+// @Param q query []number false "Квантили (0 <= q <= 1) для метрик типа summary" collectionFormat(multi)
 // @Success 200 {object} Metrics
 // @Failure 400 {string} string "Неверный запрос"
 // @Failure 404 {string} string "Метрика не найдена"
@@ -38,17 +41,21 @@ func GetMetricHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Mars
 
 	switch req.MetricType {
 	case models.Gauge:
-		return getGaugeMetricHandler(ctx, metricsRepo, req.MetricID)
+		return getGaugeMetricHandler(ctx, metricsRepo, req.MetricID, req.Labels)
 	case models.Counter:
-		return getCounterMetricHandler(ctx, metricsRepo, req.MetricID)
+		return getCounterMetricHandler(ctx, metricsRepo, req.MetricID, req.Labels)
+	case models.Histogram:
+		return getHistogramMetricHandler(ctx, metricsRepo, req.MetricID, req.Labels)
+	case models.Summary:
+		return getSummaryMetricHandler(ctx, metricsRepo, req.MetricID, req.Labels, req.Quantiles)
 	default:
 		// Попасть сюда невозможно, из-за валидации запроса.
 		return http.StatusInternalServerError, nil, fmt.Errorf("unknown metric type")
 	}
 }
 
-func getGaugeMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string) (int, easyjson.Marshaler, error) {
-	value, err := repo.GetGaugeMetric(ctx, id)
+func getGaugeMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string, labels models.Labels) (int, easyjson.Marshaler, error) {
+	value, err := repo.GetGaugeMetric(ctx, id, labels)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			return http.StatusNotFound, nil, err
@@ -58,14 +65,15 @@ func getGaugeMetricHandler(ctx context.Context, repo *metrics.MetricsRepository,
 	}
 
 	return http.StatusOK, Metrics{
-		ID:    id,
-		MType: string(models.Gauge),
-		Value: &value,
+		ID:     id,
+		MType:  string(models.Gauge),
+		Labels: labels,
+		Value:  &value,
 	}, nil
 }
 
-func getCounterMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string) (int, easyjson.Marshaler, error) {
-	value, err := repo.GetCounterMetric(ctx, id)
+func getCounterMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string, labels models.Labels) (int, easyjson.Marshaler, error) {
+	value, err := repo.GetCounterMetric(ctx, id, labels)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			return http.StatusNotFound, nil, err
@@ -75,9 +83,46 @@ func getCounterMetricHandler(ctx context.Context, repo *metrics.MetricsRepositor
 	}
 
 	return http.StatusOK, Metrics{
-		ID:    id,
-		MType: string(models.Counter),
-		Delta: &value,
+		ID:     id,
+		MType:  string(models.Counter),
+		Labels: labels,
+		Delta:  &value,
+	}, nil
+}
+
+func getHistogramMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string, labels models.Labels) (int, easyjson.Marshaler, error) {
+	value, err := repo.GetHistogramMetric(ctx, id, labels)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return http.StatusNotFound, nil, err
+		}
+
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, Metrics{
+		ID:        id,
+		MType:     string(models.Histogram),
+		Labels:    labels,
+		Histogram: newHistogram(value),
+	}, nil
+}
+
+func getSummaryMetricHandler(ctx context.Context, repo *metrics.MetricsRepository, id string, labels models.Labels, quantiles []float64) (int, easyjson.Marshaler, error) {
+	value, err := repo.GetSummaryMetric(ctx, id, labels)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return http.StatusNotFound, nil, err
+		}
+
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, Metrics{
+		ID:      id,
+		MType:   string(models.Summary),
+		Labels:  labels,
+		Summary: newSummary(value, quantiles),
 	}, nil
 }
 
@@ -87,6 +132,10 @@ type GetMetricsRequest struct {
 	MetricID string `example:"Alloc"`
 	// Тип метрики
 	MetricType models.MetricType `example:"gauge"`
+	// Метки метрики
+	Labels models.Labels
+	// Запрошенные квантили (только для метрик типа summary)
+	Quantiles []float64
 }
 
 // ParseGetMetricRequest парсит запрос на получение метрики.
@@ -109,14 +158,43 @@ func ParseGetMetricRequest(ctx *gin.Context) (GetMetricsRequest, error) {
 	}
 
 	req.MetricID = rawReq.ID
-	if req.MetricID == "" {
-		return GetMetricsRequest{}, fmt.Errorf("metric id cannot be empty")
+	req.Labels = rawReq.Labels
+	req.MetricType, err = models.ParseMetricIdentity(rawReq.ID, rawReq.MType)
+	if err != nil {
+		return GetMetricsRequest{}, err
 	}
 
-	req.MetricType, err = parseType(rawReq.MType)
+	req.Quantiles, err = parseQuantiles(ctx.QueryArray("q"))
 	if err != nil {
 		return GetMetricsRequest{}, err
 	}
 
+	if len(req.Quantiles) > 0 && req.MetricType != models.Summary {
+		return GetMetricsRequest{}, fmt.Errorf("quantiles can be requested only for summary metrics")
+	}
+
 	return req, nil
 }
+
+// parseQuantiles парсит запрошенные квантили.
+func parseQuantiles(rawQuantiles []string) ([]float64, error) {
+	if len(rawQuantiles) == 0 {
+		return nil, nil
+	}
+
+	quantiles := make([]float64, len(rawQuantiles))
+	for i, rawQuantile := range rawQuantiles {
+		quantile, err := tools.StrToFloat(rawQuantile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantile: %v", err)
+		}
+
+		if math.IsNaN(quantile) || quantile < 0 || quantile > 1 {
+			return nil, fmt.Errorf("quantile must be in range [0, 1]")
+		}
+
+		quantiles[i] = quantile
+	}
+
+	return quantiles, nil
+}
@@ -0,0 +1,166 @@
+package v2handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+// maxRangeStep максимальная длина интервала агрегации (сек),
+// представимая в time.Duration.
+const maxRangeStep = int64(math.MaxInt64 / int64(time.Second))
+
+//easyjson:json
+type MetricsRange struct {
+	Labels map[string]string `json:"labels,omitempty"`          // метки метрики
+	ID     string            `json:"id" example:"HeapAlloc"`    // идентификатор метрики
+	MType  string            `json:"type" example:"gauge"`      // параметр, принимающий значение gauge или counter
+	From   int64             `json:"from" example:"1735678800"` // начало промежутка (unix-время в секундах)
+	To     int64             `json:"to" example:"1735682400"`   // конец промежутка (unix-время в секундах)
+	Step   int64             `json:"step" example:"60"`         // длина интервала агрегации (сек)
+}
+
+//easyjson:json
+type MetricsRangeResponse struct {
+	Labels map[string]string   `json:"labels,omitempty"`       // метки метрики
+	ID     string              `json:"id" example:"HeapAlloc"` // идентификатор метрики
+	MType  string              `json:"type" example:"gauge"`   // параметр, принимающий значение gauge или counter
+	Points []MetricsRangePoint `json:"points"`                 // агрегированные значения
+}
+
+// MetricsRangePoint агрегированные значения метрики на интервале.
+type MetricsRangePoint struct {
+	Sum       *int64  `json:"sum,omitempty" example:"12"` // сумма приращений в случае передачи counter
+	Timestamp int64   `json:"ts" example:"1735678800"`    // начало интервала (unix-время в секундах)
+	Min       float64 `json:"min" example:"1.5"`          // минимальное значение
+	Max       float64 `json:"max" example:"7.5"`          // максимальное значение
+	Avg       float64 `json:"avg" example:"4.2"`          // среднее значение
+	Last      float64 `json:"last" example:"6"`           // последнее значение
+	Count     int     `json:"count" example:"30"`         // количество значений
+}
+
+// GetMetricRangeHandler реализация хендлера для получения
+// истории значений метрики, агрегированной по интервалам.
+// @Tags Metrics
+// @Summary Получение истории метрики
+// @Description Получение значений метрики за промежуток времени, агрегированных по интервалам длиной step
+// @Description История хранится, только если задано время её хранения (флаг -history)
+// @ID getMetricRange
+// @Accept  json
+// @Produce json
+// @Param payload body MetricsRange true "Тело запроса"
+// @Success 200 {object} MetricsRangeResponse
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /range [post]
+func GetMetricRangeHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	req, err := ParseGetMetricRangeRequest(ctx)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	buckets, err := s.GetMetricsRepo().GetMetricRange(ctx, req.MetricType, req.MetricID, req.Labels, req.From, req.To, req.Step)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRange) || errors.Is(err, models.ErrHistoryDisabled) {
+			return http.StatusBadRequest, nil, err
+		}
+		return http.StatusInternalServerError, nil, err
+	}
+
+	resp := MetricsRangeResponse{
+		Labels: req.Labels,
+		ID:     req.MetricID,
+		MType:  string(req.MetricType),
+		Points: make([]MetricsRangePoint, len(buckets)),
+	}
+
+	for i, bucket := range buckets {
+		point := MetricsRangePoint{
+			Timestamp: bucket.Start.Unix(),
+			Min:       bucket.Min,
+			Max:       bucket.Max,
+			Avg:       bucket.Avg,
+			Last:      bucket.Last,
+			Count:     bucket.Count,
+		}
+		if req.MetricType == models.Counter {
+			point.Sum = &bucket.Sum
+		}
+
+		resp.Points[i] = point
+	}
+
+	return http.StatusOK, resp, nil
+}
+
+// GetMetricRangeRequest запрос на получение истории метрики.
+type GetMetricRangeRequest struct {
+	Labels     models.Labels
+	From       time.Time
+	To         time.Time
+	MetricID   string
+	MetricType models.MetricType
+	Step       time.Duration
+}
+
+// ParseGetMetricRangeRequest парсит запрос на получение истории метрики.
+func ParseGetMetricRangeRequest(ctx *gin.Context) (GetMetricRangeRequest, error) {
+	var (
+		err       error
+		bodyBytes []byte
+		rawReq    MetricsRange
+		req       GetMetricRangeRequest
+	)
+
+	bodyBytes, err = io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return GetMetricRangeRequest{}, err
+	}
+
+	err = easyjson.Unmarshal(bodyBytes, &rawReq)
+	if err != nil {
+		return GetMetricRangeRequest{}, err
+	}
+
+	req.MetricID = rawReq.ID
+	req.Labels = rawReq.Labels
+	req.MetricType, err = models.ParseMetricIdentity(rawReq.ID, rawReq.MType)
+	if err != nil {
+		return GetMetricRangeRequest{}, err
+	}
+
+	if req.MetricType == models.Histogram || req.MetricType == models.Summary {
+		return GetMetricRangeRequest{}, fmt.Errorf("history isn't kept for %s metrics", req.MetricType)
+	}
+
+	if rawReq.Step <= 0 {
+		return GetMetricRangeRequest{}, fmt.Errorf("step must be positive")
+	}
+	if rawReq.Step > maxRangeStep {
+		return GetMetricRangeRequest{}, fmt.Errorf("step cannot exceed %d seconds", maxRangeStep)
+	}
+	if rawReq.To < rawReq.From {
+		return GetMetricRangeRequest{}, fmt.Errorf("end of range cannot be before its start")
+	}
+	// Разность вычисляется без знака, чтобы не переполниться
+	// на промежутках длиннее math.MaxInt64 секунд.
+	if uint64(rawReq.To-rawReq.From)/uint64(rawReq.Step) >= metrics.MaxRangeBuckets {
+		return GetMetricRangeRequest{}, fmt.Errorf("too many buckets requested, increase step")
+	}
+
+	req.From = time.Unix(rawReq.From, 0)
+	req.To = time.Unix(rawReq.To, 0)
+	req.Step = time.Duration(rawReq.Step) * time.Second
+
+	return req, nil
+}
@@ -0,0 +1,414 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *MetricsRangeResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 string
+					v1 = string(in.String())
+					(out.Labels)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "type":
+			out.MType = string(in.String())
+		case "points":
+			if in.IsNull() {
+				in.Skip()
+				out.Points = nil
+			} else {
+				in.Delim('[')
+				if out.Points == nil {
+					if !in.IsDelim(']') {
+						out.Points = make([]MetricsRangePoint, 0, 1)
+					} else {
+						out.Points = []MetricsRangePoint{}
+					}
+				} else {
+					out.Points = (out.Points)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 MetricsRangePoint
+					easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in, &v2)
+					out.Points = append(out.Points, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in MetricsRangeResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v3First := true
+			for v3Name, v3Value := range in.Labels {
+				if v3First {
+					v3First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v3Name))
+				out.RawByte(':')
+				out.String(string(v3Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.MType))
+	}
+	{
+		const prefix string = ",\"points\":"
+		out.RawString(prefix)
+		if in.Points == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v4, v5 := range in.Points {
+				if v4 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out, v5)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MetricsRangeResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MetricsRangeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MetricsRangeResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MetricsRangeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *MetricsRangePoint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "sum":
+			if in.IsNull() {
+				in.Skip()
+				out.Sum = nil
+			} else {
+				if out.Sum == nil {
+					out.Sum = new(int64)
+				}
+				*out.Sum = int64(in.Int64())
+			}
+		case "ts":
+			out.Timestamp = int64(in.Int64())
+		case "min":
+			out.Min = float64(in.Float64())
+		case "max":
+			out.Max = float64(in.Float64())
+		case "avg":
+			out.Avg = float64(in.Float64())
+		case "last":
+			out.Last = float64(in.Float64())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in MetricsRangePoint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Sum != nil {
+		const prefix string = ",\"sum\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int64(int64(*in.Sum))
+	}
+	{
+		const prefix string = ",\"ts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.Timestamp))
+	}
+	{
+		const prefix string = ",\"min\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Min))
+	}
+	{
+		const prefix string = ",\"max\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Max))
+	}
+	{
+		const prefix string = ",\"avg\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Avg))
+	}
+	{
+		const prefix string = ",\"last\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Last))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+func easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in *jlexer.Lexer, out *MetricsRange) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v6 string
+					v6 = string(in.String())
+					(out.Labels)[key] = v6
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "type":
+			out.MType = string(in.String())
+		case "from":
+			out.From = int64(in.Int64())
+		case "to":
+			out.To = int64(in.Int64())
+		case "step":
+			out.Step = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out *jwriter.Writer, in MetricsRange) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v7First := true
+			for v7Name, v7Value := range in.Labels {
+				if v7First {
+					v7First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v7Name))
+				out.RawByte(':')
+				out.String(string(v7Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.MType))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.From))
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.To))
+	}
+	{
+		const prefix string = ",\"step\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Step))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MetricsRange) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MetricsRange) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF4856181EncodeGithubComXantiniumMetrixInternalServerHandlersV22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MetricsRange) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MetricsRange) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF4856181DecodeGithubComXantiniumMetrixInternalServerHandlersV22(l, v)
+}
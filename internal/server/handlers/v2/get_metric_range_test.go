@@ -0,0 +1,89 @@
+package v2handlers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/xantinium/metrix/internal/models"
+	v2handlers "github.com/xantinium/metrix/internal/server/handlers/v2"
+)
+
+func TestParseGetMetricRangeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqBody string
+		want    v2handlers.GetMetricRangeRequest
+		wantErr bool
+	}{
+		{
+			name:    "Валидный json",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":100,"to":200,"step":10}`,
+			want: v2handlers.GetMetricRangeRequest{
+				MetricID:   "HeapAlloc",
+				MetricType: models.Gauge,
+				From:       time.Unix(100, 0),
+				To:         time.Unix(200, 0),
+				Step:       10 * time.Second,
+			},
+		},
+		{
+			name:    "Невалидный json: пустой id",
+			reqBody: `{"type":"gauge","from":100,"to":200,"step":10}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: неизвестный type",
+			reqBody: `{"id":"HeapAlloc","type":"unknown","from":100,"to":200,"step":10}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: нулевой step",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":100,"to":200}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: to меньше from",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":200,"to":100,"step":10}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: слишком большой step",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":100,"to":200,"step":9223372036854775807}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: слишком много интервалов",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":0,"to":11000,"step":1}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: промежуток длиннее int64",
+			reqBody: `{"id":"HeapAlloc","type":"gauge","from":-9223372036854775808,"to":9223372036854775807,"step":9223372036}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &gin.Context{
+				Request: &http.Request{
+					Body: io.NopCloser(bytes.NewBuffer([]byte(tt.reqBody))),
+				},
+			}
+
+			got, err := v2handlers.ParseGetMetricRangeRequest(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGetMetricRangeRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGetMetricRangeRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -17,6 +18,7 @@ func TestParseGetMetricRequest(t *testing.T) {
 	tests := []struct {
 		name    string
 		reqBody string
+		query   string
 		want    v2handlers.GetMetricsRequest
 		wantErr bool
 	}{
@@ -30,6 +32,28 @@ func TestParseGetMetricRequest(t *testing.T) {
 			reqBody: `{"id":"PollCount","type":"counter"}`,
 			want:    v2handlers.GetMetricsRequest{MetricID: "PollCount", MetricType: models.Counter},
 		},
+		{
+			name:    "Валидный json для типа Summary с квантилями",
+			reqBody: `{"id":"Latency","type":"summary"}`,
+			query:   "q=0.5&q=0.99",
+			want: v2handlers.GetMetricsRequest{
+				MetricID:   "Latency",
+				MetricType: models.Summary,
+				Quantiles:  []float64{0.5, 0.99},
+			},
+		},
+		{
+			name:    "Невалидный квантиль",
+			reqBody: `{"id":"Latency","type":"summary"}`,
+			query:   "q=1.5",
+			wantErr: true,
+		},
+		{
+			name:    "Квантиль для метрики типа Gauge",
+			reqBody: `{"id":"Alloc","type":"gauge"}`,
+			query:   "q=0.5",
+			wantErr: true,
+		},
 		{
 			name:    "Невалидный json: пустой id",
 			reqBody: `{"id":""}`,
@@ -45,6 +69,7 @@ func TestParseGetMetricRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := &gin.Context{
 				Request: &http.Request{
+					URL:  &url.URL{RawQuery: tt.query},
 					Body: io.NopCloser(bytes.NewBuffer([]byte(tt.reqBody))),
 				},
 			}
@@ -0,0 +1,104 @@
+package v2handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols"
+	"github.com/xantinium/metrix/internal/protocols/graphite"
+	"github.com/xantinium/metrix/internal/protocols/influx"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+//easyjson:json
+type IngestResponse struct {
+	Errors   []IngestLineError `json:"errors,omitempty"`     // ошибки разбора отдельных строк
+	Accepted int               `json:"accepted" example:"2"` // количество сохранённых метрик
+}
+
+// IngestLineError ошибка разбора строки.
+type IngestLineError struct {
+	Error string `json:"error" example:"invalid metric value"` // текст ошибки
+	Line  int    `json:"line" example:"3"`                     // номер строки (нумерация с единицы)
+}
+
+// parseFunc функция разбора текстового протокола.
+type parseFunc = func(data []byte) ([]models.MetricInfo, []protocols.LineError)
+
+// GraphiteIngestHandler реализация хендлера для приёма
+// метрик в формате Graphite plaintext.
+// @Tags Ingest
+// @Summary Приём метрик в формате Graphite
+// @Description Приём строк вида "path value [timestamp]". Все значения сохраняются как метрики типа gauge.
+// @Description Строки с ошибками пропускаются, остальные сохраняются.
+// @ID ingestGraphite
+// @Accept  plain
+// @Produce json
+// @Param payload body string true "Строки в формате Graphite plaintext"
+// @Success 200 {object} IngestResponse
+// @Failure 400 {object} IngestResponse "Ни одна строка не разобрана"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
+// @Router /ingest/graphite [post]
+func GraphiteIngestHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	return ingest(ctx, s, graphite.Parse)
+}
+
+// InfluxIngestHandler реализация хендлера для приёма
+// метрик в формате InfluxDB line protocol.
+// @Tags Ingest
+// @Summary Приём метрик в формате InfluxDB line protocol
+// @Description Приём строк вида "measurement[,tags] field=value[,...] [timestamp]".
+// @Description Целочисленные поля сохраняются как метрики типа counter, дробные - как метрики типа gauge.
+// @Description Строки с ошибками пропускаются, остальные сохраняются.
+// @ID ingestInflux
+// @Accept  plain
+// @Produce json
+// @Param payload body string true "Строки в формате InfluxDB line protocol"
+// @Success 200 {object} IngestResponse
+// @Failure 400 {object} IngestResponse "Ни одна строка не разобрана"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
+// @Router /ingest/influx [post]
+func InfluxIngestHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	return ingest(ctx, s, influx.Parse)
+}
+
+// ingest разбирает тело запроса при помощи parse и сохраняет
+// успешно разобранные метрики. Ошибки разбора возвращаются в ответе.
+func ingest(ctx *gin.Context, s interfaces.Server, parse parseFunc) (int, easyjson.Marshaler, error) {
+	bodyBytes, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	metrics, lineErrs := parse(bodyBytes)
+
+	resp := IngestResponse{Accepted: len(metrics)}
+	for _, lineErr := range lineErrs {
+		resp.Errors = append(resp.Errors, IngestLineError{
+			Line:  lineErr.Line,
+			Error: lineErr.Err.Error(),
+		})
+	}
+
+	if len(metrics) == 0 {
+		if len(lineErrs) > 0 {
+			return http.StatusBadRequest, resp, nil
+		}
+
+		return http.StatusBadRequest, nil, errors.New("request contains no metrics")
+	}
+
+	err = s.GetMetricsRepo().UpdateMetrics(ctx, metrics)
+	if err != nil {
+		return updateErrorStatus(ctx, err), nil, err
+	}
+
+	return http.StatusOK, resp, nil
+}
@@ -0,0 +1,178 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8b74818cDecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *IngestResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]IngestLineError, 0, 2)
+					} else {
+						out.Errors = []IngestLineError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 IngestLineError
+					easyjson8b74818cDecodeGithubComXantiniumMetrixInternalServerHandlersV21(in, &v1)
+					out.Errors = append(out.Errors, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "accepted":
+			out.Accepted = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b74818cEncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in IngestResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Errors) != 0 {
+		const prefix string = ",\"errors\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Errors {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8b74818cEncodeGithubComXantiniumMetrixInternalServerHandlersV21(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"accepted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Accepted))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IngestResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8b74818cEncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IngestResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8b74818cEncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IngestResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8b74818cDecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IngestResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8b74818cDecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjson8b74818cDecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *IngestLineError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "error":
+			out.Error = string(in.String())
+		case "line":
+			out.Line = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b74818cEncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in IngestLineError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"line\":"
+		out.RawString(prefix)
+		out.Int(int(in.Line))
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,58 @@
+package v2handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/protocols/otlp"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+// OTLPMetricsHandler реализация хендлера для приёма метрик
+// OpenTelemetry по протоколу OTLP/HTTP в JSON-кодировке.
+// @Tags Ingest
+// @Summary Приём метрик OpenTelemetry (OTLP/HTTP JSON)
+// @Description Приём объекта ExportMetricsServiceRequest. Поддерживаются метрики Gauge и Sum:
+// @Description монотонные суммы сохраняются как метрики типа counter, остальные - как метрики типа gauge.
+// @Description Атрибуты ресурса и значений сохраняются в виде меток.
+// @ID ingestOTLP
+// @Accept  json
+// @Produce json
+// @Param payload body otlp.ExportMetricsServiceRequest true "Тело запроса"
+// @Success 200 {object} otlp.ExportMetricsServiceResponse
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
+// @Router /v1/metrics [post]
+func OTLPMetricsHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	bodyBytes, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	var req otlp.ExportMetricsServiceRequest
+
+	err = easyjson.Unmarshal(bodyBytes, &req)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	result, err := s.GetOTLPConverter().Export(ctx, req)
+	if err != nil {
+		return updateErrorStatus(ctx, err), nil, err
+	}
+
+	var resp otlp.ExportMetricsServiceResponse
+	if result.Rejected > 0 {
+		resp.PartialSuccess = &otlp.ExportMetricsPartialSuccess{
+			RejectedDataPoints: result.Rejected,
+			ErrorMessage:       strings.Join(result.Errors, "; "),
+		}
+	}
+
+	return http.StatusOK, resp, nil
+}
@@ -0,0 +1,164 @@
+package v2handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/query"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+// maxQueryClockSkew допустимое расхождение момента вычисления
+// с текущим временем, при котором используются текущие значения метрик.
+const maxQueryClockSkew = 5 * time.Second
+
+// Типы результатов запроса.
+const (
+	resultTypeScalar = "scalar"
+	resultTypeVector = "vector"
+)
+
+//easyjson:json
+type Query struct {
+	Query string `json:"query" example:"sum by (host) (CPUutilization*)"` // выражение
+	Time  int64  `json:"time,omitempty" example:"1735678800"`             // момент вычисления (unix-время в секундах, не в будущем), по умолчанию текущий
+}
+
+//easyjson:json
+type QueryResponse struct {
+	Scalar     *float64      `json:"scalar,omitempty" example:"42"` // значение в случае результата типа scalar
+	Result     []QuerySample `json:"result"`                        // значения рядов в случае результата типа vector
+	ResultType string        `json:"resultType" example:"vector"`   // тип результата: scalar или vector
+	Time       int64         `json:"time" example:"1735678800"`     // момент вычисления (unix-время в секундах)
+}
+
+// QuerySample значение временного ряда в момент вычисления.
+type QuerySample struct {
+	Labels map[string]string `json:"labels,omitempty"`                 // метки ряда
+	Value  *float64          `json:"value" example:"93.5"`             // значение; null, если оно не является конечным числом
+	ID     string            `json:"id,omitempty" example:"HeapAlloc"` // идентификатор метрики; отсутствует у вычисленных рядов
+}
+
+// QueryHandler реализация хендлера для вычисления выражения
+// над текущими значениями и историей метрик.
+// @Tags Metrics
+// @Summary Вычисление выражения
+// @Description Вычисление выражения (выборки по названию, шаблону и меткам, арифметика, sum/avg/min/max by (...), rate(), delta()) на заданный момент.
+// @Description На прошедший момент выборки возвращают последние значения рядов из истории не позднее этого момента
+// @ID query
+// @Accept  json
+// @Produce json
+// @Param payload body Query true "Тело запроса"
+// @Success 200 {object} QueryResponse
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /query [post]
+func QueryHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	req, err := ParseQueryRequest(ctx, time.Now())
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	var value query.Value
+	if req.Historical {
+		value, err = query.EvaluateAt(ctx, s.GetMetricsRepo(), req.Expr, req.Time)
+	} else {
+		value, err = query.Evaluate(ctx, s.GetMetricsRepo(), req.Expr, req.Time)
+	}
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return http.StatusBadRequest, nil, err
+		}
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, newQueryResponse(value, req.Time), nil
+}
+
+// QueryRequest запрос на вычисление выражения.
+type QueryRequest struct {
+	Time time.Time
+	Expr query.Expr
+	// Historical признак вычисления на прошедший момент по истории метрик.
+	Historical bool
+}
+
+// ParseQueryRequest парсит запрос на вычисление выражения.
+// Если момент вычисления не указан, используется now.
+// Момент вычисления не может быть в будущем.
+func ParseQueryRequest(ctx *gin.Context, now time.Time) (QueryRequest, error) {
+	var rawReq Query
+
+	bodyBytes, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return QueryRequest{}, err
+	}
+
+	err = easyjson.Unmarshal(bodyBytes, &rawReq)
+	if err != nil {
+		return QueryRequest{}, err
+	}
+
+	if rawReq.Query == "" {
+		return QueryRequest{}, fmt.Errorf("query cannot be empty")
+	}
+
+	req := QueryRequest{Time: now}
+	if rawReq.Time != 0 {
+		req.Time = time.Unix(rawReq.Time, 0)
+
+		if req.Time.After(now.Add(maxQueryClockSkew)) {
+			return QueryRequest{}, fmt.Errorf("query time cannot be in the future")
+		}
+
+		req.Historical = req.Time.Before(now.Add(-maxQueryClockSkew))
+	}
+
+	req.Expr, err = query.Parse(rawReq.Query)
+	if err != nil {
+		return QueryRequest{}, err
+	}
+
+	return req, nil
+}
+
+func newQueryResponse(value query.Value, now time.Time) QueryResponse {
+	resp := QueryResponse{
+		Result: []QuerySample{},
+		Time:   now.Unix(),
+	}
+
+	switch v := value.(type) {
+	case query.Scalar:
+		resp.ResultType = resultTypeScalar
+		resp.Scalar = finiteOrNil(float64(v))
+	case query.Vector:
+		resp.ResultType = resultTypeVector
+		resp.Result = make([]QuerySample, len(v))
+		for i, sample := range v {
+			resp.Result[i] = QuerySample{
+				Labels: sample.Labels,
+				Value:  finiteOrNil(sample.Value),
+				ID:     sample.MetricID,
+			}
+		}
+	}
+
+	return resp
+}
+
+// finiteOrNil возвращает указатель на значение или nil,
+// если значение не может быть представлено в JSON (NaN, ±Inf).
+func finiteOrNil(value float64) *float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return &value
+}
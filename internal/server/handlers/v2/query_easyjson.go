@@ -0,0 +1,332 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *QueryResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "scalar":
+			if in.IsNull() {
+				in.Skip()
+				out.Scalar = nil
+			} else {
+				if out.Scalar == nil {
+					out.Scalar = new(float64)
+				}
+				*out.Scalar = float64(in.Float64())
+			}
+		case "result":
+			if in.IsNull() {
+				in.Skip()
+				out.Result = nil
+			} else {
+				in.Delim('[')
+				if out.Result == nil {
+					if !in.IsDelim(']') {
+						out.Result = make([]QuerySample, 0, 2)
+					} else {
+						out.Result = []QuerySample{}
+					}
+				} else {
+					out.Result = (out.Result)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 QuerySample
+					easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in, &v1)
+					out.Result = append(out.Result, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "resultType":
+			out.ResultType = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in QueryResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Scalar != nil {
+		const prefix string = ",\"scalar\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Float64(float64(*in.Scalar))
+	}
+	{
+		const prefix string = ",\"result\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Result == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Result {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"resultType\":"
+		out.RawString(prefix)
+		out.String(string(in.ResultType))
+	}
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v QueryResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v QueryResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *QueryResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *QueryResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *QuerySample) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "labels":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Labels = make(map[string]string)
+				} else {
+					out.Labels = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v4 string
+					v4 = string(in.String())
+					(out.Labels)[key] = v4
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "value":
+			if in.IsNull() {
+				in.Skip()
+				out.Value = nil
+			} else {
+				if out.Value == nil {
+					out.Value = new(float64)
+				}
+				*out.Value = float64(in.Float64())
+			}
+		case "id":
+			out.ID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in QuerySample) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Labels) != 0 {
+		const prefix string = ",\"labels\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('{')
+			v5First := true
+			for v5Name, v5Value := range in.Labels {
+				if v5First {
+					v5First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v5Name))
+				out.RawByte(':')
+				out.String(string(v5Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"value\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Value == nil {
+			out.RawString("null")
+		} else {
+			out.Float64(float64(*in.Value))
+		}
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	out.RawByte('}')
+}
+func easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in *jlexer.Lexer, out *Query) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "query":
+			out.Query = string(in.String())
+		case "time":
+			out.Time = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out *jwriter.Writer, in Query) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"query\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Query))
+	}
+	if in.Time != 0 {
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Time))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Query) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Query) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson90b16446EncodeGithubComXantiniumMetrixInternalServerHandlersV22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Query) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Query) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson90b16446DecodeGithubComXantiniumMetrixInternalServerHandlersV22(l, v)
+}
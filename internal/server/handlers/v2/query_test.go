@@ -0,0 +1,88 @@
+package v2handlers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	v2handlers "github.com/xantinium/metrix/internal/server/handlers/v2"
+)
+
+func TestParseQueryRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		reqBody  string
+		wantExpr string
+		wantTime time.Time
+		// wantHistorical признак вычисления по истории.
+		wantHistorical bool
+		wantErr        bool
+	}{
+		{
+			name:     "Запрос на текущий момент",
+			reqBody:  `{"query":"sum by (host) (CPUutilization*)"}`,
+			wantExpr: "sum by (host) (CPUutilization*)",
+			wantTime: now,
+		},
+		{
+			name:           "Запрос на заданный момент",
+			reqBody:        `{"query":"rate(PollCount[1m])","time":1735678800}`,
+			wantExpr:       "rate(PollCount[1m0s])",
+			wantTime:       time.Unix(1735678800, 0),
+			wantHistorical: true,
+		},
+		{
+			name:     "Запрос на момент в пределах расхождения часов",
+			reqBody:  `{"query":"PollCount","time":1735689601}`,
+			wantExpr: "PollCount",
+			wantTime: time.Unix(1735689601, 0),
+		},
+		{
+			name:    "Запрос на момент в будущем",
+			reqBody: `{"query":"PollCount","time":1735693200}`,
+			wantErr: true,
+		},
+		{
+			name:    "Пустое выражение",
+			reqBody: `{"time":1735678800}`,
+			wantErr: true,
+		},
+		{
+			name:    "Некорректное выражение",
+			reqBody: `{"query":"sum(PollCount"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json",
+			reqBody: `{"query":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &gin.Context{
+				Request: &http.Request{
+					Body: io.NopCloser(bytes.NewBuffer([]byte(tt.reqBody))),
+				},
+			}
+
+			got, err := v2handlers.ParseQueryRequest(ctx, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantExpr, got.Expr.String())
+			require.True(t, tt.wantTime.Equal(got.Time))
+			require.Equal(t, tt.wantHistorical, got.Historical)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package v2handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/server/interfaces"
+)
+
+// Состояния заглушения в ответах.
+const (
+	silenceStatePending = "pending"
+	silenceStateActive  = "active"
+	silenceStateExpired = "expired"
+)
+
+//easyjson:json
+type Silence struct {
+	StartsAt  *time.Time `json:"startsAt,omitempty" example:"2025-01-01T00:00:00Z"`       // начало действия (по умолчанию текущий момент)
+	EndsAt    *time.Time `json:"endsAt,omitempty" example:"2025-01-01T02:00:00Z"`         // окончание действия
+	Matchers  []Matcher  `json:"matchers,omitempty"`                                      // условия на метки оповещения
+	ID        string     `json:"id,omitempty" example:"6f1c0e2a9b7d4c3e8a5f1b2c3d4e5f60"` // идентификатор заглушения (в ответе)
+	RuleName  string     `json:"ruleName,omitempty" example:"HighCPU*"`                   // шаблон названия правила (синтаксис path.Match)
+	Duration  string     `json:"duration,omitempty" example:"2h"`                         // длительность действия, если не указан endsAt
+	Comment   string     `json:"comment,omitempty" example:"deploy"`                      // комментарий
+	CreatedBy string     `json:"createdBy,omitempty" example:"ops"`                       // автор
+	State     string     `json:"state,omitempty" example:"active"`                        // состояние: pending, active или expired (в ответе)
+}
+
+// Matcher условие на значение метки оповещения.
+// Идентификатор метрики доступен как метка __name__.
+type Matcher struct {
+	Name  string `json:"name" example:"__name__"`          // название метки
+	Value string `json:"value" example:"CPUutilization.*"` // значение или регулярное выражение
+	Type  string `json:"type,omitempty" example:"=~"`      // тип сопоставления: =, !=, =~ или !~ (по умолчанию =)
+}
+
+//easyjson:json
+type SilencesResponse struct {
+	Silences []Silence `json:"silences"` // все заглушения, включая завершённые
+}
+
+// CreateSilenceHandler реализация хендлера для создания заглушения.
+// @Tags Alerts
+// @Summary Создание заглушения
+// @Description Создание заглушения оповещений по названию правила и условиям на метки на промежуток времени
+// @ID createSilence
+// @Accept  json
+// @Produce json
+// @Param payload body Silence true "Тело запроса"
+// @Success 200 {object} Silence
+// @Failure 400 {string} string "Неверный запрос"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /silences [post]
+func CreateSilenceHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	now := time.Now()
+
+	silence, err := ParseCreateSilenceRequest(ctx, now)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
+	silence, err = s.GetMetricsRepo().CreateSilence(ctx, silence)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	return http.StatusOK, newSilence(silence, now), nil
+}
+
+// GetSilencesHandler реализация хендлера для получения заглушений.
+// @Tags Alerts
+// @Summary Получение заглушений
+// @Description Получение всех заглушений, включая завершённые
+// @ID getSilences
+// @Produce json
+// @Success 200 {object} SilencesResponse
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /silences [get]
+func GetSilencesHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	now := time.Now()
+
+	silences, err := s.GetMetricsRepo().GetSilences(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+
+	resp := SilencesResponse{Silences: make([]Silence, len(silences))}
+	for i, silence := range silences {
+		resp.Silences[i] = newSilence(silence, now)
+	}
+
+	return http.StatusOK, resp, nil
+}
+
+// ExpireSilenceHandler реализация хендлера для завершения заглушения.
+// @Tags Alerts
+// @Summary Завершение заглушения
+// @Description Досрочное завершение заглушения. Завершённые заглушения остаются в списке
+// @Description до истечения времени хранения (флаг -silence-retention)
+// @ID expireSilence
+// @Produce json
+// @Param id path string true "Идентификатор заглушения"
+// @Success 200 {object} Silence
+// @Failure 404 {string} string "Заглушение не найдено"
+// @Failure 409 {string} string "Заглушение уже завершено"
+// @Failure 500 {string} string "Внутренняя ошибка"
+// @Router /silences/{id} [delete]
+func ExpireSilenceHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
+	now := time.Now()
+
+	silence, err := s.GetMetricsRepo().ExpireSilence(ctx, ctx.Param("id"), now)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			return http.StatusNotFound, nil, err
+		case errors.Is(err, models.ErrSilenceExpired):
+			return http.StatusConflict, nil, err
+		default:
+			return http.StatusInternalServerError, nil, err
+		}
+	}
+
+	return http.StatusOK, newSilence(silence, now), nil
+}
+
+// ParseCreateSilenceRequest парсит запрос на создание заглушения.
+// Если начало действия не указано, используется момент now.
+func ParseCreateSilenceRequest(ctx *gin.Context, now time.Time) (models.Silence, error) {
+	var rawReq Silence
+
+	bodyBytes, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	err = easyjson.Unmarshal(bodyBytes, &rawReq)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	silence := models.Silence{
+		StartsAt:  now,
+		Matchers:  make([]models.LabelMatcher, len(rawReq.Matchers)),
+		RuleName:  rawReq.RuleName,
+		Comment:   rawReq.Comment,
+		CreatedBy: rawReq.CreatedBy,
+	}
+
+	if rawReq.StartsAt != nil {
+		silence.StartsAt = *rawReq.StartsAt
+	}
+
+	switch {
+	case rawReq.EndsAt != nil && rawReq.Duration != "":
+		return models.Silence{}, fmt.Errorf("only one of endsAt and duration can be set")
+	case rawReq.EndsAt != nil:
+		silence.EndsAt = *rawReq.EndsAt
+	case rawReq.Duration != "":
+		var duration time.Duration
+
+		duration, err = time.ParseDuration(rawReq.Duration)
+		if err != nil {
+			return models.Silence{}, fmt.Errorf("invalid duration: %v", err)
+		}
+
+		silence.EndsAt = silence.StartsAt.Add(duration)
+	default:
+		return models.Silence{}, fmt.Errorf("endsAt or duration is required")
+	}
+
+	for i, matcher := range rawReq.Matchers {
+		matchType := models.MatchType(matcher.Type)
+		if matchType == "" {
+			matchType = models.MatchEqual
+		}
+
+		silence.Matchers[i] = models.LabelMatcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  matchType,
+		}
+	}
+
+	err = silence.Validate()
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	if !silence.EndsAt.After(now) {
+		return models.Silence{}, fmt.Errorf("silence cannot end in the past")
+	}
+
+	return silence, nil
+}
+
+func newSilence(silence models.Silence, now time.Time) Silence {
+	startsAt := silence.StartsAt.UTC()
+	endsAt := silence.EndsAt.UTC()
+
+	item := Silence{
+		StartsAt:  &startsAt,
+		EndsAt:    &endsAt,
+		Matchers:  make([]Matcher, len(silence.Matchers)),
+		ID:        silence.ID,
+		RuleName:  silence.RuleName,
+		Comment:   silence.Comment,
+		CreatedBy: silence.CreatedBy,
+	}
+
+	switch {
+	case silence.IsActive(now):
+		item.State = silenceStateActive
+	case now.Before(silence.StartsAt):
+		item.State = silenceStatePending
+	default:
+		item.State = silenceStateExpired
+	}
+
+	for i, matcher := range silence.Matchers {
+		item.Matchers[i] = Matcher{
+			Name:  matcher.Name,
+			Value: matcher.Value,
+			Type:  string(matcher.Type),
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,388 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package v2handlers
+
+import (
+	json "encoding/json"
+
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV2(in *jlexer.Lexer, out *SilencesResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "silences":
+			if in.IsNull() {
+				in.Skip()
+				out.Silences = nil
+			} else {
+				in.Delim('[')
+				if out.Silences == nil {
+					if !in.IsDelim(']') {
+						out.Silences = make([]Silence, 0, 0)
+					} else {
+						out.Silences = []Silence{}
+					}
+				} else {
+					out.Silences = (out.Silences)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 Silence
+					(v1).UnmarshalEasyJSON(in)
+					out.Silences = append(out.Silences, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV2(out *jwriter.Writer, in SilencesResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"silences\":"
+		out.RawString(prefix[1:])
+		if in.Silences == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Silences {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SilencesResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SilencesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SilencesResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SilencesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV2(l, v)
+}
+func easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV21(in *jlexer.Lexer, out *Silence) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "startsAt":
+			if in.IsNull() {
+				in.Skip()
+				out.StartsAt = nil
+			} else {
+				if out.StartsAt == nil {
+					out.StartsAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.StartsAt).UnmarshalJSON(data))
+				}
+			}
+		case "endsAt":
+			if in.IsNull() {
+				in.Skip()
+				out.EndsAt = nil
+			} else {
+				if out.EndsAt == nil {
+					out.EndsAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.EndsAt).UnmarshalJSON(data))
+				}
+			}
+		case "matchers":
+			if in.IsNull() {
+				in.Skip()
+				out.Matchers = nil
+			} else {
+				in.Delim('[')
+				if out.Matchers == nil {
+					if !in.IsDelim(']') {
+						out.Matchers = make([]Matcher, 0, 1)
+					} else {
+						out.Matchers = []Matcher{}
+					}
+				} else {
+					out.Matchers = (out.Matchers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 Matcher
+					easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in, &v4)
+					out.Matchers = append(out.Matchers, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "id":
+			out.ID = string(in.String())
+		case "ruleName":
+			out.RuleName = string(in.String())
+		case "duration":
+			out.Duration = string(in.String())
+		case "comment":
+			out.Comment = string(in.String())
+		case "createdBy":
+			out.CreatedBy = string(in.String())
+		case "state":
+			out.State = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV21(out *jwriter.Writer, in Silence) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.StartsAt != nil {
+		const prefix string = ",\"startsAt\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Raw((*in.StartsAt).MarshalJSON())
+	}
+	if in.EndsAt != nil {
+		const prefix string = ",\"endsAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.EndsAt).MarshalJSON())
+	}
+	if len(in.Matchers) != 0 {
+		const prefix string = ",\"matchers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Matchers {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ID))
+	}
+	if in.RuleName != "" {
+		const prefix string = ",\"ruleName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.RuleName))
+	}
+	if in.Duration != "" {
+		const prefix string = ",\"duration\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Duration))
+	}
+	if in.Comment != "" {
+		const prefix string = ",\"comment\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Comment))
+	}
+	if in.CreatedBy != "" {
+		const prefix string = ",\"createdBy\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.CreatedBy))
+	}
+	if in.State != "" {
+		const prefix string = ",\"state\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.State))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Silence) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Silence) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Silence) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Silence) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV21(l, v)
+}
+func easyjsonBa71ae30DecodeGithubComXantiniumMetrixInternalServerHandlersV22(in *jlexer.Lexer, out *Matcher) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonBa71ae30EncodeGithubComXantiniumMetrixInternalServerHandlersV22(out *jwriter.Writer, in Matcher) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	if in.Type != "" {
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	out.RawByte('}')
+}
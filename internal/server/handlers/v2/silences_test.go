@@ -0,0 +1,92 @@
+package v2handlers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/models"
+	v2handlers "github.com/xantinium/metrix/internal/server/handlers/v2"
+)
+
+func TestParseCreateSilenceRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		reqBody string
+		want    models.Silence
+		wantErr bool
+	}{
+		{
+			name:    "Заглушение по метрикам на заданное время",
+			reqBody: `{"matchers":[{"name":"__name__","value":"CPUutilization.*","type":"=~"},{"name":"host","value":"a"}],"duration":"2h","comment":"deploy"}`,
+			want: models.Silence{
+				StartsAt: now,
+				EndsAt:   now.Add(2 * time.Hour),
+				Matchers: []models.LabelMatcher{
+					{Name: "__name__", Value: "CPUutilization.*", Type: models.MatchRegexp},
+					{Name: "host", Value: "a", Type: models.MatchEqual},
+				},
+				Comment: "deploy",
+			},
+		},
+		{
+			name:    "Заглушение по правилу в заданном промежутке",
+			reqBody: `{"ruleName":"HighCPU","startsAt":"2025-01-01T01:00:00Z","endsAt":"2025-01-01T02:00:00Z","createdBy":"ops"}`,
+			want: models.Silence{
+				StartsAt:  now.Add(time.Hour),
+				EndsAt:    now.Add(2 * time.Hour),
+				Matchers:  []models.LabelMatcher{},
+				RuleName:  "HighCPU",
+				CreatedBy: "ops",
+			},
+		},
+		{
+			name:    "Не указано окончание",
+			reqBody: `{"ruleName":"HighCPU"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Указаны одновременно окончание и длительность",
+			reqBody: `{"ruleName":"HighCPU","endsAt":"2025-01-01T02:00:00Z","duration":"1h"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Окончание в прошлом",
+			reqBody: `{"ruleName":"HighCPU","startsAt":"2024-12-31T00:00:00Z","endsAt":"2024-12-31T01:00:00Z"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Нет ни правила, ни условий",
+			reqBody: `{"duration":"1h"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &gin.Context{
+				Request: &http.Request{
+					Body: io.NopCloser(bytes.NewBuffer([]byte(tt.reqBody))),
+				},
+			}
+
+			got, err := v2handlers.ParseCreateSilenceRequest(ctx, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tt.want.StartsAt.Equal(got.StartsAt))
+			require.True(t, tt.want.EndsAt.Equal(got.EndsAt))
+			tt.want.StartsAt, tt.want.EndsAt = got.StartsAt, got.EndsAt
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -23,11 +23,14 @@ import (
 // @Failure 400 {string} string "Неверный запрос"
 // @Failure 404 {string} string "Метрика не найдена"
 // @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
 // @Router /update [post]
 func UpdateMetricHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
 	var (
-		updatedGaugeValue   float64
-		updatedCounterValue int64
+		updatedGaugeValue     float64
+		updatedCounterValue   int64
+		updatedHistogramValue models.HistogramValue
+		updatedSummaryValue   models.SummaryValue
 	)
 
 	req, err := ParseUpdateMetricRequest(ctx)
@@ -36,23 +39,30 @@ func UpdateMetricHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.M
 	}
 
 	resp := Metrics{
-		ID:    req.Metric.ID(),
-		MType: string(req.Metric.Type()),
+		ID:     req.Metric.ID(),
+		MType:  string(req.Metric.Type()),
+		Labels: req.Metric.Labels(),
 	}
 
 	metricsRepo := s.GetMetricsRepo()
 
 	switch req.Metric.Type() {
 	case models.Gauge:
-		updatedGaugeValue, err = metricsRepo.UpdateGaugeMetric(ctx, req.Metric.ID(), req.Metric.GaugeValue())
+		updatedGaugeValue, err = metricsRepo.UpdateGaugeMetric(ctx, req.Metric.ID(), req.Metric.Labels(), req.Metric.GaugeValue())
 		resp.Value = &updatedGaugeValue
 	case models.Counter:
-		updatedCounterValue, err = metricsRepo.UpdateCounterMetric(ctx, req.Metric.ID(), req.Metric.CounterValue())
+		updatedCounterValue, err = metricsRepo.UpdateCounterMetric(ctx, req.Metric.ID(), req.Metric.Labels(), req.Metric.CounterValue())
 		resp.Delta = &updatedCounterValue
+	case models.Histogram:
+		updatedHistogramValue, err = metricsRepo.UpdateHistogramMetric(ctx, req.Metric.ID(), req.Metric.Labels(), req.Metric.HistogramValue())
+		resp.Histogram = newHistogram(updatedHistogramValue)
+	case models.Summary:
+		updatedSummaryValue, err = metricsRepo.UpdateSummaryMetric(ctx, req.Metric.ID(), req.Metric.Labels(), req.Metric.SummaryValue())
+		resp.Summary = newSummary(updatedSummaryValue, nil)
 	}
 
 	if err != nil {
-		return http.StatusInternalServerError, nil, err
+		return updateErrorStatus(ctx, err), nil, err
 	}
 
 	return http.StatusOK, resp, nil
@@ -30,6 +30,44 @@ func TestParseUpdateMetricRequest(t *testing.T) {
 			reqBody: `{"id":"PollCounter","type":"counter","delta":8}`,
 			want:    v2handlers.UpdateMetricRequest{Metric: models.NewCounterMetric("PollCounter", 8)},
 		},
+		{
+			name:    "Валидный json c типом Histogram",
+			reqBody: `{"id":"Latency","type":"histogram","histogram":{"bounds":[0.1,0.5],"counts":[2,1,0],"count":3,"sum":0.7}}`,
+			want: v2handlers.UpdateMetricRequest{Metric: models.NewHistogramMetric("Latency", models.HistogramValue{
+				Bounds: []float64{0.1, 0.5},
+				Counts: []int64{2, 1, 0},
+				Count:  3,
+				Sum:    0.7,
+			})},
+		},
+		{
+			name:    "Невалидный json: количество интервалов гистограммы не соответствует границам",
+			reqBody: `{"id":"Latency","type":"histogram","histogram":{"bounds":[0.1,0.5],"counts":[2,1],"sum":0.7}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Невалидный json: общее количество значений гистограммы не совпадает с интервалами",
+			reqBody: `{"id":"Latency","type":"histogram","histogram":{"bounds":[0.1],"counts":[2,1],"count":5,"sum":0.7}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Валидный json c типом Summary",
+			reqBody: `{"id":"Latency","type":"summary","summary":{"accuracy":0.01,"positive":{"offset":-10,"counts":[1,0,2]},"zeroCount":1,"sum":2.5,"min":0,"max":1}}`,
+			want: v2handlers.UpdateMetricRequest{Metric: models.NewSummaryMetric("Latency", models.SummaryValue{
+				Positive:  models.SummaryBins{Offset: -10, Counts: []int64{1, 0, 2}},
+				Accuracy:  0.01,
+				ZeroCount: 1,
+				Count:     4,
+				Sum:       2.5,
+				Min:       0,
+				Max:       1,
+			})},
+		},
+		{
+			name:    "Невалидный json: некорректная точность скетча",
+			reqBody: `{"id":"Latency","type":"summary","summary":{"accuracy":2,"sum":0}}`,
+			wantErr: true,
+		},
 		{
 			name:    "Невалидный json: отсутствует значение",
 			reqBody: `{"id":"Alloc","type":"gauge","delta":8}`,
@@ -1,6 +1,7 @@
 package v2handlers
 
 import (
+	"errors"
 	"io"
 	"net/http"
 
@@ -23,6 +24,7 @@ import (
 // @Failure 400 {string} string "Неверный запрос"
 // @Failure 404 {string} string "Метрика не найдена"
 // @Failure 500 {string} string "Внутренняя ошибка"
+// @Failure 503 {string} string "Очередь обновлений переполнена"
 // @Router /updates [post]
 func UpdateMetricsHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.Marshaler, error) {
 	req, err := ParseUpdateMetricsRequest(ctx)
@@ -32,12 +34,26 @@ func UpdateMetricsHandler(ctx *gin.Context, s interfaces.Server) (int, easyjson.
 
 	err = s.GetMetricsRepo().UpdateMetrics(ctx, req.Metrics)
 	if err != nil {
-		return http.StatusInternalServerError, nil, err
+		return updateErrorStatus(ctx, err), nil, err
 	}
 
 	return http.StatusOK, nil, nil
 }
 
+// updateErrorStatus возвращает код ответа для ошибки обновления метрик.
+// При переполнении очереди обновлений клиенту предлагается повторить запрос позже.
+func updateErrorStatus(ctx *gin.Context, err error) int {
+	switch {
+	case errors.Is(err, models.ErrHistogramBoundsMismatch), errors.Is(err, models.ErrSummaryAccuracyMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrQueueFull):
+		ctx.Header("Retry-After", "1")
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // UpdateMetricsRequest запрос на батчевое обновление метрик.
 type UpdateMetricsRequest struct {
 	Metrics []models.MetricInfo
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HistoryPruner сущность, удаляющая устаревшие значения истории.
+type HistoryPruner interface {
+	PruneHistory(ctx context.Context, now time.Time) (int, error)
+}
+
+// NewHistoryWorker создаёт новый воркер для удаления значений истории,
+// вышедших за время хранения retention. При нулевом retention
+// история не хранится и воркер не запускается.
+//
+// Устаревшие значения удаляются с интервалом retention, но не реже
+// раза в минуту, в том числе у метрик, которые больше не обновляются.
+func NewHistoryWorker(retention time.Duration, pruner HistoryPruner) *HistoryWorker {
+	worker := &HistoryWorker{
+		pruner: pruner,
+	}
+	worker.PeriodicWorker = NewPeriodicWorker("history-worker", cleanupInterval(retention), worker.pruneHistory)
+
+	return worker
+}
+
+// HistoryWorker структура, описывающая воркер
+// для периодического удаления устаревших значений истории.
+type HistoryWorker struct {
+	*PeriodicWorker
+	pruner HistoryPruner
+}
+
+// pruneHistory удаляет значения истории, устаревшие к моменту now.
+func (worker *HistoryWorker) pruneHistory(ctx context.Context, now time.Time) {
+	_, err := worker.pruner.PruneHistory(ctx, now)
+	if err != nil {
+		worker.log(fmt.Sprintf("failed to prune metrics history: %v", err))
+	}
+}
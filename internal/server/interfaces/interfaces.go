@@ -4,6 +4,8 @@ package interfaces
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/protocols/otlp"
 	"github.com/xantinium/metrix/internal/repository/metrics"
 )
 
@@ -11,4 +13,6 @@ import (
 type Server interface {
 	GetInternalRouter() *gin.Engine
 	GetMetricsRepo() *metrics.MetricsRepository
+	GetAlertsManager() *alerting.Manager
+	GetOTLPConverter() *otlp.Converter
 }
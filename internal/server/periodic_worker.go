@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/xantinium/metrix/internal/logger"
+)
+
+// maxCleanupInterval максимальный интервал между периодическими
+// удалениями устаревших данных.
+const maxCleanupInterval = time.Minute
+
+// cleanupInterval возвращает интервал удаления данных, устаревающих
+// через retention: не больше retention, но не реже раза в минуту.
+// Данные удаляются не позднее, чем через retention + 1 минута.
+func cleanupInterval(retention time.Duration) time.Duration {
+	return min(retention, maxCleanupInterval)
+}
+
+// PeriodicTask задача, выполняемая воркером в момент now.
+type PeriodicTask func(ctx context.Context, now time.Time)
+
+// NewPeriodicWorker создаёт новый воркер, выполняющий задачу task
+// с интервалом interval. При нулевом interval воркер не запускается.
+//
+// entity - имя воркера в логах.
+func NewPeriodicWorker(entity string, interval time.Duration, task PeriodicTask) *PeriodicWorker {
+	return &PeriodicWorker{
+		stopFunc: func() {},
+		task:     task,
+		entity:   entity,
+		interval: interval,
+	}
+}
+
+// PeriodicWorker структура, описывающая воркер
+// для периодического выполнения задачи.
+type PeriodicWorker struct {
+	task     PeriodicTask
+	stopFunc context.CancelFunc
+	entity   string
+	interval time.Duration
+}
+
+// Run запускает воркер.
+func (worker *PeriodicWorker) Run() {
+	if worker.interval == 0 {
+		return
+	}
+
+	var ctx context.Context
+	ctx, worker.stopFunc = context.WithCancel(context.TODO())
+
+	t := time.NewTicker(worker.interval)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				worker.log("stopping...")
+				t.Stop()
+				return
+			case now := <-t.C:
+				worker.task(ctx, now)
+			}
+		}
+	}()
+}
+
+// Stop прекращает работу воркера.
+func (worker *PeriodicWorker) Stop() {
+	worker.stopFunc()
+}
+
+// log логирует события воркера.
+func (worker *PeriodicWorker) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: worker.entity,
+		},
+	)
+}
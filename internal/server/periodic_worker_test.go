@@ -0,0 +1,49 @@
+package server_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/server"
+)
+
+func TestPeriodicWorker(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	t.Run("Задача выполняется с заданным интервалом", func(t *testing.T) {
+		var calls atomic.Int64
+
+		worker := server.NewPeriodicWorker("test-worker", 10*time.Millisecond, func(_ context.Context, _ time.Time) {
+			calls.Add(1)
+		})
+
+		worker.Run()
+		require.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+		worker.Stop()
+
+		// После остановки задача больше не выполняется.
+		stopped := calls.Load()
+		time.Sleep(50 * time.Millisecond)
+		require.LessOrEqual(t, calls.Load(), stopped+1)
+	})
+
+	t.Run("При нулевом интервале воркер не запускается", func(t *testing.T) {
+		var calls atomic.Int64
+
+		worker := server.NewPeriodicWorker("test-worker", 0, func(_ context.Context, _ time.Time) {
+			calls.Add(1)
+		})
+
+		worker.Run()
+		time.Sleep(50 * time.Millisecond)
+		worker.Stop()
+
+		require.Zero(t, calls.Load())
+	})
+}
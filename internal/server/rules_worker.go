@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xantinium/metrix/internal/logger"
+)
+
+// RulesEvaluator сущность, вычисляющая правила.
+type RulesEvaluator interface {
+	Evaluate(ctx context.Context, now time.Time) error
+}
+
+// NewRulesWorker создаёт новый воркер для вычисления правил.
+//
+// evaluationInterval - интервал между вычислениями правил.
+// Вычислители вызываются последовательно в порядке передачи,
+// поэтому правила записи следует передавать раньше правил оповещений,
+// чтобы оповещения учитывали свежие значения производных метрик.
+func NewRulesWorker(evaluationInterval time.Duration, evaluators ...RulesEvaluator) *RulesWorker {
+	return &RulesWorker{
+		stopFunc:           func() {},
+		evaluationInterval: evaluationInterval,
+		evaluators:         evaluators,
+	}
+}
+
+// RulesWorker структура, описывающая воркер
+// для периодического вычисления правил записи и оповещений.
+type RulesWorker struct {
+	stopFunc           context.CancelFunc
+	done               chan struct{}
+	evaluators         []RulesEvaluator
+	evaluationInterval time.Duration
+}
+
+// Run запускает воркер.
+func (worker *RulesWorker) Run() {
+	// Вычисление правил работает только при ненулевом evaluationInterval.
+	if worker.evaluationInterval == 0 {
+		return
+	}
+
+	var ctx context.Context
+	ctx, worker.stopFunc = context.WithCancel(context.TODO())
+
+	t := time.NewTicker(worker.evaluationInterval)
+
+	worker.done = make(chan struct{})
+
+	go func() {
+		defer close(worker.done)
+
+		for {
+			select {
+			case <-ctx.Done():
+				worker.log("stopping...")
+				t.Stop()
+				return
+			case now := <-t.C:
+				worker.evaluate(ctx, now)
+			}
+		}
+	}()
+}
+
+// evaluate вычисляет правила всех вычислителей на момент now.
+func (worker *RulesWorker) evaluate(ctx context.Context, now time.Time) {
+	for _, evaluator := range worker.evaluators {
+		err := evaluator.Evaluate(ctx, now)
+		if err != nil {
+			worker.log(fmt.Sprintf("failed to evaluate rules: %v", err))
+		}
+	}
+}
+
+// Stop прекращает работу воркера и дожидается
+// завершения текущего вычисления правил.
+func (worker *RulesWorker) Stop() {
+	worker.stopFunc()
+
+	if worker.done != nil {
+		<-worker.done
+	}
+}
+
+// log логирует события воркера.
+func (worker *RulesWorker) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: "rules-worker",
+		},
+	)
+}
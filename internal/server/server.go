@@ -4,13 +4,18 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	_ "net/http/pprof" // Используется для корректной работы профилировщика.
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/xantinium/metrix/internal/alerting"
+	"github.com/xantinium/metrix/internal/protocols/otlp"
+	"github.com/xantinium/metrix/internal/recording"
 	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/repository/metrics/writebehind"
 	"github.com/xantinium/metrix/internal/server/handlers"
 	v2handlers "github.com/xantinium/metrix/internal/server/handlers/v2"
 	"github.com/xantinium/metrix/internal/server/middlewares"
@@ -24,8 +29,10 @@ func init() {
 // internalMetrixServer внутренняя структура сервера.
 // Является реализацией интерфейса сервера, получаемого хендлерами.
 type internalMetrixServer struct {
-	router      *gin.Engine
-	metricsRepo *metrics.MetricsRepository
+	router        *gin.Engine
+	metricsRepo   *metrics.MetricsRepository
+	alertsManager *alerting.Manager
+	otlpConverter *otlp.Converter
 }
 
 // GetInternalRouter возвращает используемый роутер.
@@ -38,14 +45,37 @@ func (server *internalMetrixServer) GetMetricsRepo() *metrics.MetricsRepository
 	return server.metricsRepo
 }
 
+// GetAlertsManager возвращает менеджер оповещений.
+func (server *internalMetrixServer) GetAlertsManager() *alerting.Manager {
+	return server.alertsManager
+}
+
+// GetOTLPConverter возвращает преобразователь OTLP-метрик.
+func (server *internalMetrixServer) GetOTLPConverter() *otlp.Converter {
+	return server.otlpConverter
+}
+
 // MetrixServerBuilder билдер для создания сервера метрик.
 type MetrixServerBuilder struct {
-	dbChecker          metrics.DatabaseChecker
-	storage            metrics.MetricsStorage
-	addr               string
-	privateKey         string
-	storeInterval      time.Duration
-	isProfilingEnabled bool
+	dbChecker            metrics.DatabaseChecker
+	storage              metrics.MetricsStorage
+	alertsNotifier       *alerting.Notifier
+	alertingRules        []alerting.Rule
+	recordingRules       []recording.Rule
+	addr                 string
+	statsdAddr           string
+	graphiteAddr         string
+	grpcAddr             string
+	privateKey           string
+	writeBehindSize      int
+	writeBehindFlushSize int
+	storeInterval        time.Duration
+	rulesInterval        time.Duration
+	writeBehindInterval  time.Duration
+	metricsTTL           time.Duration
+	historyRetention     time.Duration
+	silenceRetention     time.Duration
+	isProfilingEnabled   bool
 }
 
 // NewMetrixServerBuilder создаёт новый билдер сервера метрик.
@@ -59,6 +89,29 @@ func (b *MetrixServerBuilder) SetAddr(addr string) *MetrixServerBuilder {
 	return b
 }
 
+// SetStatsdAddr устанавливает адрес UDP-слушателя
+// для приёма метрик в формате StatsD.
+// Если адрес пустой, слушатель не запускается.
+func (b *MetrixServerBuilder) SetStatsdAddr(addr string) *MetrixServerBuilder {
+	b.statsdAddr = addr
+	return b
+}
+
+// SetGraphiteAddr устанавливает адрес TCP-слушателя
+// для приёма метрик в формате Graphite plaintext.
+// Если адрес пустой, слушатель не запускается.
+func (b *MetrixServerBuilder) SetGraphiteAddr(addr string) *MetrixServerBuilder {
+	b.graphiteAddr = addr
+	return b
+}
+
+// SetGRPCAddr устанавливает адрес gRPC-сервера метрик.
+// Если адрес пустой, gRPC-сервер не запускается.
+func (b *MetrixServerBuilder) SetGRPCAddr(addr string) *MetrixServerBuilder {
+	b.grpcAddr = addr
+	return b
+}
+
 // SetPrivateKey устанавливает приватный ключ,
 // используемый в алгоритмах хеширования.
 func (b *MetrixServerBuilder) SetPrivateKey(key string) *MetrixServerBuilder {
@@ -73,6 +126,73 @@ func (b *MetrixServerBuilder) SetStoreInterval(interval time.Duration) *MetrixSe
 	return b
 }
 
+// SetRulesInterval устанавливает интервал между вычислениями
+// правил записи и оповещений.
+// Если интервал равен нулю, правила не вычисляются.
+func (b *MetrixServerBuilder) SetRulesInterval(interval time.Duration) *MetrixServerBuilder {
+	b.rulesInterval = interval
+	return b
+}
+
+// SetAlertingRules устанавливает правила оповещений.
+func (b *MetrixServerBuilder) SetAlertingRules(rules []alerting.Rule) *MetrixServerBuilder {
+	b.alertingRules = rules
+	return b
+}
+
+// SetRecordingRules устанавливает правила записи.
+func (b *MetrixServerBuilder) SetRecordingRules(rules []recording.Rule) *MetrixServerBuilder {
+	b.recordingRules = rules
+	return b
+}
+
+// SetAlertsNotifier устанавливает сущность для отправки
+// уведомлений об изменении состояний оповещений на вебхуки.
+func (b *MetrixServerBuilder) SetAlertsNotifier(notifier *alerting.Notifier) *MetrixServerBuilder {
+	b.alertsNotifier = notifier
+	return b
+}
+
+// SetWriteBehind включает буферизацию обновлений gauge и counter
+// перед записью в хранилище. Буфер вмещает до maxSize рядов
+// и записывается в хранилище при накоплении flushSize рядов,
+// но не реже, чем раз в flushInterval.
+// Если maxSize равен нулю, обновления записываются в хранилище сразу.
+//
+// При включённой буферизации метрики не сохраняются после каждого
+// обновления, даже если интервал сохранения равен нулю.
+func (b *MetrixServerBuilder) SetWriteBehind(maxSize, flushSize int, flushInterval time.Duration) *MetrixServerBuilder {
+	b.writeBehindSize = maxSize
+	b.writeBehindFlushSize = flushSize
+	b.writeBehindInterval = flushInterval
+	return b
+}
+
+// SetMetricsTTL устанавливает время, по истечении которого
+// метрики, не получавшие обновлений, удаляются вместе с историей.
+// Если время равно нулю, метрики не удаляются.
+func (b *MetrixServerBuilder) SetMetricsTTL(ttl time.Duration) *MetrixServerBuilder {
+	b.metricsTTL = ttl
+	return b
+}
+
+// SetHistoryRetention устанавливает время хранения истории значений
+// метрик, по истечении которого значения периодически удаляются.
+// Должно совпадать со временем хранения, переданным хранилищу.
+// Если время равно нулю, история не хранится.
+func (b *MetrixServerBuilder) SetHistoryRetention(retention time.Duration) *MetrixServerBuilder {
+	b.historyRetention = retention
+	return b
+}
+
+// SetSilenceRetention устанавливает время, по истечении которого
+// завершившиеся заглушения удаляются. Если время равно нулю,
+// завершившиеся заглушения хранятся бессрочно.
+func (b *MetrixServerBuilder) SetSilenceRetention(retention time.Duration) *MetrixServerBuilder {
+	b.silenceRetention = retention
+	return b
+}
+
 // EnabledProfiling активирует профилирование.
 func (b *MetrixServerBuilder) EnabledProfiling() *MetrixServerBuilder {
 	b.isProfilingEnabled = true
@@ -91,12 +211,49 @@ func (b *MetrixServerBuilder) Build() *MetrixServer {
 	router := gin.New()
 	applyMiddlewares(router, b.privateKey)
 
+	storage := b.storage
+
+	var writeBehind *writebehind.WriteBehindStorage
+	if b.writeBehindSize > 0 {
+		writeBehind = writebehind.NewWriteBehindStorage(writebehind.WriteBehindStorageOptions{
+			Storage:       b.storage,
+			MaxSize:       b.writeBehindSize,
+			FlushSize:     b.writeBehindFlushSize,
+			FlushInterval: b.writeBehindInterval,
+		})
+		storage = writeBehind
+	}
+
+	metricsRepo := metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{
+		Storage:     storage,
+		SyncMetrics: b.storeInterval == 0 && writeBehind == nil,
+		DBChecker:   b.dbChecker,
+	})
+
+	alertsManagerOpts := alerting.ManagerOptions{
+		Source:   metricsRepo,
+		Silences: metricsRepo,
+		Rules:    b.alertingRules,
+	}
+	// Типизированный nil в интерфейсе не равен nil,
+	// поэтому передаём уведомитель, только если он задан.
+	if b.alertsNotifier != nil {
+		alertsManagerOpts.Notifier = b.alertsNotifier
+	}
+
+	recordingManager := recording.NewManager(recording.ManagerOptions{
+		Source: metricsRepo,
+		Writer: metricsRepo,
+		Rules:  b.recordingRules,
+	})
+
 	internalServer := &internalMetrixServer{
-		router: router,
-		metricsRepo: metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{
-			Storage:     b.storage,
-			SyncMetrics: b.storeInterval == 0,
-			DBChecker:   b.dbChecker,
+		router:        router,
+		metricsRepo:   metricsRepo,
+		alertsManager: alerting.NewManager(alertsManagerOpts),
+		otlpConverter: otlp.NewConverter(otlp.ConverterOptions{
+			Storage:    metricsRepo,
+			StaleAfter: otlp.DefaultStaleAfter,
 		}),
 	}
 
@@ -104,9 +261,37 @@ func (b *MetrixServerBuilder) Build() *MetrixServer {
 	handlers.RegisterHandler(internalServer, http.MethodGet, "/value/:type/:id", handlers.GetMetricHandler)
 	handlers.RegisterHandler(internalServer, http.MethodPost, "/update/:type/:id/:value", handlers.UpdateMetricHandler)
 	handlers.RegisterHandler(internalServer, http.MethodGet, "/ping", handlers.PingHandler)
+	handlers.RegisterHandler(internalServer, http.MethodGet, "/metrics", handlers.PrometheusMetricsHandler)
+	handlers.RegisterHandler(internalServer, http.MethodPost, "/api/v1/write", handlers.RemoteWriteHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodGet, "/alerts", v2handlers.GetAlertsHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodGet, "/silences", v2handlers.GetSilencesHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/silences", v2handlers.CreateSilenceHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodDelete, "/silences/:id", v2handlers.ExpireSilenceHandler)
 	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/value/", v2handlers.GetMetricHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodDelete, "/value/", v2handlers.DeleteMetricHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodDelete, "/values/", v2handlers.DeleteMetricsHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/range/", v2handlers.GetMetricRangeHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/query/", v2handlers.QueryHandler)
 	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/update/", v2handlers.UpdateMetricHandler)
 	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/updates/", v2handlers.UpdateMetricsHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/ingest/graphite", v2handlers.GraphiteIngestHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/ingest/influx", v2handlers.InfluxIngestHandler)
+	handlers.RegisterV2Handler(internalServer, http.MethodPost, "/v1/metrics", v2handlers.OTLPMetricsHandler)
+
+	var statsdListener *StatsdListener
+	if b.statsdAddr != "" {
+		statsdListener = NewStatsdListener(b.statsdAddr, internalServer.metricsRepo)
+	}
+
+	var graphiteListener *GraphiteListener
+	if b.graphiteAddr != "" {
+		graphiteListener = NewGraphiteListener(b.graphiteAddr, internalServer.metricsRepo)
+	}
+
+	var grpcServer *GRPCServer
+	if b.grpcAddr != "" {
+		grpcServer = NewGRPCServer(b.grpcAddr, internalServer.metricsRepo, b.privateKey)
+	}
 
 	return &MetrixServer{
 		server: &http.Server{
@@ -114,7 +299,16 @@ func (b *MetrixServerBuilder) Build() *MetrixServer {
 			Handler: router,
 		},
 		internalServer:     internalServer,
-		worker:             NewMetrixServerWorker(b.storeInterval, b.storage),
+		worker:             NewMetrixServerWorker(b.storeInterval, storage),
+		writeBehind:        writeBehind,
+		rulesWorker:        NewRulesWorker(b.rulesInterval, recordingManager, internalServer.alertsManager),
+		ttlWorker:          NewTTLWorker(b.metricsTTL, metricsRepo),
+		historyWorker:      NewHistoryWorker(b.historyRetention, metricsRepo),
+		silencesWorker:     NewSilencesWorker(b.silenceRetention, metricsRepo),
+		alertsNotifier:     b.alertsNotifier,
+		statsdListener:     statsdListener,
+		graphiteListener:   graphiteListener,
+		grpcServer:         grpcServer,
 		isProfilingEnabled: b.isProfilingEnabled,
 	}
 }
@@ -124,6 +318,15 @@ type MetrixServer struct {
 	server             *http.Server
 	internalServer     *internalMetrixServer
 	worker             *MetrixServerWorker
+	writeBehind        *writebehind.WriteBehindStorage
+	rulesWorker        *RulesWorker
+	ttlWorker          *TTLWorker
+	historyWorker      *HistoryWorker
+	silencesWorker     *SilencesWorker
+	alertsNotifier     *alerting.Notifier
+	statsdListener     *StatsdListener
+	graphiteListener   *GraphiteListener
+	grpcServer         *GRPCServer
 	isProfilingEnabled bool
 }
 
@@ -135,6 +338,30 @@ func (s *MetrixServer) Run() chan error {
 
 	errChan := make(chan error, 1)
 
+	if s.statsdListener != nil {
+		err := s.statsdListener.Run()
+		if err != nil {
+			errChan <- err
+			return errChan
+		}
+	}
+
+	if s.graphiteListener != nil {
+		err := s.graphiteListener.Run()
+		if err != nil {
+			errChan <- err
+			return errChan
+		}
+	}
+
+	if s.grpcServer != nil {
+		err := s.grpcServer.Run()
+		if err != nil {
+			errChan <- err
+			return errChan
+		}
+	}
+
 	go func() {
 		err := s.server.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
@@ -145,7 +372,17 @@ func (s *MetrixServer) Run() chan error {
 		errChan <- nil
 	}()
 
+	if s.writeBehind != nil {
+		s.writeBehind.Run()
+	}
 	s.worker.Run()
+	s.rulesWorker.Run()
+	s.ttlWorker.Run()
+	s.historyWorker.Run()
+	s.silencesWorker.Run()
+	if s.alertsNotifier != nil {
+		s.alertsNotifier.Run()
+	}
 
 	return errChan
 }
@@ -153,13 +390,36 @@ func (s *MetrixServer) Run() chan error {
 // Stop останавливает сервер метрик.
 func (s *MetrixServer) Stop() error {
 	defer func() {
+		// Буфер записывается в хранилище после остановки слушателей
+		// и воркеров, чтобы не потерять принятые и вычисленные обновления.
+		s.rulesWorker.Stop()
+		s.ttlWorker.Stop()
+		s.historyWorker.Stop()
+		s.silencesWorker.Stop()
+		if s.writeBehind != nil {
+			s.writeBehind.Stop()
+		}
 		s.worker.Stop()
+		if s.alertsNotifier != nil {
+			s.alertsNotifier.Stop()
+		}
 	}()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	return s.server.Shutdown(ctx)
+	var statsdErr, graphiteErr, grpcErr error
+	if s.statsdListener != nil {
+		statsdErr = s.statsdListener.Stop()
+	}
+	if s.graphiteListener != nil {
+		graphiteErr = s.graphiteListener.Stop()
+	}
+	if s.grpcServer != nil {
+		grpcErr = s.grpcServer.Stop()
+	}
+
+	return errors.Join(s.server.Shutdown(ctx), statsdErr, graphiteErr, grpcErr)
 }
 
 func applyMiddlewares(router *gin.Engine, privateKey string) {
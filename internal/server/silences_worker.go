@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExpiredSilencesCleaner сущность, удаляющая завершившиеся заглушения.
+type ExpiredSilencesCleaner interface {
+	DeleteExpiredSilences(ctx context.Context, endedBefore time.Time) (int, error)
+}
+
+// NewSilencesWorker создаёт новый воркер для удаления заглушений,
+// завершившихся раньше, чем retention назад. При нулевом retention
+// заглушения не удаляются.
+//
+// Завершившиеся заглушения ищутся с интервалом retention, но не реже
+// раза в минуту, поэтому заглушение удаляется не позднее, чем через
+// retention + 1 минута после завершения.
+func NewSilencesWorker(retention time.Duration, cleaner ExpiredSilencesCleaner) *SilencesWorker {
+	worker := &SilencesWorker{
+		retention: retention,
+		cleaner:   cleaner,
+	}
+	worker.PeriodicWorker = NewPeriodicWorker("silences-worker", cleanupInterval(retention), worker.deleteExpiredSilences)
+
+	return worker
+}
+
+// SilencesWorker структура, описывающая воркер
+// для периодического удаления завершившихся заглушений.
+type SilencesWorker struct {
+	*PeriodicWorker
+	cleaner   ExpiredSilencesCleaner
+	retention time.Duration
+}
+
+// deleteExpiredSilences удаляет заглушения, завершившиеся
+// раньше, чем retention до момента now.
+func (worker *SilencesWorker) deleteExpiredSilences(ctx context.Context, now time.Time) {
+	deleted, err := worker.cleaner.DeleteExpiredSilences(ctx, now.Add(-worker.retention))
+	if err != nil {
+		worker.log(fmt.Sprintf("failed to delete expired silences: %v", err))
+	}
+
+	if deleted > 0 {
+		worker.log(fmt.Sprintf("deleted %d expired silences", deleted))
+	}
+}
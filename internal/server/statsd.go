@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/models"
+	"github.com/xantinium/metrix/internal/protocols/statsd"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+)
+
+// statsdMaxPacketSize максимальный размер UDP-пакета.
+const statsdMaxPacketSize = 65535
+
+// NewStatsdListener создаёт новый UDP-слушатель для приёма метрик
+// в формате StatsD.
+func NewStatsdListener(addr string, metricsRepo *metrics.MetricsRepository) *StatsdListener {
+	return &StatsdListener{
+		addr:        addr,
+		metricsRepo: metricsRepo,
+	}
+}
+
+// StatsdListener структура, описывающая UDP-слушатель
+// для приёма метрик в формате StatsD.
+type StatsdListener struct {
+	conn        net.PacketConn
+	metricsRepo *metrics.MetricsRepository
+	addr        string
+	wg          sync.WaitGroup
+}
+
+// Run начинает прослушивание адреса.
+func (listener *StatsdListener) Run() error {
+	var err error
+
+	listener.conn, err = net.ListenPacket("udp", listener.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen statsd address: %v", err)
+	}
+
+	listener.wg.Add(1)
+	go func() {
+		defer listener.wg.Done()
+
+		buf := make([]byte, statsdMaxPacketSize)
+
+		for {
+			n, _, readErr := listener.conn.ReadFrom(buf)
+			if readErr != nil {
+				if errors.Is(readErr, net.ErrClosed) {
+					listener.log("stopping...")
+					return
+				}
+
+				listener.log(fmt.Sprintf("failed to read packet: %v", readErr))
+				continue
+			}
+
+			listener.handlePacket(buf[:n])
+		}
+	}()
+
+	return nil
+}
+
+// Addr возвращает адрес, на котором запущен слушатель.
+func (listener *StatsdListener) Addr() net.Addr {
+	return listener.conn.LocalAddr()
+}
+
+// Stop прекращает прослушивание адреса.
+func (listener *StatsdListener) Stop() error {
+	if listener.conn == nil {
+		return nil
+	}
+
+	err := listener.conn.Close()
+	listener.wg.Wait()
+
+	return err
+}
+
+// handlePacket разбирает пакет и сохраняет метрики одним батчем.
+//
+// Относительные изменения gauge-метрик применяются к последнему
+// значению из этого же пакета, а при его отсутствии - к значению
+// из хранилища. Пакеты обрабатываются последовательно, поэтому
+// изменения, полученные по StatsD, не теряются.
+func (listener *StatsdListener) handlePacket(packet []byte) {
+	ctx := context.Background()
+
+	lines, errs := statsd.Parse(packet)
+	for _, err := range errs {
+		listener.log(fmt.Sprintf("failed to parse line: %v", err))
+	}
+
+	gauges := make(map[string]float64)
+	batch := make([]models.MetricInfo, 0, len(lines))
+
+	for _, line := range lines {
+		switch line.Type {
+		case models.Counter:
+			batch = append(batch, models.NewCounterMetric(line.Name, line.CounterValue()))
+		case models.Gauge:
+			value := line.Value
+
+			if line.IsDelta {
+				current, exists := gauges[line.Name]
+				if !exists {
+					var err error
+
+					current, err = listener.metricsRepo.GetGaugeMetric(ctx, line.Name, nil)
+					if err != nil && !errors.Is(err, models.ErrNotFound) {
+						listener.log(fmt.Sprintf("failed to get gauge metric %q: %v", line.Name, err))
+						continue
+					}
+				}
+
+				value += current
+			}
+
+			gauges[line.Name] = value
+			batch = append(batch, models.NewGaugeMetric(line.Name, value))
+		}
+	}
+
+	err := listener.metricsRepo.UpdateMetrics(ctx, batch)
+	if err != nil {
+		listener.log(fmt.Sprintf("failed to update metrics: %v", err))
+	}
+}
+
+// log логирует события слушателя.
+func (listener *StatsdListener) log(msg string) {
+	logger.Info(
+		msg,
+		logger.Field{
+			Name:  "entity",
+			Value: "statsd-listener",
+		},
+	)
+}
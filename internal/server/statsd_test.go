@@ -0,0 +1,54 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xantinium/metrix/internal/infrastructure/memstorage"
+	"github.com/xantinium/metrix/internal/logger"
+	"github.com/xantinium/metrix/internal/repository/metrics"
+	"github.com/xantinium/metrix/internal/server"
+)
+
+func TestStatsdListener(t *testing.T) {
+	logger.Init(true)
+	defer logger.Destroy()
+
+	ctx := context.Background()
+
+	storage, err := memstorage.NewMemStorage(memstorage.MemStorageOptions{Path: "metrix.db"})
+	require.NoError(t, err)
+
+	repo := metrics.NewMetricsRepository(metrics.MetricsRepositoryOptions{Storage: storage})
+
+	_, err = repo.UpdateGaugeMetric(ctx, "connections", nil, 10)
+	require.NoError(t, err)
+
+	listener := server.NewStatsdListener("127.0.0.1:0", repo)
+	require.NoError(t, listener.Run())
+	defer listener.Stop()
+
+	conn, err := net.Dial("udp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("requests:1|c\nrequests:1|c|@0.5\nHeapAlloc:12.5|g\nconnections:+3|g\nconnections:-1|g\ninvalid"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		requests, getErr := repo.GetCounterMetric(ctx, "requests", nil)
+		return getErr == nil && requests == 3
+	}, time.Second, 10*time.Millisecond)
+
+	heapAlloc, err := repo.GetGaugeMetric(ctx, "HeapAlloc", nil)
+	require.NoError(t, err)
+	require.Equal(t, 12.5, heapAlloc)
+
+	connections, err := repo.GetGaugeMetric(ctx, "connections", nil)
+	require.NoError(t, err)
+	require.Equal(t, float64(12), connections)
+}
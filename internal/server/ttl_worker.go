@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StaleMetricsCleaner сущность, удаляющая устаревшие метрики.
+type StaleMetricsCleaner interface {
+	DeleteStaleMetrics(ctx context.Context, updatedBefore time.Time) (int, error)
+}
+
+// NewTTLWorker создаёт новый воркер для удаления метрик,
+// не обновлявшихся дольше ttl. При нулевом ttl метрики не удаляются.
+//
+// Устаревшие метрики ищутся с интервалом ttl, но не реже раза в минуту,
+// поэтому метрика удаляется не позднее, чем через ttl + 1 минута
+// после последнего обновления.
+func NewTTLWorker(ttl time.Duration, cleaner StaleMetricsCleaner) *TTLWorker {
+	worker := &TTLWorker{
+		ttl:     ttl,
+		cleaner: cleaner,
+	}
+	worker.PeriodicWorker = NewPeriodicWorker("ttl-worker", cleanupInterval(ttl), worker.deleteStaleMetrics)
+
+	return worker
+}
+
+// TTLWorker структура, описывающая воркер
+// для периодического удаления устаревших метрик.
+type TTLWorker struct {
+	*PeriodicWorker
+	cleaner StaleMetricsCleaner
+	ttl     time.Duration
+}
+
+// deleteStaleMetrics удаляет метрики, не обновлявшиеся дольше ttl к моменту now.
+func (worker *TTLWorker) deleteStaleMetrics(ctx context.Context, now time.Time) {
+	deleted, err := worker.cleaner.DeleteStaleMetrics(ctx, now.Add(-worker.ttl))
+	if err != nil {
+		worker.log(fmt.Sprintf("failed to delete stale metrics: %v", err))
+	}
+
+	if deleted > 0 {
+		worker.log(fmt.Sprintf("deleted %d stale metrics", deleted))
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic записывает файл атомарно: содержимое записывается
+// во временный файл path.tmp, сбрасывается на диск и переименовывается
+// в path. При аварийном завершении в path остаётся либо прежнее,
+// либо новое содержимое целиком.
+func WriteFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+
+	err := WriteFileSync(tmpPath, data)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return err
+	}
+
+	return SyncDir(filepath.Dir(path))
+}
+
+// WriteFileSync записывает файл и сбрасывает его на диск.
+func WriteFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// SyncDir сбрасывает на диск содержимое директории,
+// чтобы переименования файлов пережили аварийное завершение.
+func SyncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
@@ -21,14 +21,17 @@ type Retrier struct {
 }
 
 // Exec запускает вызов функции execFunc с последующими ретраями.
+// Ретраи прекращаются, как только функция вернула false.
 func (r *Retrier) Exec(execFunc execFuncT) {
-	if execFunc() {
-		for _, delay := range r.pattern {
-			time.Sleep(delay)
+	if !execFunc() {
+		return
+	}
+
+	for _, delay := range r.pattern {
+		time.Sleep(delay)
 
-			if execFunc() {
-				return
-			}
+		if !execFunc() {
+			return
 		}
 	}
 }
@@ -2,7 +2,9 @@ package tools_test
 
 import (
 	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -171,3 +173,43 @@ func TestCompression(t *testing.T) {
 		require.Equal(t, tt.data, got)
 	}
 }
+
+func TestRetrier_Exec(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+	}{
+		{name: "успех с первой попытки", failures: 0, wantCalls: 1},
+		{name: "успех после ретрая", failures: 1, wantCalls: 2},
+		{name: "все попытки неудачны", failures: 10, wantCalls: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retrier := tools.NewRetrier(time.Millisecond, time.Millisecond, time.Millisecond)
+
+			var calls int
+			retrier.Exec(func() bool {
+				calls++
+				return calls <= tt.failures
+			})
+
+			require.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := t.TempDir() + "/outbox.json"
+
+	require.NoError(t, tools.WriteFileAtomic(path, []byte("first")))
+	require.NoError(t, tools.WriteFileAtomic(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+
+	// Временный файл не остаётся после переименования.
+	_, err = os.Stat(path + ".tmp")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}